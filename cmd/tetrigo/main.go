@@ -1,6 +1,8 @@
 package main
 
 import (
+	"path/filepath"
+
 	"github.com/adrg/xdg"
 	"github.com/alecthomas/kong"
 )
@@ -10,12 +12,20 @@ type CLI struct {
 
 	Menu        MenuCmd        `cmd:"" help:"Start in the menu" default:"1"`
 	Play        PlayCmd        `cmd:"" help:"Play a specific game mode"`
+	Versus      VersusCmd      `cmd:"" help:"Play a local two player versus game"`
+	Serve       ServeCmd       `cmd:"" help:"Host a server for online versus games"`
+	Join        JoinCmd        `cmd:"" help:"Join an online versus game on a server"`
 	Leaderboard LeaderboardCmd `cmd:"" help:"Start on the leaderboard"`
+	Replay      ReplayCmd      `cmd:"" help:"Watch the replay of a game"`
+	Verify      VerifyCmd      `cmd:"" help:"Check a leaderboard score matches its replay by re-simulating it"`
+	Resume      ResumeCmd      `cmd:"" help:"Resume the saved single player game"`
 }
 
 type GlobalVars struct {
-	Config string `help:"Path to config file. Empty value will use XDG data directory." default:""`
-	DB     string `help:"Path to database file. Empty value will use XDG data directory." default:""`
+	Config  string `help:"Path to config file. Empty value will use XDG data directory." default:""`
+	DB      string `help:"Path to database file. Empty value will use XDG data directory." default:""`
+	Replays string `help:"Path to replays directory. Empty value will use XDG data directory." default:""`
+	Save    string `help:"Path to saved game file. Empty value will use XDG data directory." default:""`
 }
 
 func main() {
@@ -50,5 +60,15 @@ func handleDefaultGlobals(g *GlobalVars) error {
 			return err
 		}
 	}
+	if g.Replays == "" {
+		g.Replays = filepath.Join(xdg.DataHome, "tetrigo", "replays")
+	}
+	if g.Save == "" {
+		var err error
+		g.Save, err = xdg.DataFile("./tetrigo/save.json")
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -2,12 +2,25 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/Broderick-Westrope/tetrigo/internal/config"
 	"github.com/Broderick-Westrope/tetrigo/internal/data"
+	"github.com/Broderick-Westrope/tetrigo/internal/netplay"
+	"github.com/Broderick-Westrope/tetrigo/internal/replay"
+	"github.com/Broderick-Westrope/tetrigo/internal/savegame"
 	"github.com/Broderick-Westrope/tetrigo/internal/tui"
 	"github.com/Broderick-Westrope/tetrigo/internal/tui/starter"
 )
@@ -22,29 +35,282 @@ type PlayCmd struct {
 	GameMode string `arg:"" help:"Game mode to play" default:"marathon"`
 	Level    int    `help:"Level to start at" short:"l" default:"1"`
 	Name     string `help:"Name of the player" short:"n" default:"Anonymous"`
+	Bot      bool   `help:"Watch a bot play the game"`
+	Width    int    `help:"Number of columns in the matrix (4-20). Overrides the config"`
+	Height   int    `help:"Number of visible rows in the matrix (4-40). Overrides the config"`
+	Lines    int    `help:"Number of lines to clear in Sprint, eg. 20, 40 or 100. Defaults to 40"`
+	Minutes  int    `help:"Number of minutes to play Ultra for, eg. 1, 2, 3 or 5. Defaults to 2"`
+	Garbage  int    `help:"Number of garbage lines to clear in Dig, eg. 5, 10 or 18. Defaults to 10"`
+	Rising   bool   `help:"Make garbage keep rising in Dig"`
 }
 
 func (c *PlayCmd) Run(globals *GlobalVars) error {
-	singlePlayerModes := map[string]tui.Mode{
-		"marathon": tui.ModeMarathon,
-		"sprint":   tui.ModeSprint,
-		"ultra":    tui.ModeUltra,
+	mode, err := parseSinglePlayerMode(c.GameMode)
+	if err != nil {
+		return err
 	}
 
-	mode, ok := singlePlayerModes[c.GameMode]
-	if !ok {
-		return fmt.Errorf("invalid game mode: %s", c.GameMode)
+	var opts []func(*tui.SingleInput)
+	if c.Bot {
+		opts = append(opts, tui.WithBot())
+	}
+	if c.Width != 0 || c.Height != 0 {
+		opts = append(opts, tui.WithMatrixSize(c.Width, c.Height))
+	}
+	if c.Lines != 0 {
+		if mode != tui.ModeSprint {
+			return fmt.Errorf("--lines is only supported by %s", tui.ModeSprint)
+		}
+		if c.Lines < 0 {
+			return fmt.Errorf("invalid number of lines: %d", c.Lines)
+		}
+		opts = append(opts, tui.WithLineGoal(c.Lines))
+	}
+	if c.Minutes != 0 {
+		if mode != tui.ModeUltra {
+			return fmt.Errorf("--minutes is only supported by %s", tui.ModeUltra)
+		}
+		if c.Minutes < 0 {
+			return fmt.Errorf("invalid number of minutes: %d", c.Minutes)
+		}
+		opts = append(opts, tui.WithTimeLimit(time.Duration(c.Minutes)*time.Minute))
+	}
+	if c.Garbage != 0 || c.Rising {
+		if mode != tui.ModeDig {
+			return fmt.Errorf("--garbage and --rising are only supported by %s", tui.ModeDig)
+		}
+		if c.Garbage < 0 {
+			return fmt.Errorf("invalid number of garbage lines: %d", c.Garbage)
+		}
+		opts = append(opts, tui.WithGarbageLines(c.Garbage))
+		if c.Rising {
+			opts = append(opts, tui.WithRisingGarbage())
+		}
 	}
 
-	return launchStarter(context.Background(), globals, mode, tui.NewSingleInput(mode, c.Level, c.Name))
+	return launchStarter(context.Background(), globals, mode, tui.NewSingleInput(mode, c.Level, c.Name, opts...))
+}
+
+type VersusCmd struct {
+	Level int `help:"Level to start at" short:"l" default:"1"`
+}
+
+func (c *VersusCmd) Run(globals *GlobalVars) error {
+	return launchStarter(context.Background(), globals, tui.ModeVersus, tui.NewVersusInput(c.Level))
+}
+
+type ServeCmd struct {
+	Addr string `help:"Address to listen on" default:":7777"`
+}
+
+func (c *ServeCmd) Run(_ *GlobalVars) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var lc net.ListenConfig
+	ln, err := lc.Listen(ctx, "tcp", c.Addr)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", c.Addr, err)
+	}
+
+	fmt.Printf("Serving on %s. Press Ctrl+C to stop.\n", ln.Addr())
+	if err = netplay.NewServer().Serve(ctx, ln); err != nil {
+		return fmt.Errorf("serving: %w", err)
+	}
+	return nil
+}
+
+type JoinCmd struct {
+	Addr  string `arg:"" help:"Address of the server, eg. localhost:7777"`
+	Level int    `help:"Level to start at" short:"l" default:"1"`
+	Name  string `help:"Name of the player" short:"n" default:"Anonymous"`
+}
+
+func (c *JoinCmd) Run(globals *GlobalVars) error {
+	return launchStarter(context.Background(), globals, tui.ModeOnline, tui.NewOnlineInput(c.Addr, c.Name, c.Level))
 }
 
 type LeaderboardCmd struct {
+	Show   LeaderboardShowCmd   `cmd:"" help:"Start on the leaderboard" default:"withargs"`
+	Export LeaderboardExportCmd `cmd:"" help:"Export the leaderboard to a file"`
+	Import LeaderboardImportCmd `cmd:"" help:"Import scores exported from another leaderboard"`
+}
+
+type LeaderboardShowCmd struct {
 	GameMode string `arg:"" help:"Game mode to display" default:"marathon"`
+	Name     string `help:"Name of the player whose personal bests can be shown"`
+}
+
+func (c *LeaderboardShowCmd) Run(globals *GlobalVars) error {
+	return launchStarter(context.Background(), globals, tui.ModeLeaderboard,
+		tui.NewLeaderboardInput(c.GameMode, tui.WithUsername(c.Name)))
+}
+
+type LeaderboardExportCmd struct {
+	Format string `help:"Format of the exported scores" enum:"csv,json" default:"json"`
+	Output string `help:"Path of the file to write. Empty value will write to stdout." short:"o" default:""`
+}
+
+func (c *LeaderboardExportCmd) Run(globals *GlobalVars) (err error) {
+	ctx := context.Background()
+	db, err := data.NewDB(ctx, globals.DB)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	scores, err := data.NewLeaderboardRepository(db).Scores(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching scores: %w", err)
+	}
+
+	var w io.Writer = os.Stdout
+	if c.Output != "" {
+		f, err := os.Create(c.Output)
+		if err != nil {
+			return fmt.Errorf("creating output file: %w", err)
+		}
+		defer func() {
+			err = errors.Join(err, f.Close())
+		}()
+		w = f
+	}
+
+	switch c.Format {
+	case "csv":
+		err = data.WriteScoresCSV(w, scores)
+	default:
+		err = data.WriteScoresJSON(w, scores)
+	}
+	if err != nil {
+		return fmt.Errorf("writing scores: %w", err)
+	}
+	return nil
+}
+
+type LeaderboardImportCmd struct {
+	File   string `arg:"" help:"Path to the exported scores" type:"existingfile"`
+	Format string `help:"Format of the file. Empty value will use the file extension." enum:",csv,json" default:""`
+	DryRun bool   `help:"Report what would be imported without changing the leaderboard"`
+}
+
+func (c *LeaderboardImportCmd) Run(globals *GlobalVars) error {
+	format := c.Format
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(c.File)), ".")
+	}
+
+	f, err := os.Open(c.File)
+	if err != nil {
+		return fmt.Errorf("opening file: %w", err)
+	}
+	defer f.Close()
+
+	var scores []data.Score
+	switch format {
+	case "csv":
+		scores, err = data.ReadScoresCSV(f)
+	case "json":
+		scores, err = data.ReadScoresJSON(f)
+	default:
+		return fmt.Errorf("unknown format of %s, use --format to set it", c.File)
+	}
+	if err != nil {
+		return fmt.Errorf("reading scores: %w", err)
+	}
+
+	// Game modes are stored with the same name as the game uses, regardless of how they are written in the file.
+	for i := range scores {
+		in, err := tui.ParseGameModeName(scores[i].GameMode)
+		if err != nil {
+			return fmt.Errorf("invalid score %d: %w", i+1, err)
+		}
+		scores[i].GameMode = in.GameModeName()
+	}
+
+	ctx := context.Background()
+	db, err := data.NewDB(ctx, globals.DB)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	res, err := data.NewLeaderboardRepository(db).Import(ctx, scores, c.DryRun)
+	if err != nil {
+		return fmt.Errorf("importing scores: %w", err)
+	}
+
+	verb := "Imported"
+	if c.DryRun {
+		verb = "Would import"
+	}
+	fmt.Printf("%s %d scores, skipping %d duplicates\n", verb, res.Imported, res.Duplicates)
+	return nil
 }
 
-func (c *LeaderboardCmd) Run(globals *GlobalVars) error {
-	return launchStarter(context.Background(), globals, tui.ModeLeaderboard, tui.NewLeaderboardInput(c.GameMode))
+type ReplayCmd struct {
+	File string `arg:"" help:"Path to the replay file" type:"existingfile"`
+}
+
+func (c *ReplayCmd) Run(globals *GlobalVars) error {
+	r, err := replay.Load(c.File)
+	if err != nil {
+		return fmt.Errorf("loading replay: %w", err)
+	}
+
+	return launchStarter(context.Background(), globals, tui.ModeReplay, tui.NewReplayInput(r))
+}
+
+type VerifyCmd struct {
+	ScoreID int `arg:"" help:"ID of the score, as shown by \"leaderboard export --format=json\""`
+}
+
+func (c *VerifyCmd) Run(globals *GlobalVars) error {
+	ctx := context.Background()
+	db, err := data.NewDB(ctx, globals.DB)
+	if err != nil {
+		return fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	score, err := data.NewLeaderboardRepository(db).Get(ctx, c.ScoreID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("no score with ID %d", c.ScoreID)
+	} else if err != nil {
+		return fmt.Errorf("fetching score: %w", err)
+	}
+
+	if err = score.Verify(); err != nil {
+		return fmt.Errorf("score %d does not match its replay: %w", c.ScoreID, err)
+	}
+	fmt.Printf("Score %d matches its replay: %s scored %d with %d lines at level %d in %s\n",
+		c.ScoreID, score.Name, score.Score, score.Lines, score.Level, score.GameMode)
+	return nil
+}
+
+type ResumeCmd struct{}
+
+func (c *ResumeCmd) Run(globals *GlobalVars) error {
+	if !savegame.Exists(globals.Save) {
+		return fmt.Errorf("no saved game found at %s", globals.Save)
+	}
+	return launchStarter(context.Background(), globals, tui.ModeResume, tui.NewResumeInput())
+}
+
+// parseSinglePlayerMode returns the single player game mode with the given name, ignoring case.
+func parseSinglePlayerMode(gameMode string) (tui.Mode, error) {
+	mode, err := tui.ParseMode(gameMode)
+	if err != nil {
+		return 0, fmt.Errorf("invalid game mode: %s", gameMode)
+	}
+	switch mode {
+	case tui.ModeMarathon, tui.ModeSprint, tui.ModeUltra, tui.ModeDig, tui.ModeMaster:
+		return mode, nil
+	case tui.ModeMenu, tui.ModeLeaderboard, tui.ModeReplay, tui.ModeVersus, tui.ModeOnline, tui.ModeResume:
+		fallthrough
+	default:
+		return 0, fmt.Errorf("invalid game mode: %s", gameMode)
+	}
 }
 
 func launchStarter(ctx context.Context, globals *GlobalVars, starterMode tui.Mode, switchIn tui.SwitchModeInput) error {
@@ -59,7 +325,11 @@ func launchStarter(ctx context.Context, globals *GlobalVars, starterMode tui.Mod
 	}
 
 	model, err := starter.NewModel(ctx,
-		starter.NewInput(starterMode, switchIn, db, cfg))
+		starter.NewInput(starterMode, switchIn, db, cfg,
+			starter.WithReplayDir(globals.Replays),
+			starter.WithSavePath(globals.Save),
+			starter.WithTerminal(os.Stdout),
+		))
 	if err != nil {
 		return fmt.Errorf("creating starter model: %w", err)
 	}
@@ -4,8 +4,13 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
+
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris"
 )
 
 type Config struct {
@@ -15,15 +20,37 @@ type Config struct {
 	// Whether a ghost piece will be displayed beneath the current tetrimino.
 	GhostEnabled bool `toml:"ghost_enabled"`
 
-	// TODO: What mode to use when locking down a tetrimino.
+	// What mode to use when locking down a tetrimino: Extended, Infinite or Classic.
 	LockDownMode string `toml:"lock_down_mode"`
 
+	// How the order of tetriminos is generated: 7-bag, 14-bag, random, classic, tgm1, tgm2 or sequence:<values>.
+	Randomizer string `toml:"randomizer"`
+
+	// The size of the matrix for each game mode (marathon, sprint, ultra, dig, master or versus).
+	// Unset modes use a width of 10 and height of 20.
+	Matrix map[string]MatrixSize `toml:"matrix"`
+
 	// The maximum level to reach before the game ends or the level stops increasing.
 	MaxLevel int `toml:"max_level"`
 
 	// Whether the game ends when the max level is reached.
 	EndOnMaxLevel bool `toml:"end_on_max_level"`
 
+	// Delayed Auto Shift: how long in milliseconds a horizontal movement key is held before it repeats.
+	DAS int `toml:"das_ms"`
+
+	// Auto Repeat Rate: the time in milliseconds between repeated horizontal movements. 0 moves instantly to the wall.
+	ARR int `toml:"arr_ms"`
+
+	// Soft Drop Factor: how many times faster a tetrimino falls whilst soft dropping.
+	SoftDropFactor int `toml:"sdf"`
+
+	// Whether the soft drop key toggles soft drop on and off, or soft drops whilst held: toggle or hold.
+	SoftDropMode string `toml:"soft_drop_mode"`
+
+	// The lock delay in milliseconds for each section of 100 levels in master mode. Unset sections use the default.
+	MasterLockDelays []int `toml:"master_lock_delay_ms"`
+
 	// The styling for the game in all modes
 	Theme *Theme `toml:"theme"`
 
@@ -31,13 +58,43 @@ type Config struct {
 	Keys *Keys `toml:"keys"`
 }
 
+// MatrixSize is the number of columns and visible rows of the matrix.
+type MatrixSize struct {
+	Width  int `toml:"width"`
+	Height int `toml:"height"`
+}
+
+const (
+	// SoftDropModeToggle makes the soft drop key toggle soft drop on and off.
+	SoftDropModeToggle = "toggle"
+	// SoftDropModeHold makes the game soft drop whilst the soft drop key is held.
+	SoftDropModeHold = "hold"
+)
+
+const (
+	// maxAutoShiftMillis is the maximum DAS and ARR in milliseconds.
+	maxAutoShiftMillis = 1000
+	// maxSoftDropFactor is the maximum Soft Drop Factor.
+	maxSoftDropFactor = 100
+	// maxLockDelayMillis is the maximum lock delay of a section of master mode in milliseconds.
+	maxLockDelayMillis = 1000
+)
+
+// matrixSizeModes are the game modes for which a MatrixSize can be configured.
+var matrixSizeModes = []string{"marathon", "sprint", "ultra", "dig", "master", "versus"}
+
 func GetConfig(path string) (*Config, error) {
 	c := Config{
 		NextQueueLength: 5,
 		GhostEnabled:    true,
 		LockDownMode:    "Extended",
+		Randomizer:      tetris.RandomizerBag7,
 		MaxLevel:        15,
 		EndOnMaxLevel:   false,
+		DAS:             167,
+		ARR:             33,
+		SoftDropFactor:  tetris.DefaultSoftDropFactor,
+		SoftDropMode:    SoftDropModeToggle,
 
 		Theme: DefaultTheme(),
 		Keys:  DefaultKeys(),
@@ -63,8 +120,62 @@ func (c *Config) validate() error {
 	if c.NextQueueLength < 0 || c.NextQueueLength > 7 {
 		return fmt.Errorf("NextQueueLength '%d' must be between 0 and 7", c.NextQueueLength)
 	}
-	if c.LockDownMode != "Extended" && c.LockDownMode != "Infinite" && c.LockDownMode != "Classic" {
+	if _, err := tetris.ParseLockDownMode(c.LockDownMode); err != nil {
 		return fmt.Errorf("LockDownMode '%s' must be one of 'Extended', 'Infinite', or 'Classic'", c.LockDownMode)
 	}
+	if _, err := tetris.NewRandomizer(c.Randomizer); err != nil {
+		return fmt.Errorf("Randomizer '%s' must be one of '7-bag', '14-bag', 'random', 'classic', 'tgm1', 'tgm2', "+
+			"or 'sequence:<values>'", c.Randomizer)
+	}
+	if c.DAS < 0 || c.DAS > maxAutoShiftMillis {
+		return fmt.Errorf("DAS '%d' must be between 0 and %d", c.DAS, maxAutoShiftMillis)
+	}
+	if c.ARR < 0 || c.ARR > maxAutoShiftMillis {
+		return fmt.Errorf("ARR '%d' must be between 0 and %d", c.ARR, maxAutoShiftMillis)
+	}
+	if c.SoftDropFactor < 1 || c.SoftDropFactor > maxSoftDropFactor {
+		return fmt.Errorf("SoftDropFactor '%d' must be between 1 and %d", c.SoftDropFactor, maxSoftDropFactor)
+	}
+	if c.SoftDropMode != SoftDropModeToggle && c.SoftDropMode != SoftDropModeHold {
+		return fmt.Errorf("SoftDropMode '%s' must be one of '%s' or '%s'",
+			c.SoftDropMode, SoftDropModeToggle, SoftDropModeHold)
+	}
+	if len(c.MasterLockDelays) > len(tetris.DefaultMasterSections) {
+		return fmt.Errorf("MasterLockDelays must have at most %d sections", len(tetris.DefaultMasterSections))
+	}
+	for _, delay := range c.MasterLockDelays {
+		if delay < 1 || delay > maxLockDelayMillis {
+			return fmt.Errorf("MasterLockDelays '%d' must be between 1 and %d", delay, maxLockDelayMillis)
+		}
+	}
+	for mode, size := range c.Matrix {
+		if !slices.Contains(matrixSizeModes, mode) {
+			return fmt.Errorf("Matrix mode '%s' must be one of 'marathon', 'sprint', 'ultra', 'dig', 'master', "+
+				"or 'versus'", mode)
+		}
+		if size.Width != 0 && (size.Width < tetris.MinMatrixWidth || size.Width > tetris.MaxMatrixWidth) {
+			return fmt.Errorf("Matrix width '%d' for mode '%s' must be between %d and %d",
+				size.Width, mode, tetris.MinMatrixWidth, tetris.MaxMatrixWidth)
+		}
+		if size.Height != 0 && (size.Height < tetris.MinMatrixHeight || size.Height > tetris.MaxMatrixHeight) {
+			return fmt.Errorf("Matrix height '%d' for mode '%s' must be between %d and %d",
+				size.Height, mode, tetris.MinMatrixHeight, tetris.MaxMatrixHeight)
+		}
+	}
 	return nil
 }
+
+// GetMatrixSize returns the configured MatrixSize for the given game mode (eg. "marathon").
+// A width or height of 0 means the default should be used.
+func (c *Config) GetMatrixSize(mode string) MatrixSize {
+	return c.Matrix[strings.ToLower(mode)]
+}
+
+// GetMasterSections returns the timing of each section of master mode, using the configured lock delays.
+func (c *Config) GetMasterSections() []tetris.MasterSection {
+	sections := slices.Clone(tetris.DefaultMasterSections)
+	for i, delay := range c.MasterLockDelays {
+		sections[i].LockDelay = time.Duration(delay) * time.Millisecond
+	}
+	return sections
+}
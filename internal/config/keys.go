@@ -11,6 +11,25 @@ type Keys struct {
 	Right                  []string `toml:"right"`
 	RotateCounterClockwise []string `toml:"rotate_counter_clockwise"`
 	RotateClockwise        []string `toml:"rotate_clockwise"`
+
+	// The keybindings for each player in a local versus game. These replace the game controls above.
+	Versus *VersusKeys `toml:"versus"`
+}
+
+type VersusKeys struct {
+	PlayerOne *PlayerKeys `toml:"player_one"`
+	PlayerTwo *PlayerKeys `toml:"player_two"`
+}
+
+// PlayerKeys are the game controls for a single player when the keyboard is shared.
+type PlayerKeys struct {
+	Up                     []string `toml:"up"`
+	Down                   []string `toml:"down"`
+	Left                   []string `toml:"left"`
+	Right                  []string `toml:"right"`
+	RotateCounterClockwise []string `toml:"rotate_counter_clockwise"`
+	RotateClockwise        []string `toml:"rotate_clockwise"`
+	Hold                   []string `toml:"hold"`
 }
 
 func DefaultKeys() *Keys {
@@ -25,5 +44,25 @@ func DefaultKeys() *Keys {
 		Right:                  []string{"d"},
 		RotateCounterClockwise: []string{"q"},
 		RotateClockwise:        []string{"e"},
+		Versus: &VersusKeys{
+			PlayerOne: &PlayerKeys{
+				Up:                     []string{"w"},
+				Down:                   []string{"s"},
+				Left:                   []string{"a"},
+				Right:                  []string{"d"},
+				RotateCounterClockwise: []string{"q"},
+				RotateClockwise:        []string{"e"},
+				Hold:                   []string{"c"},
+			},
+			PlayerTwo: &PlayerKeys{
+				Up:                     []string{"up"},
+				Down:                   []string{"down"},
+				Left:                   []string{"left"},
+				Right:                  []string{"right"},
+				RotateCounterClockwise: []string{","},
+				RotateClockwise:        []string{"."},
+				Hold:                   []string{"/"},
+			},
+		},
 	}
 }
@@ -3,18 +3,108 @@ package data
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/replay"
 )
 
+// Score is an entry on the leaderboard. The ID is exported so the score can be referred to (eg. to check it), but it
+// is ignored when importing since, like the Rank, it is specific to a database.
 type Score struct {
-	ID       int
-	Rank     int
-	GameMode string
-	Name     string
-	Time     time.Duration
-	Score    int
-	Lines    int
-	Level    int
+	ID       int           `json:"id,omitempty"`
+	Rank     int           `json:"-"`
+	GameMode string        `json:"game_mode"`
+	Name     string        `json:"name"`
+	Time     time.Duration `json:"time"`
+	Score    int           `json:"score"`
+	Lines    int           `json:"lines"`
+	Level    int           `json:"level"`
+	// Completed is true if the game reached the goal of its mode (eg. 40 lines in Sprint) rather than topping out.
+	Completed bool `json:"completed"`
+	// StartLevel is the level the game started at, or 0 if it is unknown.
+	StartLevel int `json:"start_level"`
+	// CreatedAt is when the score was saved, or the zero time if it is unknown. Save uses the current time if unset.
+	CreatedAt time.Time `json:"created_at"`
+
+	Pieces     int     `json:"pieces"` // The number of Tetriminos locked down.
+	PPS        float64 `json:"pps"`    // The number of pieces per second.
+	MaxCombo   int     `json:"max_combo"`
+	TSpins     int     `json:"t_spins"`
+	MiniTSpins int     `json:"mini_t_spins"`
+	Tetrises   int     `json:"tetrises"`
+	// Splits are the times at which a Sprint reached every 10 lines and its line goal, or nil if unknown.
+	Splits []time.Duration `json:"splits,omitempty"`
+
+	// Replay is the recording of the game, which the score can be checked against. It is nil if it wasn't recorded.
+	Replay *replay.Replay `json:"replay,omitempty"`
+	// ReplayCheck is whether the score matched its Replay when it was saved. It is set when the score is read from
+	// the leaderboard, and ignored when saving since the score is checked again.
+	ReplayCheck ReplayCheck `json:"-"`
+}
+
+// Validate returns an error if the score could not have been achieved, such as when it has a negative value.
+func (s *Score) Validate() error {
+	switch {
+	case s.GameMode == "":
+		return errors.New("missing game mode")
+	case s.Name == "":
+		return errors.New("missing name")
+	case s.Time < 0:
+		return fmt.Errorf("negative time %v", s.Time)
+	case s.Score < 0, s.Lines < 0, s.Level < 0, s.StartLevel < 0:
+		return errors.New("negative score, lines or level")
+	case s.Pieces < 0, s.PPS < 0, s.MaxCombo < 0, s.TSpins < 0, s.MiniTSpins < 0, s.Tetrises < 0:
+		return errors.New("negative stats")
+	case slices.ContainsFunc(s.Splits, func(d time.Duration) bool { return d < 0 }):
+		return errors.New("negative split")
+	}
+	return nil
+}
+
+// ScoreFilter narrows down the scores of a game mode. The zero value matches every score.
+type ScoreFilter struct {
+	// Name matches the scores of the player with this name, ignoring case.
+	Name string
+	// StartLevel matches the scores of games which started at this level, when it is non-zero.
+	StartLevel int
+	// From matches the scores saved at or after this time, when it is non-zero.
+	From time.Time
+	// To matches the scores saved before this time, when it is non-zero.
+	To time.Time
+}
+
+// IsZero reports whether the filter matches every score.
+func (f ScoreFilter) IsZero() bool {
+	return f == ScoreFilter{}
+}
+
+// where returns an SQL condition matching the filter, appending its arguments to args.
+// Arguments are numbered after those already in args.
+func (f ScoreFilter) where(args []any) (string, []any) {
+	conditions := []string{"TRUE"}
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if f.Name != "" {
+		add("name = $%d COLLATE NOCASE", f.Name)
+	}
+	if f.StartLevel != 0 {
+		add("start_level = $%d", f.StartLevel)
+	}
+	if !f.From.IsZero() {
+		add("created_at >= $%d", f.From.Unix())
+	}
+	if !f.To.IsZero() {
+		add("created_at < $%d", f.To.Unix())
+	}
+	return strings.Join(conditions, " AND "), args
 }
 
 type LeaderboardRepository struct {
@@ -25,12 +115,39 @@ func NewLeaderboardRepository(db *sql.DB) *LeaderboardRepository {
 	return &LeaderboardRepository{db}
 }
 
+// All returns the scores of the given game mode, ordered by the Ranking of the mode.
 func (r *LeaderboardRepository) All(ctx context.Context, gameMode string) ([]Score, error) {
+	return r.Page(ctx, gameMode, ScoreFilter{}, 0, 0)
+}
+
+// Page returns up to limit scores of the given game mode which match the filter, after skipping the first offset.
+// They are ordered by the Ranking of the mode, with a limit of 0 returning every remaining score.
+// The rank of each score is its position amongst every score of the mode, not only those matching the filter.
+// Game modes are matched ignoring case.
+func (r *LeaderboardRepository) Page(
+	ctx context.Context, gameMode string, filter ScoreFilter, offset, limit int,
+) ([]Score, error) {
+	if limit <= 0 {
+		// SQLite treats a negative limit as no limit.
+		limit = -1
+	}
+	// The limit and offset are the last arguments, since SQLite numbers "$" parameters in the order they appear.
+	where, args := filter.where([]any{gameMode})
+	args = append(args, limit, offset)
+
+	//nolint:gosec // The ORDER BY clause is chosen from the constant Rankings and the WHERE clause only contains
+	// constant conditions, with the filter values passed as arguments.
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, game_mode, name, time, score, lines, level FROM leaderboard
-		WHERE game_mode = $1
-		ORDER BY score DESC, time ASC
-	`, gameMode)
+		WITH ranked AS (
+			SELECT *, ROW_NUMBER() OVER (ORDER BY `+RankingFor(gameMode).orderBy+`, id ASC) AS rank
+			FROM leaderboard
+			WHERE game_mode = $1 COLLATE NOCASE
+		)
+		SELECT `+scoreColumns+`, rank
+		FROM ranked
+		WHERE `+where+`
+		ORDER BY rank
+		LIMIT `+fmt.Sprintf("$%d OFFSET $%d", len(args)-1, len(args)), args...)
 	if err != nil {
 		return nil, err
 	}
@@ -38,11 +155,12 @@ func (r *LeaderboardRepository) All(ctx context.Context, gameMode string) ([]Sco
 
 	var scores []Score
 	for rows.Next() {
-		var s Score
-		if err = rows.Scan(&s.ID, &s.GameMode, &s.Name, &s.Time, &s.Score, &s.Lines, &s.Level); err != nil {
+		var rank int
+		s, err := scanScore(rows, &rank)
+		if err != nil {
 			return nil, err
 		}
-		s.Rank = len(scores) + 1
+		s.Rank = rank
 		scores = append(scores, s)
 	}
 	if err = rows.Err(); err != nil {
@@ -52,11 +170,219 @@ func (r *LeaderboardRepository) All(ctx context.Context, gameMode string) ([]Sco
 	return scores, nil
 }
 
+// Count returns the number of scores of the given game mode which match the filter.
+func (r *LeaderboardRepository) Count(ctx context.Context, gameMode string, filter ScoreFilter) (int, error) {
+	where, args := filter.where([]any{gameMode})
+
+	var count int
+	//nolint:gosec // The WHERE clause only contains constant conditions, with the filter values passed as arguments.
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM leaderboard
+		WHERE game_mode = $1 COLLATE NOCASE AND `+where, args...).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Rank returns the rank of the score with the given ID amongst the scores of the given game mode.
+// It returns sql.ErrNoRows if there is no such score.
+func (r *LeaderboardRepository) Rank(ctx context.Context, gameMode string, id int) (int, error) {
+	var rank int
+	//nolint:gosec // The ORDER BY clause is chosen from the constant Rankings, not user input.
+	err := r.db.QueryRowContext(ctx, `
+		WITH ranked AS (
+			SELECT id, ROW_NUMBER() OVER (ORDER BY `+RankingFor(gameMode).orderBy+`, id ASC) AS rank
+			FROM leaderboard
+			WHERE game_mode = $1 COLLATE NOCASE
+		)
+		SELECT rank FROM ranked WHERE id = $2`, gameMode, id).Scan(&rank)
+	if err != nil {
+		return 0, err
+	}
+	return rank, nil
+}
+
+// GameModes returns each game mode with a score matching the filter, ignoring case, ordered by name.
+func (r *LeaderboardRepository) GameModes(ctx context.Context, filter ScoreFilter) ([]string, error) {
+	where, args := filter.where(nil)
+
+	//nolint:gosec // The WHERE clause only contains constant conditions, with the filter values passed as arguments.
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT game_mode
+		FROM leaderboard
+		WHERE `+where+`
+		GROUP BY lower(game_mode)
+		ORDER BY lower(game_mode)`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var gameModes []string
+	for rows.Next() {
+		var gameMode string
+		if err = rows.Scan(&gameMode); err != nil {
+			return nil, err
+		}
+		gameModes = append(gameModes, gameMode)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return gameModes, nil
+}
+
+// PersonalBests returns the best score of the player with the given name in each game mode they have played.
+// The name is matched ignoring case and the scores are ordered by game mode.
+func (r *LeaderboardRepository) PersonalBests(ctx context.Context, name string) ([]Score, error) {
+	gameModes, err := r.GameModes(ctx, ScoreFilter{Name: name})
+	if err != nil {
+		return nil, err
+	}
+
+	bests := make([]Score, 0, len(gameModes))
+	for _, gameMode := range gameModes {
+		scores, err := r.Page(ctx, gameMode, ScoreFilter{Name: name}, 0, 1)
+		if err != nil {
+			return nil, fmt.Errorf("fetching best %s score: %w", gameMode, err)
+		}
+		bests = append(bests, scores...)
+	}
+	return bests, nil
+}
+
+// Scores returns every score on the leaderboard, across all game modes, in the order they were saved.
+// The scores are not ranked.
+func (r *LeaderboardRepository) Scores(ctx context.Context) ([]Score, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT `+scoreColumns+` FROM leaderboard ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var scores []Score
+	for rows.Next() {
+		s, err := scanScore(rows)
+		if err != nil {
+			return nil, err
+		}
+		scores = append(scores, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return scores, nil
+}
+
+// Get returns the score with the given ID. The score is not ranked.
+// It returns sql.ErrNoRows if there is no such score.
+func (r *LeaderboardRepository) Get(ctx context.Context, id int) (*Score, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+scoreColumns+` FROM leaderboard WHERE id = $1`, id)
+	s, err := scanScore(row)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
 // Save saves a score to the leaderboard and returns the ID of the new score.
 func (r *LeaderboardRepository) Save(ctx context.Context, score *Score) (int, error) {
-	res, err := r.db.ExecContext(ctx,
-		"INSERT INTO leaderboard (game_mode, name, time, score, lines, level) VALUES ($1, $2, $3, $4, $5, $6)",
-		score.GameMode, score.Name, score.Time, score.Score, score.Lines, score.Level,
+	createdAt := score.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	return save(ctx, r.db, score, createdAt.Unix())
+}
+
+// ImportResult is the outcome of LeaderboardRepository.Import.
+type ImportResult struct {
+	// Imported is the number of scores saved to the leaderboard.
+	Imported int
+	// Duplicates is the number of scores skipped because they were already on the leaderboard.
+	Duplicates int
+}
+
+// Import saves the scores which are not already on the leaderboard, such as those exported from another database.
+// A score is a duplicate if its game mode, name, creation time, time played, and all its results are the same.
+// Unlike Save, scores with an unknown creation time are saved without one.
+// The scores are saved in a single transaction, so either all or none of them are saved.
+// When dryRun is true nothing is saved, but the result is the same as if the scores were.
+func (r *LeaderboardRepository) Import(ctx context.Context, scores []Score, dryRun bool) (res ImportResult, err error) {
+	for i := range scores {
+		if err = scores[i].Validate(); err != nil {
+			return ImportResult{}, fmt.Errorf("invalid score %d: %w", i+1, err)
+		}
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return ImportResult{}, err
+	}
+	defer func() {
+		if err != nil || dryRun {
+			err = errors.Join(err, tx.Rollback())
+		}
+	}()
+
+	for i := range scores {
+		var exists bool
+		exists, err = scoreExists(ctx, tx, &scores[i])
+		if err != nil {
+			return ImportResult{}, fmt.Errorf("checking for duplicate of score %d: %w", i+1, err)
+		}
+		if exists {
+			res.Duplicates++
+			continue
+		}
+
+		if _, err = save(ctx, tx, &scores[i], unixTime(scores[i].CreatedAt)); err != nil {
+			return ImportResult{}, fmt.Errorf("saving score %d: %w", i+1, err)
+		}
+		res.Imported++
+	}
+
+	if dryRun {
+		return res, nil
+	}
+	return res, tx.Commit()
+}
+
+// execer is implemented by both sql.DB and sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// save saves the score with the given creation time (in Unix seconds), since its CreatedAt may be unset.
+// The score is checked against its Replay once here, so that the leaderboard doesn't have to re-simulate it.
+func save(ctx context.Context, ex execer, score *Score, createdAt int64) (int, error) {
+	var replayJSON []byte
+	if score.Replay != nil {
+		var err error
+		replayJSON, err = json.Marshal(score.Replay)
+		if err != nil {
+			return 0, fmt.Errorf("encoding replay: %w", err)
+		}
+	}
+	var splitsJSON sql.NullString
+	if score.Splits != nil {
+		b, err := json.Marshal(score.Splits)
+		if err != nil {
+			return 0, fmt.Errorf("encoding splits: %w", err)
+		}
+		splitsJSON = sql.NullString{String: string(b), Valid: true}
+	}
+
+	res, err := ex.ExecContext(ctx, `
+		INSERT INTO leaderboard (game_mode, name, time, score, lines, level, completed, start_level, created_at,
+			pieces, pps, max_combo, t_spins, mini_t_spins, tetrises, splits, replay, replay_check)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)`,
+		score.GameMode, score.Name, score.Time, score.Score, score.Lines, score.Level, score.Completed,
+		score.StartLevel, createdAt,
+		score.Pieces, score.PPS, score.MaxCombo, score.TSpins, score.MiniTSpins, score.Tetrises, splitsJSON, replayJSON,
+		score.CheckReplay(),
 	)
 	if err != nil {
 		return 0, err
@@ -67,3 +393,60 @@ func (r *LeaderboardRepository) Save(ctx context.Context, score *Score) (int, er
 	}
 	return int(id), nil
 }
+
+// scoreColumns are the columns of the leaderboard table which are scanned by scanScore, in order.
+const scoreColumns = `id, game_mode, name, time, score, lines, level, completed, start_level, created_at,
+	pieces, pps, max_combo, t_spins, mini_t_spins, tetrises, splits, replay, replay_check`
+
+// scanScore scans a row starting with the scoreColumns, followed by the given extra columns.
+func scanScore(row interface{ Scan(dest ...any) error }, extra ...any) (Score, error) {
+	var s Score
+	var createdAt int64
+	var splitsJSON sql.NullString
+	var replayJSON []byte
+	dest := []any{&s.ID, &s.GameMode, &s.Name, &s.Time, &s.Score, &s.Lines, &s.Level, &s.Completed,
+		&s.StartLevel, &createdAt, &s.Pieces, &s.PPS, &s.MaxCombo, &s.TSpins, &s.MiniTSpins, &s.Tetrises,
+		&splitsJSON, &replayJSON, &s.ReplayCheck}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return Score{}, err
+	}
+
+	if createdAt != 0 {
+		s.CreatedAt = time.Unix(createdAt, 0)
+	}
+	if splitsJSON.Valid {
+		if err := json.Unmarshal([]byte(splitsJSON.String), &s.Splits); err != nil {
+			return Score{}, fmt.Errorf("decoding splits of score %d: %w", s.ID, err)
+		}
+	}
+	if replayJSON != nil {
+		s.Replay = new(replay.Replay)
+		if err := json.Unmarshal(replayJSON, s.Replay); err != nil {
+			return Score{}, fmt.Errorf("decoding replay of score %d: %w", s.ID, err)
+		}
+	}
+	return s, nil
+}
+
+// scoreExists reports whether the leaderboard has a duplicate of the score, as described by Import.
+func scoreExists(ctx context.Context, q querier, score *Score) (bool, error) {
+	var exists bool
+	err := q.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM leaderboard
+			WHERE game_mode = $1 AND name = $2 AND time = $3 AND score = $4 AND lines = $5 AND level = $6
+				AND completed = $7 AND start_level = $8 AND created_at = $9 AND pieces = $10
+		)`,
+		score.GameMode, score.Name, score.Time, score.Score, score.Lines, score.Level,
+		score.Completed, score.StartLevel, unixTime(score.CreatedAt), score.Pieces,
+	).Scan(&exists)
+	return exists, err
+}
+
+// unixTime returns the Unix time (in seconds) which is stored for t, with the zero time being stored as 0.
+func unixTime(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
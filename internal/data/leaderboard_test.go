@@ -2,6 +2,8 @@ package data_test
 
 import (
 	"context"
+	"database/sql"
+	"strings"
 	"testing"
 	"time"
 
@@ -70,6 +72,13 @@ func TestSave(t *testing.T) {
 			Score:    5000,
 			Lines:    40,
 			Level:    7,
+
+			Pieces:     100,
+			PPS:        0.66,
+			MaxCombo:   4,
+			TSpins:     2,
+			MiniTSpins: 1,
+			Tetrises:   3,
 		}
 
 		id, err := repo.Save(context.Background(), input)
@@ -87,6 +96,12 @@ func TestSave(t *testing.T) {
 		assert.Equal(t, input.Score, got.Score)
 		assert.Equal(t, input.Lines, got.Lines)
 		assert.Equal(t, input.Level, got.Level)
+		assert.Equal(t, input.Pieces, got.Pieces)
+		assert.InDelta(t, input.PPS, got.PPS, 1e-9)
+		assert.Equal(t, input.MaxCombo, got.MaxCombo)
+		assert.Equal(t, input.TSpins, got.TSpins)
+		assert.Equal(t, input.MiniTSpins, got.MiniTSpins)
+		assert.Equal(t, input.Tetrises, got.Tetrises)
 		assert.Equal(t, 1, got.Rank)
 	})
 
@@ -293,3 +308,353 @@ func TestAll(t *testing.T) {
 		assert.Empty(t, scores)
 	})
 }
+
+func TestAll_Ranking(t *testing.T) {
+	t.Parallel()
+
+	tt := map[string]struct {
+		gameMode  string
+		entries   []data.Score
+		wantNames []string
+	}{
+		"marathon ranks by score then lines": {
+			gameMode: "Marathon",
+			entries: []data.Score{
+				{Name: "FewerLines", Score: 1000, Lines: 8, Time: time.Minute},
+				{Name: "Low", Score: 500, Lines: 20, Time: time.Minute},
+				{Name: "MoreLines", Score: 1000, Lines: 10, Time: 2 * time.Minute},
+			},
+			wantNames: []string{"MoreLines", "FewerLines", "Low"},
+		},
+		"sprint ranks completed runs by time": {
+			gameMode: "Sprint",
+			entries: []data.Score{
+				{Name: "Slow", Score: 9000, Lines: 40, Time: 2 * time.Minute, Completed: true},
+				{Name: "Aborted", Score: 100, Lines: 12, Time: 10 * time.Second},
+				{Name: "Fast", Score: 5000, Lines: 40, Time: time.Minute, Completed: true},
+				{Name: "AbortedLater", Score: 200, Lines: 30, Time: time.Minute},
+			},
+			wantNames: []string{"Fast", "Slow", "AbortedLater", "Aborted"},
+		},
+		"ultra ranks by score then lines": {
+			gameMode: "ultra",
+			entries: []data.Score{
+				{Name: "Low", Score: 500, Lines: 30, Time: 2 * time.Minute, Completed: true},
+				{Name: "High", Score: 4000, Lines: 20, Time: 90 * time.Second},
+				{Name: "HighMoreLines", Score: 4000, Lines: 25, Time: 2 * time.Minute, Completed: true},
+			},
+			wantNames: []string{"HighMoreLines", "High", "Low"},
+		},
+		"master ranks by grade then level": {
+			gameMode: "Master",
+			entries: []data.Score{
+				{Name: "S9", Score: 200000, Level: 998, Time: 12 * time.Minute},
+				{Name: "GM", Score: 130000, Level: 999, Time: 13 * time.Minute, Completed: true},
+				{Name: "LowerLevel", Score: 5000, Level: 300, Time: 4 * time.Minute},
+				{Name: "HigherLevel", Score: 5000, Level: 320, Time: 5 * time.Minute},
+			},
+			wantNames: []string{"GM", "S9", "HigherLevel", "LowerLevel"},
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			db := setupTestDB(t)
+			repo := data.NewLeaderboardRepository(db)
+
+			for i := range tc.entries {
+				tc.entries[i].GameMode = tc.gameMode
+				_, err := repo.Save(context.Background(), &tc.entries[i])
+				require.NoError(t, err)
+			}
+
+			scores, err := repo.All(context.Background(), tc.gameMode)
+			require.NoError(t, err)
+			names := make([]string, len(scores))
+			for i, s := range scores {
+				names[i] = s.Name
+				assert.Equal(t, i+1, s.Rank)
+			}
+			assert.Equal(t, tc.wantNames, names)
+		})
+	}
+}
+
+func TestPage(t *testing.T) {
+	t.Parallel()
+
+	day := time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC)
+	entries := []data.Score{
+		{Name: "Alice", Score: 5000, StartLevel: 1, CreatedAt: day},
+		{Name: "Bob", Score: 4000, StartLevel: 5, CreatedAt: day.AddDate(0, 0, 1)},
+		{Name: "alice", Score: 3000, StartLevel: 5, CreatedAt: day.AddDate(0, 0, 2)},
+		{Name: "Carol", Score: 2000, StartLevel: 1, CreatedAt: day.AddDate(0, 0, 3)},
+		{Name: "Alice", Score: 1000, StartLevel: 1, CreatedAt: day.AddDate(0, 0, 4)},
+	}
+
+	tt := map[string]struct {
+		filter    data.ScoreFilter
+		offset    int
+		limit     int
+		wantRanks []int
+	}{
+		"no filter or limit": {
+			wantRanks: []int{1, 2, 3, 4, 5},
+		},
+		"first page": {
+			limit:     2,
+			wantRanks: []int{1, 2},
+		},
+		"last partial page": {
+			offset:    4,
+			limit:     2,
+			wantRanks: []int{5},
+		},
+		"offset past the end": {
+			offset:    10,
+			limit:     2,
+			wantRanks: nil,
+		},
+		"name ignores case": {
+			filter:    data.ScoreFilter{Name: "ALICE"},
+			wantRanks: []int{1, 3, 5},
+		},
+		"start level": {
+			filter:    data.ScoreFilter{StartLevel: 5},
+			wantRanks: []int{2, 3},
+		},
+		"date range": {
+			filter:    data.ScoreFilter{From: day.AddDate(0, 0, 1), To: day.AddDate(0, 0, 3)},
+			wantRanks: []int{2, 3},
+		},
+		"combined filters with paging": {
+			filter:    data.ScoreFilter{Name: "alice", StartLevel: 1},
+			offset:    1,
+			limit:     1,
+			wantRanks: []int{5},
+		},
+	}
+
+	db := setupTestDB(t)
+	repo := data.NewLeaderboardRepository(db)
+	for i := range entries {
+		entries[i].GameMode = "Marathon"
+		_, err := repo.Save(context.Background(), &entries[i])
+		require.NoError(t, err)
+	}
+
+	// The subtests share the database, so they aren't run in parallel (each in-memory connection is a separate database).
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			// The game mode is matched ignoring case.
+			scores, err := repo.Page(context.Background(), "marathon", tc.filter, tc.offset, tc.limit)
+			require.NoError(t, err)
+			var ranks []int
+			for _, s := range scores {
+				ranks = append(ranks, s.Rank)
+				assert.Equal(t, entries[s.Rank-1].CreatedAt.Unix(), s.CreatedAt.Unix())
+				assert.Equal(t, entries[s.Rank-1].StartLevel, s.StartLevel)
+			}
+			assert.Equal(t, tc.wantRanks, ranks)
+
+			if tc.offset == 0 && tc.limit == 0 {
+				count, err := repo.Count(context.Background(), "marathon", tc.filter)
+				require.NoError(t, err)
+				assert.Equal(t, len(tc.wantRanks), count)
+			}
+		})
+	}
+}
+
+func TestSave_CreatedAt(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	repo := data.NewLeaderboardRepository(db)
+
+	before := time.Now().Add(-time.Second)
+	_, err := repo.Save(context.Background(), &data.Score{GameMode: "marathon", Name: "Alice"})
+	require.NoError(t, err)
+
+	scores, err := repo.All(context.Background(), "marathon")
+	require.NoError(t, err)
+	require.Len(t, scores, 1)
+	assert.WithinRange(t, scores[0].CreatedAt, before, time.Now())
+}
+
+func TestRank(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	repo := data.NewLeaderboardRepository(db)
+	ctx := context.Background()
+
+	ids := make([]int, 3)
+	for i := range ids {
+		var err error
+		ids[i], err = repo.Save(ctx, &data.Score{GameMode: "marathon", Name: "Alice", Score: i * 100})
+		require.NoError(t, err)
+	}
+
+	for i, id := range ids {
+		rank, err := repo.Rank(ctx, "marathon", id)
+		require.NoError(t, err)
+		assert.Equal(t, len(ids)-i, rank)
+	}
+
+	_, err := repo.Rank(ctx, "sprint", ids[0])
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestPersonalBests(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	repo := data.NewLeaderboardRepository(db)
+	ctx := context.Background()
+
+	for _, s := range []data.Score{
+		{GameMode: "Sprint", Name: "Alice", Lines: 40, Time: 90 * time.Second, Completed: true},
+		{GameMode: "Sprint", Name: "Bob", Lines: 40, Time: 60 * time.Second, Completed: true},
+		{GameMode: "Sprint", Name: "alice", Lines: 40, Time: 80 * time.Second, Completed: true},
+		{GameMode: "Marathon", Name: "Alice", Score: 1000},
+		{GameMode: "marathon", Name: "Alice", Score: 3000},
+		{GameMode: "Ultra", Name: "Bob", Score: 9000},
+		{
+			GameMode: "Sprint 20L", Name: "Alice", Lines: 20, Time: 30 * time.Second, Completed: true,
+			Splits: []time.Duration{14 * time.Second, 30 * time.Second},
+		},
+	} {
+		_, err := repo.Save(ctx, &s)
+		require.NoError(t, err)
+	}
+
+	bests, err := repo.PersonalBests(ctx, "Alice")
+	require.NoError(t, err)
+	require.Len(t, bests, 3)
+
+	assert.True(t, strings.EqualFold("marathon", bests[0].GameMode))
+	assert.Equal(t, 3000, bests[0].Score)
+	assert.Equal(t, 1, bests[0].Rank)
+
+	assert.Equal(t, "Sprint", bests[1].GameMode)
+	assert.Equal(t, 80*time.Second, bests[1].Time)
+	assert.Equal(t, 2, bests[1].Rank)
+	assert.Nil(t, bests[1].Splits)
+
+	// Variants of a game mode are ranked separately.
+	assert.Equal(t, "Sprint 20L", bests[2].GameMode)
+	assert.Equal(t, 1, bests[2].Rank)
+	assert.Equal(t, []time.Duration{14 * time.Second, 30 * time.Second}, bests[2].Splits)
+
+	bests, err = repo.PersonalBests(ctx, "Nobody")
+	require.NoError(t, err)
+	assert.Empty(t, bests)
+}
+
+func TestGameModes(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	repo := data.NewLeaderboardRepository(db)
+	ctx := context.Background()
+
+	for _, s := range []data.Score{
+		{GameMode: "Sprint 100L", Name: "Alice"},
+		{GameMode: "Marathon", Name: "Bob"},
+		{GameMode: "sprint 100l", Name: "Bob"},
+		{GameMode: "Sprint", Name: "Bob"},
+	} {
+		_, err := repo.Save(ctx, &s)
+		require.NoError(t, err)
+	}
+
+	gameModes, err := repo.GameModes(ctx, data.ScoreFilter{})
+	require.NoError(t, err)
+	require.Len(t, gameModes, 3)
+	assert.Equal(t, "Marathon", gameModes[0])
+	assert.Equal(t, "Sprint", gameModes[1])
+	assert.True(t, strings.EqualFold("Sprint 100L", gameModes[2]))
+
+	gameModes, err = repo.GameModes(ctx, data.ScoreFilter{Name: "alice"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Sprint 100L"}, gameModes)
+}
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("skips duplicates", func(t *testing.T) {
+		t.Parallel()
+		db := setupTestDB(t)
+		repo := data.NewLeaderboardRepository(db)
+		ctx := context.Background()
+
+		scores := exampleScores()
+		res, err := repo.Import(ctx, scores, false)
+		require.NoError(t, err)
+		assert.Equal(t, data.ImportResult{Imported: 2}, res)
+
+		// Scores with an unknown creation time are still detected as duplicates.
+		scores = append(scores, data.Score{GameMode: "Ultra", Name: "Carol", Score: 100})
+		res, err = repo.Import(ctx, scores, false)
+		require.NoError(t, err)
+		assert.Equal(t, data.ImportResult{Imported: 1, Duplicates: 2}, res)
+
+		all, err := repo.Scores(ctx)
+		require.NoError(t, err)
+		require.Len(t, all, 3)
+		assert.True(t, all[1].CreatedAt.IsZero())
+	})
+
+	t.Run("duplicates within the import", func(t *testing.T) {
+		t.Parallel()
+		db := setupTestDB(t)
+		repo := data.NewLeaderboardRepository(db)
+
+		scores := append(exampleScores(), exampleScores()...)
+		res, err := repo.Import(context.Background(), scores, true)
+		require.NoError(t, err)
+		assert.Equal(t, data.ImportResult{Imported: 2, Duplicates: 2}, res)
+	})
+
+	t.Run("dry run saves nothing", func(t *testing.T) {
+		t.Parallel()
+		db := setupTestDB(t)
+		repo := data.NewLeaderboardRepository(db)
+		ctx := context.Background()
+
+		res, err := repo.Import(ctx, exampleScores(), true)
+		require.NoError(t, err)
+		assert.Equal(t, data.ImportResult{Imported: 2}, res)
+
+		all, err := repo.Scores(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, all)
+	})
+
+	t.Run("invalid score saves nothing", func(t *testing.T) {
+		t.Parallel()
+		db := setupTestDB(t)
+		repo := data.NewLeaderboardRepository(db)
+		ctx := context.Background()
+
+		scores := exampleScores()
+		scores[1].Lines = -1
+		_, err := repo.Import(ctx, scores, false)
+		require.ErrorContains(t, err, "invalid score 2")
+
+		all, err := repo.Scores(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, all)
+	})
+}
+
+func TestRankingFor(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, data.RankingMarathon, data.RankingFor("Marathon"))
+	assert.Equal(t, data.RankingSprint, data.RankingFor("sprint"))
+	assert.Equal(t, data.RankingSprint, data.RankingFor("Sprint 20L"))
+	assert.Equal(t, data.RankingUltra, data.RankingFor("ULTRA"))
+	assert.Equal(t, data.RankingDig, data.RankingFor("Dig 18L Rising"))
+	assert.Equal(t, data.RankingMaster, data.RankingFor("master"))
+	assert.Equal(t, data.RankingDefault, data.RankingFor("unknown"))
+}
@@ -0,0 +1,210 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// migrationsFS holds the up-migrations, named "<version>_<description>.sql" with versions starting at 1.
+// Once released, a migration must never be changed; schema changes are made by adding a new migration instead.
+//
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// querier is implemented by both sql.DB and sql.Tx.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// migration is a change to the database schema, identified by the version it upgrades the schema to.
+type migration struct {
+	version int
+	name    string
+	query   string
+}
+
+// Migrate upgrades the database schema to the latest version.
+// All pending migrations are applied in a single transaction, so the schema is left unchanged if any of them fail.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	migrations, err := loadMigrations(migrationsFS)
+	if err != nil {
+		return fmt.Errorf("loading migrations: %w", err)
+	}
+	return applyMigrations(ctx, db, migrations)
+}
+
+// SchemaVersion returns the version of the database schema, or 0 if it has not been created.
+func SchemaVersion(ctx context.Context, db *sql.DB) (int, error) {
+	return schemaVersion(ctx, db)
+}
+
+// LatestSchemaVersion returns the schema version which Migrate upgrades the database to.
+func LatestSchemaVersion() (int, error) {
+	migrations, err := loadMigrations(migrationsFS)
+	if err != nil {
+		return 0, fmt.Errorf("loading migrations: %w", err)
+	}
+	if len(migrations) == 0 {
+		return 0, nil
+	}
+	return migrations[len(migrations)-1].version, nil
+}
+
+// loadMigrations reads the migrations in the root "migrations" directory of fsys, ordered by version.
+// It returns an error if the versions are not consecutive from 1.
+func loadMigrations(fsys fs.FS) ([]migration, error) {
+	entries, err := fs.ReadDir(fsys, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	// ReadDir returns the entries sorted by name, and the versions are zero-padded.
+	migrations := make([]migration, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), ".sql")
+		versionStr, _, _ := strings.Cut(name, "_")
+		version, err := strconv.Atoi(versionStr)
+		if err != nil {
+			return nil, fmt.Errorf("migration %q does not start with a version: %w", entry.Name(), err)
+		}
+		if version != len(migrations)+1 {
+			return nil, fmt.Errorf("migration %q has version %d, expected %d", entry.Name(), version, len(migrations)+1)
+		}
+
+		query, err := fs.ReadFile(fsys, path.Join("migrations", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, migration{version: version, name: name, query: string(query)})
+	}
+	return migrations, nil
+}
+
+func applyMigrations(ctx context.Context, db *sql.DB, migrations []migration) (err error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, tx.Rollback())
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_version
+(version INTEGER PRIMARY KEY, name TEXT NOT NULL, applied_at INTEGER NOT NULL)`)
+	if err != nil {
+		return fmt.Errorf("creating schema_version table: %w", err)
+	}
+
+	current, err := schemaVersion(ctx, tx)
+	if err != nil {
+		return fmt.Errorf("reading schema version: %w", err)
+	}
+	if latest := len(migrations); current > latest {
+		return fmt.Errorf("database schema version %d is newer than the latest known version %d", current, latest)
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		if _, err = tx.ExecContext(ctx, m.query); err != nil {
+			return fmt.Errorf("applying migration %q: %w", m.name, err)
+		}
+		_, err = tx.ExecContext(ctx, "INSERT INTO schema_version (version, name, applied_at) VALUES ($1, $2, $3)",
+			m.version, m.name, time.Now().Unix())
+		if err != nil {
+			return fmt.Errorf("recording migration %q: %w", m.name, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// schemaVersion returns the latest version recorded in the schema_version table.
+// Databases created before versions were tracked have no schema_version table (or an empty one), in which case
+// the version is inferred from the columns of the leaderboard table.
+func schemaVersion(ctx context.Context, q querier) (int, error) {
+	tables, err := tableNames(ctx, q)
+	if err != nil {
+		return 0, err
+	}
+
+	if tables["schema_version"] {
+		var version sql.NullInt64
+		err = q.QueryRowContext(ctx, "SELECT MAX(version) FROM schema_version").Scan(&version)
+		if err != nil {
+			return 0, err
+		}
+		if version.Valid {
+			return int(version.Int64), nil
+		}
+	}
+
+	if !tables["leaderboard"] {
+		return 0, nil
+	}
+	columns, err := tableColumns(ctx, q, "leaderboard")
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case columns["completed"]:
+		return 3, nil
+	case columns["pieces"]:
+		return 2, nil
+	default:
+		return 1, nil
+	}
+}
+
+// tableNames returns the set of table names in the database.
+func tableNames(ctx context.Context, q querier) (map[string]bool, error) {
+	rows, err := q.QueryContext(ctx, "SELECT name FROM sqlite_master WHERE type = 'table'")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tables := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		tables[name] = true
+	}
+	return tables, rows.Err()
+}
+
+// tableColumns returns the set of column names in the given table.
+func tableColumns(ctx context.Context, q querier, table string) (map[string]bool, error) {
+	rows, err := q.QueryContext(ctx, "SELECT name FROM pragma_table_info($1)", table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		columns[name] = true
+	}
+	return columns, rows.Err()
+}
@@ -0,0 +1,98 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	t.Parallel()
+
+	tt := map[string]struct {
+		files     fstest.MapFS
+		wantNames []string
+		wantErr   bool
+	}{
+		"ordered by version": {
+			files: fstest.MapFS{
+				"migrations/0002_second.sql": {Data: []byte("SELECT 2;")},
+				"migrations/0001_first.sql":  {Data: []byte("SELECT 1;")},
+				"migrations/README.md":       {Data: []byte("not a migration")},
+			},
+			wantNames: []string{"0001_first", "0002_second"},
+		},
+		"missing version": {
+			files: fstest.MapFS{
+				"migrations/0001_first.sql": {Data: []byte("SELECT 1;")},
+				"migrations/0003_third.sql": {Data: []byte("SELECT 3;")},
+			},
+			wantErr: true,
+		},
+		"no version": {
+			files: fstest.MapFS{
+				"migrations/first.sql": {Data: []byte("SELECT 1;")},
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			migrations, err := loadMigrations(tc.files)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			names := make([]string, len(migrations))
+			for i, m := range migrations {
+				names[i] = m.name
+				assert.Equal(t, i+1, m.version)
+			}
+			assert.Equal(t, tc.wantNames, names)
+		})
+	}
+}
+
+func TestLoadMigrations_Embedded(t *testing.T) {
+	t.Parallel()
+	migrations, err := loadMigrations(migrationsFS)
+	require.NoError(t, err)
+	assert.NotEmpty(t, migrations)
+}
+
+func TestApplyMigrations_RollsBackOnError(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() {
+		if closeErr := db.Close(); closeErr != nil {
+			t.Errorf("closing test DB: %v", closeErr)
+		}
+	})
+
+	migrations := []migration{
+		{version: 1, name: "0001_create", query: "CREATE TABLE example (id INTEGER PRIMARY KEY);"},
+		{version: 2, name: "0002_invalid", query: "ALTER TABLE missing ADD COLUMN value INTEGER;"},
+	}
+	require.Error(t, applyMigrations(ctx, db, migrations))
+
+	// Neither the first migration nor the schema_version table should remain.
+	tables, err := tableNames(ctx, db)
+	require.NoError(t, err)
+	assert.Empty(t, tables)
+
+	require.NoError(t, applyMigrations(ctx, db, migrations[:1]))
+	version, err := schemaVersion(ctx, db)
+	require.NoError(t, err)
+	assert.Equal(t, 1, version)
+}
@@ -0,0 +1,136 @@
+package data_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate_Fixtures(t *testing.T) {
+	t.Parallel()
+
+	latest, err := data.LatestSchemaVersion()
+	require.NoError(t, err)
+
+	tt := map[string]struct {
+		fixture     string
+		wantVersion int
+	}{
+		"unversioned v1": {fixture: "v1_unversioned.sql", wantVersion: 1},
+		"unversioned v2": {fixture: "v2_unversioned.sql", wantVersion: 2},
+		"unversioned v3": {fixture: "v3_unversioned.sql", wantVersion: 3},
+		"v1":             {fixture: "v1.sql", wantVersion: 1},
+		"v2":             {fixture: "v2.sql", wantVersion: 2},
+		"v3":             {fixture: "v3.sql", wantVersion: 3},
+		"v4":             {fixture: "v4.sql", wantVersion: 4},
+		"v5":             {fixture: "v5.sql", wantVersion: 5},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+			db := openFixtureDB(t, tc.fixture)
+
+			version, err := data.SchemaVersion(ctx, db)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantVersion, version)
+
+			// Running it twice checks that applied migrations are not applied again.
+			require.NoError(t, data.Migrate(ctx, db))
+			require.NoError(t, data.Migrate(ctx, db))
+
+			version, err = data.SchemaVersion(ctx, db)
+			require.NoError(t, err)
+			assert.Equal(t, latest, version)
+
+			repo := data.NewLeaderboardRepository(db)
+			_, err = repo.Save(ctx, &data.Score{
+				GameMode: "marathon", Name: "Bob", Time: time.Minute, Score: 500, Lines: 5, Level: 1, Pieces: 30, PPS: 0.5,
+			})
+			require.NoError(t, err)
+
+			scores, err := repo.All(ctx, "marathon")
+			require.NoError(t, err)
+			require.Len(t, scores, 2)
+			assert.Equal(t, "Alice", scores[0].Name)
+			assert.Equal(t, 1000, scores[0].Score)
+			assert.Zero(t, scores[0].Pieces)
+			assert.Equal(t, data.ReplayUnchecked, scores[0].ReplayCheck)
+			assert.Equal(t, "Bob", scores[1].Name)
+			assert.Equal(t, 30, scores[1].Pieces)
+
+			// Sprint runs which reached 40 lines are marked as completed.
+			scores, err = repo.All(ctx, "Sprint")
+			require.NoError(t, err)
+			require.Len(t, scores, 2)
+			assert.Equal(t, "Finished", scores[0].Name)
+			assert.True(t, scores[0].Completed)
+			assert.False(t, scores[1].Completed)
+		})
+	}
+}
+
+func TestMigrate_NewDatabase(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := openFixtureDB(t, "")
+
+	version, err := data.SchemaVersion(ctx, db)
+	require.NoError(t, err)
+	assert.Zero(t, version)
+
+	require.NoError(t, data.Migrate(ctx, db))
+
+	latest, err := data.LatestSchemaVersion()
+	require.NoError(t, err)
+	version, err = data.SchemaVersion(ctx, db)
+	require.NoError(t, err)
+	assert.Equal(t, latest, version)
+
+	var applied int
+	require.NoError(t, db.QueryRowContext(ctx, "SELECT COUNT(*) FROM schema_version").Scan(&applied))
+	assert.Equal(t, latest, applied)
+}
+
+func TestMigrate_NewerVersion(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := openFixtureDB(t, "v3.sql")
+
+	_, err := db.ExecContext(ctx,
+		"INSERT INTO schema_version (version, name, applied_at) VALUES (1000, '1000_from_the_future', 0)")
+	require.NoError(t, err)
+
+	assert.Error(t, data.Migrate(ctx, db))
+}
+
+// openFixtureDB returns an in-memory database created by the given SQL file in testdata.
+// No file is run when fixture is empty.
+func openFixtureDB(t *testing.T, fixture string) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	// Each connection to ":memory:" opens a separate database, so only one may be used.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() {
+		if closeErr := db.Close(); closeErr != nil {
+			t.Errorf("closing test DB: %v", closeErr)
+		}
+	})
+
+	if fixture != "" {
+		query, err := os.ReadFile(filepath.Join("testdata", fixture))
+		require.NoError(t, err)
+		_, err = db.ExecContext(context.Background(), string(query))
+		require.NoError(t, err)
+	}
+	return db
+}
@@ -0,0 +1,3 @@
+-- The leaderboard table as it was first released, before schema versions were tracked.
+CREATE TABLE IF NOT EXISTS leaderboard
+(id INTEGER PRIMARY KEY, game_mode TEXT, name TEXT, time INTEGER, score INTEGER, lines INTEGER, level INTEGER);
@@ -0,0 +1,6 @@
+ALTER TABLE leaderboard ADD COLUMN pieces INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE leaderboard ADD COLUMN pps REAL NOT NULL DEFAULT 0;
+ALTER TABLE leaderboard ADD COLUMN max_combo INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE leaderboard ADD COLUMN t_spins INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE leaderboard ADD COLUMN mini_t_spins INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE leaderboard ADD COLUMN tetrises INTEGER NOT NULL DEFAULT 0;
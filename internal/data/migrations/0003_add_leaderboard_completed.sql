@@ -0,0 +1,4 @@
+ALTER TABLE leaderboard ADD COLUMN completed INTEGER NOT NULL DEFAULT 0;
+
+-- Sprint is the only mode where a finished run can be told apart from an aborted one.
+UPDATE leaderboard SET completed = 1 WHERE lower(game_mode) = 'sprint' AND lines >= 40;
@@ -0,0 +1,4 @@
+-- Both are 0 for scores saved before they were recorded, since their values are unknown.
+ALTER TABLE leaderboard ADD COLUMN start_level INTEGER NOT NULL DEFAULT 0;
+-- The Unix time (in seconds) the score was saved.
+ALTER TABLE leaderboard ADD COLUMN created_at INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+-- The JSON-encoded replay of the game, or NULL for scores saved without one.
+ALTER TABLE leaderboard ADD COLUMN replay BLOB;
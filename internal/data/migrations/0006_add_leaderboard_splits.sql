@@ -0,0 +1,2 @@
+-- The JSON-encoded split times of a Sprint, or NULL for scores without splits.
+ALTER TABLE leaderboard ADD COLUMN splits TEXT;
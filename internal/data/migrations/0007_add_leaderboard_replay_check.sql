@@ -0,0 +1,3 @@
+-- Whether the score matched its replay when it was saved (see ReplayCheck), since re-simulating every replay
+-- whenever the leaderboard is displayed is too slow. Existing scores are left unchecked.
+ALTER TABLE leaderboard ADD COLUMN replay_check INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,66 @@
+package data
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris"
+)
+
+// A Ranking decides the order of the scores of a game mode on the leaderboard.
+type Ranking struct {
+	name    string
+	orderBy string
+}
+
+var (
+	// RankingMarathon ranks by the highest score, then the most lines.
+	RankingMarathon = Ranking{
+		name:    "marathon",
+		orderBy: "score DESC, lines DESC, time ASC",
+	}
+	// RankingSprint ranks completed runs by the fastest time. Aborted runs are ranked below them by the most lines.
+	RankingSprint = Ranking{
+		name:    "sprint",
+		orderBy: "completed DESC, CASE WHEN completed THEN time ELSE 0 END ASC, lines DESC, time ASC",
+	}
+	// RankingUltra ranks by the highest score within the time limit, then the most lines.
+	RankingUltra = Ranking{
+		name:    "ultra",
+		orderBy: "score DESC, lines DESC",
+	}
+	// RankingDig ranks runs which cleared every garbage line by the fastest time, in the same way as RankingSprint.
+	RankingDig = Ranking{
+		name:    "dig",
+		orderBy: RankingSprint.orderBy,
+	}
+	// RankingMaster ranks by the highest grade, then the highest level and the fastest time. Since the grade is
+	// based on the score (see tetris.MasterGradeFor), the GM grade is ranked first and then the highest score.
+	RankingMaster = Ranking{
+		name: "master",
+		orderBy: fmt.Sprintf("CASE WHEN completed AND score >= %d THEN 1 ELSE 0 END DESC, "+
+			"score DESC, level DESC, time ASC", tetris.MasterGrandMasterScore),
+	}
+	// RankingDefault ranks by the highest score, then the fastest time. It is used for unknown game modes.
+	RankingDefault = Ranking{
+		name:    "default",
+		orderBy: "score DESC, time ASC",
+	}
+)
+
+// RankingFor returns the Ranking of the given game mode, ignoring case. Variants of a game mode, whose name is
+// followed by a space and their options (eg. "Sprint 20L"), are ranked the same as the game mode.
+// RankingDefault is returned for unknown game modes.
+func RankingFor(gameMode string) Ranking {
+	name, _, _ := strings.Cut(gameMode, " ")
+	for _, r := range []Ranking{RankingMarathon, RankingSprint, RankingUltra, RankingDig, RankingMaster} {
+		if strings.EqualFold(name, r.name) {
+			return r
+		}
+	}
+	return RankingDefault
+}
+
+func (r Ranking) String() string {
+	return r.name
+}
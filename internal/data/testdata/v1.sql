@@ -0,0 +1,12 @@
+-- A database at schema version 1.
+CREATE TABLE schema_version
+(version INTEGER PRIMARY KEY, name TEXT NOT NULL, applied_at INTEGER NOT NULL);
+INSERT INTO schema_version (version, name, applied_at) VALUES (1, '0001_create_leaderboard', 0);
+
+CREATE TABLE leaderboard
+(id INTEGER PRIMARY KEY, game_mode TEXT, name TEXT, time INTEGER, score INTEGER, lines INTEGER, level INTEGER);
+
+INSERT INTO leaderboard (game_mode, name, time, score, lines, level)
+VALUES ('marathon', 'Alice', 60000000000, 1000, 10, 3),
+    ('Sprint', 'Finished', 90000000000, 800, 40, 2),
+    ('Sprint', 'Aborted', 30000000000, 200, 9, 1);
@@ -0,0 +1,8 @@
+-- A database created before schema versions were tracked, by releases which only had the original leaderboard.
+CREATE TABLE leaderboard
+(id INTEGER PRIMARY KEY, game_mode TEXT, name TEXT, time INTEGER, score INTEGER, lines INTEGER, level INTEGER);
+
+INSERT INTO leaderboard (game_mode, name, time, score, lines, level)
+VALUES ('marathon', 'Alice', 60000000000, 1000, 10, 3),
+    ('Sprint', 'Finished', 90000000000, 800, 40, 2),
+    ('Sprint', 'Aborted', 30000000000, 200, 9, 1);
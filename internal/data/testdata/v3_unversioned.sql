@@ -0,0 +1,16 @@
+-- A database created before schema versions were tracked, by builds which added the completed column.
+CREATE TABLE leaderboard
+(id INTEGER PRIMARY KEY, game_mode TEXT, name TEXT, time INTEGER, score INTEGER, lines INTEGER, level INTEGER);
+ALTER TABLE leaderboard ADD COLUMN pieces INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE leaderboard ADD COLUMN pps REAL NOT NULL DEFAULT 0;
+ALTER TABLE leaderboard ADD COLUMN max_combo INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE leaderboard ADD COLUMN t_spins INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE leaderboard ADD COLUMN mini_t_spins INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE leaderboard ADD COLUMN tetrises INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE leaderboard ADD COLUMN completed INTEGER NOT NULL DEFAULT 0;
+
+INSERT INTO leaderboard (game_mode, name, time, score, lines, level)
+VALUES ('marathon', 'Alice', 60000000000, 1000, 10, 3),
+    ('Sprint', 'Finished', 90000000000, 800, 40, 2),
+    ('Sprint', 'Aborted', 30000000000, 200, 9, 1);
+UPDATE leaderboard SET completed = 1 WHERE name = 'Finished';
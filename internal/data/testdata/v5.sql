@@ -0,0 +1,26 @@
+-- A database at schema version 5.
+CREATE TABLE schema_version
+(version INTEGER PRIMARY KEY, name TEXT NOT NULL, applied_at INTEGER NOT NULL);
+INSERT INTO schema_version (version, name, applied_at)
+VALUES (1, '0001_create_leaderboard', 0), (2, '0002_add_leaderboard_stats', 0),
+    (3, '0003_add_leaderboard_completed', 0), (4, '0004_add_leaderboard_start_level_and_created_at', 0),
+    (5, '0005_add_leaderboard_replay', 0);
+
+CREATE TABLE leaderboard
+(id INTEGER PRIMARY KEY, game_mode TEXT, name TEXT, time INTEGER, score INTEGER, lines INTEGER, level INTEGER);
+ALTER TABLE leaderboard ADD COLUMN pieces INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE leaderboard ADD COLUMN pps REAL NOT NULL DEFAULT 0;
+ALTER TABLE leaderboard ADD COLUMN max_combo INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE leaderboard ADD COLUMN t_spins INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE leaderboard ADD COLUMN mini_t_spins INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE leaderboard ADD COLUMN tetrises INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE leaderboard ADD COLUMN completed INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE leaderboard ADD COLUMN start_level INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE leaderboard ADD COLUMN created_at INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE leaderboard ADD COLUMN replay BLOB;
+
+INSERT INTO leaderboard (game_mode, name, time, score, lines, level)
+VALUES ('marathon', 'Alice', 60000000000, 1000, 10, 3),
+    ('Sprint', 'Finished', 90000000000, 800, 40, 2),
+    ('Sprint', 'Aborted', 30000000000, 200, 9, 1);
+UPDATE leaderboard SET completed = 1 WHERE name = 'Finished';
@@ -20,7 +20,7 @@ func setupTestDB(t *testing.T) *sql.DB {
 		}
 	})
 
-	err = data.EnsureTablesExist(context.Background(), db)
+	err = data.Migrate(context.Background(), db)
 	require.NoError(t, err)
 
 	return db
@@ -0,0 +1,167 @@
+package data
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// ExportVersion is the current version of the JSON export format.
+const ExportVersion = 1
+
+// export is the JSON document written by WriteScoresJSON.
+type export struct {
+	Version int     `json:"version"`
+	Scores  []Score `json:"scores"`
+}
+
+// csvHeader is the header row of the CSV export format, with one column for each exported field of a Score.
+var csvHeader = []string{
+	"game_mode", "name", "time", "score", "lines", "level", "completed", "start_level", "created_at",
+	"pieces", "pps", "max_combo", "t_spins", "mini_t_spins", "tetrises",
+}
+
+// WriteScoresJSON writes the scores as a JSON document which can be read by ReadScoresJSON.
+func WriteScoresJSON(w io.Writer, scores []Score) error {
+	if scores == nil {
+		scores = []Score{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(export{Version: ExportVersion, Scores: scores})
+}
+
+// ReadScoresJSON reads the scores written by WriteScoresJSON.
+func ReadScoresJSON(r io.Reader) ([]Score, error) {
+	var e export
+	if err := json.NewDecoder(r).Decode(&e); err != nil {
+		return nil, fmt.Errorf("decoding JSON: %w", err)
+	}
+	if e.Version != ExportVersion {
+		return nil, fmt.Errorf("unsupported export version %d (expected %d)", e.Version, ExportVersion)
+	}
+	return e.Scores, nil
+}
+
+// WriteScoresCSV writes the scores as CSV, with a header row, which can be read by ReadScoresCSV.
+// Times played are written as durations (eg. "1m30.5s") and creation times in RFC 3339 format, or empty if unknown.
+// The IDs, splits and replays of the scores are only included in the JSON format.
+func WriteScoresCSV(w io.Writer, scores []Score) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, s := range scores {
+		var createdAt string
+		if !s.CreatedAt.IsZero() {
+			createdAt = s.CreatedAt.Format(time.RFC3339)
+		}
+		err := cw.Write([]string{
+			s.GameMode,
+			s.Name,
+			s.Time.String(),
+			strconv.Itoa(s.Score),
+			strconv.Itoa(s.Lines),
+			strconv.Itoa(s.Level),
+			strconv.FormatBool(s.Completed),
+			strconv.Itoa(s.StartLevel),
+			createdAt,
+			strconv.Itoa(s.Pieces),
+			strconv.FormatFloat(s.PPS, 'f', -1, 64),
+			strconv.Itoa(s.MaxCombo),
+			strconv.Itoa(s.TSpins),
+			strconv.Itoa(s.MiniTSpins),
+			strconv.Itoa(s.Tetrises),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadScoresCSV reads the scores written by WriteScoresCSV. The header row must match exactly.
+func ReadScoresCSV(r io.Reader) ([]Score, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = len(csvHeader)
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("missing header row")
+	} else if err != nil {
+		return nil, err
+	}
+	if !slices.Equal(header, csvHeader) {
+		return nil, fmt.Errorf("unexpected header row %q", header)
+	}
+
+	var scores []Score
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		s, err := parseCSVRecord(record)
+		if err != nil {
+			line, _ := cr.FieldPos(0)
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		scores = append(scores, s)
+	}
+	return scores, nil
+}
+
+func parseCSVRecord(record []string) (Score, error) {
+	s := Score{
+		GameMode: record[0],
+		Name:     record[1],
+	}
+
+	// The errors of each field are collected so that they can be reported together.
+	var errs []error
+	parseInt := func(column int) int {
+		v, err := strconv.Atoi(record[column])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s %q", csvHeader[column], record[column]))
+		}
+		return v
+	}
+
+	var err error
+	if s.Time, err = time.ParseDuration(record[2]); err != nil {
+		errs = append(errs, fmt.Errorf("invalid time %q", record[2]))
+	}
+	s.Score = parseInt(3)
+	s.Lines = parseInt(4)
+	s.Level = parseInt(5)
+	if s.Completed, err = strconv.ParseBool(record[6]); err != nil {
+		errs = append(errs, fmt.Errorf("invalid completed %q", record[6]))
+	}
+	s.StartLevel = parseInt(7)
+	if record[8] != "" {
+		if s.CreatedAt, err = time.Parse(time.RFC3339, record[8]); err != nil {
+			errs = append(errs, fmt.Errorf("invalid created_at %q", record[8]))
+		}
+	}
+	s.Pieces = parseInt(9)
+	if s.PPS, err = strconv.ParseFloat(record[10], 64); err != nil {
+		errs = append(errs, fmt.Errorf("invalid pps %q", record[10]))
+	}
+	s.MaxCombo = parseInt(11)
+	s.TSpins = parseInt(12)
+	s.MiniTSpins = parseInt(13)
+	s.Tetrises = parseInt(14)
+
+	return s, errors.Join(errs...)
+}
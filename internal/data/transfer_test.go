@@ -0,0 +1,143 @@
+package data_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/data"
+	"github.com/Broderick-Westrope/tetrigo/internal/replay"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exampleScores() []data.Score {
+	return []data.Score{
+		{
+			GameMode:   "Sprint",
+			Name:       "Alice, \"the fast\"",
+			Time:       time.Minute + 30*time.Second + 123456789*time.Nanosecond,
+			Score:      5000,
+			Lines:      40,
+			Level:      5,
+			Completed:  true,
+			StartLevel: 1,
+			CreatedAt:  time.Date(2025, time.March, 1, 10, 0, 0, 0, time.UTC),
+			Pieces:     100,
+			PPS:        1.1,
+			MaxCombo:   3,
+			TSpins:     1,
+			MiniTSpins: 2,
+			Tetrises:   4,
+		},
+		{
+			GameMode: "Marathon",
+			Name:     "Bob",
+			Time:     2 * time.Minute,
+			Score:    9000,
+			Lines:    60,
+			Level:    7,
+		},
+	}
+}
+
+func TestScoresRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tt := map[string]struct {
+		write func(w *bytes.Buffer, scores []data.Score) error
+		read  func(r *bytes.Buffer) ([]data.Score, error)
+		// wantFull is whether the ID, splits and replay are exported.
+		wantFull bool
+	}{
+		"csv": {
+			write: func(w *bytes.Buffer, scores []data.Score) error { return data.WriteScoresCSV(w, scores) },
+			read:  func(r *bytes.Buffer) ([]data.Score, error) { return data.ReadScoresCSV(r) },
+		},
+		"json": {
+			write:    func(w *bytes.Buffer, scores []data.Score) error { return data.WriteScoresJSON(w, scores) },
+			read:     func(r *bytes.Buffer) ([]data.Score, error) { return data.ReadScoresJSON(r) },
+			wantFull: true,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// The rank is specific to a page of the leaderboard, so it isn't exported.
+			exported := exampleScores()
+			exported[0].ID, exported[0].Rank = 7, 3
+			exported[0].Splits = []time.Duration{20 * time.Second, 40 * time.Second, 65 * time.Second, 90 * time.Second}
+			exported[0].Replay = &replay.Replay{Version: replay.Version, Seed: [2]uint64{1, 2}, Mode: "Sprint"}
+
+			want := exampleScores()
+			if tc.wantFull {
+				want[0].ID, want[0].Splits, want[0].Replay = exported[0].ID, exported[0].Splits, exported[0].Replay
+			}
+
+			var buf bytes.Buffer
+			require.NoError(t, tc.write(&buf, exported))
+			got, err := tc.read(&buf)
+			require.NoError(t, err)
+
+			require.Len(t, got, len(want))
+			for i := range want {
+				assert.True(t, want[i].CreatedAt.Equal(got[i].CreatedAt))
+				got[i].CreatedAt = want[i].CreatedAt
+			}
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestReadScoresCSV_Invalid(t *testing.T) {
+	t.Parallel()
+
+	header := "game_mode,name,time,score,lines,level,completed,start_level,created_at," +
+		"pieces,pps,max_combo,t_spins,mini_t_spins,tetrises\n"
+	tt := map[string]struct {
+		input   string
+		wantErr string
+	}{
+		"empty": {
+			input:   "",
+			wantErr: "missing header row",
+		},
+		"wrong header": {
+			input:   "mode,name\n",
+			wantErr: "wrong number of fields",
+		},
+		"invalid number": {
+			input:   header + "Sprint,Alice,1s,many,40,5,true,1,,100,1.1,3,1,0,2\n",
+			wantErr: `line 2: invalid score "many"`,
+		},
+		"invalid time": {
+			input:   header + "Sprint,Alice,soon,5000,40,5,true,1,,100,1.1,3,1,0,2\n",
+			wantErr: `line 2: invalid time "soon"`,
+		},
+		"invalid created at": {
+			input:   header + "Sprint,Alice,1s,5000,40,5,true,1,yesterday,100,1.1,3,1,0,2\n",
+			wantErr: `line 2: invalid created_at "yesterday"`,
+		},
+		"missing fields": {
+			input:   header + "Sprint,Alice,1s\n",
+			wantErr: "wrong number of fields",
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, err := data.ReadScoresCSV(strings.NewReader(tc.input))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.wantErr)
+		})
+	}
+}
+
+func TestReadScoresJSON_UnsupportedVersion(t *testing.T) {
+	t.Parallel()
+	_, err := data.ReadScoresJSON(strings.NewReader(`{"version": 2, "scores": []}`))
+	assert.Error(t, err)
+}
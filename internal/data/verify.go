@@ -0,0 +1,72 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoReplay is returned by Score.Verify when the score has no replay to check it against.
+var ErrNoReplay = errors.New("score has no replay")
+
+// ReplayCheck is whether a score matched its Replay when it was saved to the leaderboard.
+type ReplayCheck int
+
+const (
+	// ReplayUnchecked means the score has no Replay, or was saved before scores were checked against their replays.
+	ReplayUnchecked ReplayCheck = iota
+	// ReplayMatches means the Replay reproduces the score.
+	ReplayMatches
+	// ReplayMismatch means the Replay does not reproduce the score, or could not be simulated.
+	ReplayMismatch
+)
+
+// Verify re-simulates the Replay of the score and checks that it reproduces the game mode, score, lines, level,
+// time and whether the game was completed.
+// It returns ErrNoReplay if the score has no replay, and an error describing each mismatch if it doesn't match.
+//
+// This only shows that the score is consistent with its Replay. Nothing is signed, so a score which was edited
+// along with its Replay still matches.
+func (s *Score) Verify() error {
+	if s.Replay == nil {
+		return ErrNoReplay
+	}
+	if !strings.EqualFold(s.Replay.Mode, s.GameMode) {
+		return fmt.Errorf("replay is for game mode %q, not %q", s.Replay.Mode, s.GameMode)
+	}
+
+	result, err := s.Replay.Simulate()
+	if err != nil {
+		return fmt.Errorf("simulating replay: %w", err)
+	}
+
+	var errs []error
+	if result.Score != s.Score {
+		errs = append(errs, fmt.Errorf("replay scored %d, not %d", result.Score, s.Score))
+	}
+	if result.Lines != s.Lines {
+		errs = append(errs, fmt.Errorf("replay cleared %d lines, not %d", result.Lines, s.Lines))
+	}
+	if result.Level != s.Level {
+		errs = append(errs, fmt.Errorf("replay reached level %d, not %d", result.Level, s.Level))
+	}
+	if result.Time != s.Time {
+		errs = append(errs, fmt.Errorf("replay lasted %s, not %s", result.Time, s.Time))
+	}
+	if result.Completed != s.Completed {
+		errs = append(errs, fmt.Errorf("replay completed is %t, not %t", result.Completed, s.Completed))
+	}
+	return errors.Join(errs...)
+}
+
+// CheckReplay returns whether the score matches its Replay, as checked by Verify.
+func (s *Score) CheckReplay() ReplayCheck {
+	switch err := s.Verify(); {
+	case err == nil:
+		return ReplayMatches
+	case errors.Is(err, ErrNoReplay):
+		return ReplayUnchecked
+	default:
+		return ReplayMismatch
+	}
+}
@@ -0,0 +1,217 @@
+package data_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/data"
+	"github.com/Broderick-Westrope/tetrigo/internal/replay"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newVerifiedScore returns a score along with the replay which reproduces it.
+func newVerifiedScore(t *testing.T) *data.Score {
+	t.Helper()
+
+	r := &replay.Replay{
+		Version:  replay.Version,
+		Seed:     [2]uint64{1, 2},
+		Mode:     "Marathon",
+		Username: "Alice",
+		Config: replay.Config{
+			Level:         1,
+			MaxLevel:      15,
+			IncreaseLevel: true,
+			LockDownMode:  "Extended",
+			GhostEnabled:  true,
+		},
+		Inputs: []replay.Input{
+			{Time: 100 * time.Millisecond, Kind: replay.InputHardDrop},
+			{Time: 200 * time.Millisecond, Kind: replay.InputMoveLeft},
+			{Time: 300 * time.Millisecond, Kind: replay.InputHardDrop},
+			{Time: 400 * time.Millisecond, Kind: replay.InputEndGame},
+		},
+	}
+	result, err := r.Simulate()
+	require.NoError(t, err)
+	r.Result = result
+
+	return &data.Score{
+		GameMode: "Marathon",
+		Name:     "Alice",
+		Time:     result.Time,
+		Score:    result.Score,
+		Lines:    result.Lines,
+		Level:    result.Level,
+		Replay:   r,
+	}
+}
+
+func TestScore_Verify(t *testing.T) {
+	t.Parallel()
+
+	tt := map[string]struct {
+		modify  func(s *data.Score)
+		wantErr error
+	}{
+		"matching replay": {
+			modify: func(_ *data.Score) {},
+		},
+		"different game mode case": {
+			modify: func(s *data.Score) { s.GameMode = "marathon" },
+		},
+		"tampered score": {
+			modify:  func(s *data.Score) { s.Score += 1000 },
+			wantErr: assert.AnError,
+		},
+		"tampered lines and level": {
+			modify: func(s *data.Score) {
+				s.Lines = 40
+				s.Level = 5
+			},
+			wantErr: assert.AnError,
+		},
+		"tampered time": {
+			modify:  func(s *data.Score) { s.Time -= 100 * time.Millisecond },
+			wantErr: assert.AnError,
+		},
+		"tampered completed": {
+			modify:  func(s *data.Score) { s.Completed = true },
+			wantErr: assert.AnError,
+		},
+		"completed time limit": {
+			modify: func(s *data.Score) {
+				s.Replay.Config.TimeLimit = 400 * time.Millisecond
+				s.Completed = true
+			},
+		},
+		"time limit not reached": {
+			modify: func(s *data.Score) {
+				s.Replay.Config.TimeLimit = 500 * time.Millisecond
+				s.Completed = true
+			},
+			wantErr: assert.AnError,
+		},
+		"different game mode": {
+			modify:  func(s *data.Score) { s.GameMode = "Sprint" },
+			wantErr: assert.AnError,
+		},
+		"invalid replay": {
+			modify: func(s *data.Score) {
+				s.Replay.Inputs[1].Kind = "unknown"
+			},
+			wantErr: assert.AnError,
+		},
+		"no replay": {
+			modify:  func(s *data.Score) { s.Replay = nil },
+			wantErr: data.ErrNoReplay,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s := newVerifiedScore(t)
+			tc.modify(s)
+
+			err := s.Verify()
+			switch tc.wantErr {
+			case nil:
+				assert.NoError(t, err)
+			case assert.AnError:
+				require.Error(t, err)
+				assert.NotErrorIs(t, err, data.ErrNoReplay)
+			default:
+				assert.ErrorIs(t, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestScore_VerifyAfterSave(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := setupTestDB(t)
+	repo := data.NewLeaderboardRepository(db)
+
+	want := newVerifiedScore(t)
+	id, err := repo.Save(ctx, want)
+	require.NoError(t, err)
+	_, err = repo.Save(ctx, &data.Score{GameMode: "Marathon", Name: "Bob", Score: 10})
+	require.NoError(t, err)
+
+	got, err := repo.Get(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, want.Replay, got.Replay)
+	assert.NoError(t, got.Verify())
+	assert.Equal(t, data.ReplayMatches, got.ReplayCheck)
+
+	scores, err := repo.All(ctx, "marathon")
+	require.NoError(t, err)
+	require.Len(t, scores, 2)
+	assert.Equal(t, want.Replay, scores[0].Replay)
+	assert.Equal(t, data.ReplayMatches, scores[0].ReplayCheck)
+	assert.Nil(t, scores[1].Replay)
+	assert.Equal(t, data.ReplayUnchecked, scores[1].ReplayCheck)
+
+	_, err = repo.Get(ctx, 100)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestLeaderboardRepository_ReplayCheck(t *testing.T) {
+	t.Parallel()
+
+	tt := map[string]struct {
+		modify func(s *data.Score)
+		want   data.ReplayCheck
+	}{
+		"matching replay": {
+			modify: func(_ *data.Score) {},
+			want:   data.ReplayMatches,
+		},
+		"tampered score": {
+			modify: func(s *data.Score) { s.Score += 1000 },
+			want:   data.ReplayMismatch,
+		},
+		"no replay": {
+			modify: func(s *data.Score) { s.Replay = nil },
+			want:   data.ReplayUnchecked,
+		},
+		"check is not trusted": {
+			modify: func(s *data.Score) {
+				s.Score += 1000
+				s.ReplayCheck = data.ReplayMatches
+			},
+			want: data.ReplayMismatch,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+
+			saved := newVerifiedScore(t)
+			tc.modify(saved)
+			repo := data.NewLeaderboardRepository(setupTestDB(t))
+			id, err := repo.Save(ctx, saved)
+			require.NoError(t, err)
+			got, err := repo.Get(ctx, id)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got.ReplayCheck, "saved")
+
+			imported := newVerifiedScore(t)
+			tc.modify(imported)
+			repo = data.NewLeaderboardRepository(setupTestDB(t))
+			_, err = repo.Import(ctx, []data.Score{*imported}, false)
+			require.NoError(t, err)
+			scores, err := repo.Scores(ctx)
+			require.NoError(t, err)
+			require.Len(t, scores, 1)
+			assert.Equal(t, tc.want, scores[0].ReplayCheck, "imported")
+		})
+	}
+}
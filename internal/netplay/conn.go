@@ -0,0 +1,96 @@
+package netplay
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// maxMessageSize is the largest encoded Message which can be received, so that a peer cannot make the receiver
+// buffer an unbounded amount of input.
+const maxMessageSize = 64 << 10
+
+// Conn sends and receives Messages over a network connection.
+// Send may be called concurrently with Receive.
+type Conn struct {
+	conn    net.Conn
+	scanner *bufio.Scanner
+
+	mu  sync.Mutex
+	enc *json.Encoder
+	// writeTimeout is how long Send waits for the Message to be written, or 0 to wait indefinitely.
+	writeTimeout time.Duration
+}
+
+func NewConn(c net.Conn) *Conn {
+	scanner := bufio.NewScanner(c)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxMessageSize)
+	return &Conn{
+		conn:    c,
+		scanner: scanner,
+		enc:     json.NewEncoder(c),
+	}
+}
+
+// Dial connects to the server at the given address and introduces the player with the given name.
+// Each Send fails if the server does not accept the Message within writeTimeout, so a stalled server cannot block
+// the client indefinitely.
+func Dial(ctx context.Context, addr, name string) (*Conn, error) {
+	var d net.Dialer
+	c, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("dialing server: %w", err)
+	}
+
+	conn := NewConn(c)
+	conn.writeTimeout = writeTimeout
+	err = conn.Send(Message{Type: MessageHello, Hello: &Hello{Version: ProtocolVersion, Name: name}})
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// Send writes the Message to the connection.
+func (c *Conn) Send(m Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.writeTimeout > 0 {
+		if err := c.conn.SetWriteDeadline(time.Now().Add(c.writeTimeout)); err != nil {
+			return fmt.Errorf("setting write deadline: %w", err)
+		}
+	}
+	if err := c.enc.Encode(m); err != nil {
+		return fmt.Errorf("sending %s message: %w", m.Type, err)
+	}
+	return nil
+}
+
+// Receive blocks until the next Message is read from the connection.
+// It fails if the Message is larger than maxMessageSize.
+func (c *Conn) Receive() (Message, error) {
+	if !c.scanner.Scan() {
+		err := c.scanner.Err()
+		if err == nil {
+			err = io.EOF
+		}
+		return Message{}, fmt.Errorf("receiving message: %w", err)
+	}
+
+	var m Message
+	if err := json.Unmarshal(c.scanner.Bytes(), &m); err != nil {
+		return Message{}, fmt.Errorf("decoding message: %w", err)
+	}
+	return m, nil
+}
+
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,100 @@
+package netplay
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPipeConns(t *testing.T) (net.Conn, *Conn) {
+	t.Helper()
+
+	client, server := net.Pipe()
+	conn := NewConn(server)
+	t.Cleanup(func() {
+		_ = client.Close()
+		_ = conn.Close()
+	})
+	return client, conn
+}
+
+func TestConn_Receive(t *testing.T) {
+	tt := map[string]struct {
+		line    string
+		wantErr bool
+	}{
+		"message": {
+			line: `{"type":"garbage","garbage":{"lines":2,"hole":3}}`,
+		},
+		"large message": {
+			line: `{"type":"error","error":"` + strings.Repeat("a", maxMessageSize/2) + `"}`,
+		},
+		"too large": {
+			line:    `{"type":"error","error":"` + strings.Repeat("a", maxMessageSize) + `"}`,
+			wantErr: true,
+		},
+		"invalid json": {
+			line:    `{"type":`,
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			client, conn := newPipeConns(t)
+			go func() {
+				_, _ = client.Write([]byte(tc.line + "\n"))
+			}()
+
+			require.NoError(t, conn.conn.SetReadDeadline(time.Now().Add(time.Second*5)))
+			_, err := conn.Receive()
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestConn_SendTimeout(t *testing.T) {
+	// Nothing reads from the other end of the pipe, so the write can never complete.
+	_, conn := newPipeConns(t)
+	conn.writeTimeout = time.Millisecond * 50
+
+	done := make(chan error, 1)
+	go func() {
+		done <- conn.Send(Message{Type: MessageOpponentLeft})
+	}()
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, os.ErrDeadlineExceeded)
+	case <-time.After(time.Second * 5):
+		t.Fatal("Timeout waiting for the send to fail")
+	}
+}
+
+func TestDial_WriteTimeout(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		c, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		_, _ = bufio.NewReader(c).ReadString('\n')
+	}()
+
+	conn := dial(t, ln.Addr().String(), "alice")
+	assert.Equal(t, writeTimeout, conn.writeTimeout)
+}
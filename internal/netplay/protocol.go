@@ -0,0 +1,114 @@
+package netplay
+
+import (
+	"fmt"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/replay"
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris"
+)
+
+// ProtocolVersion is the version of the wire protocol. The server rejects clients using a different version.
+const ProtocolVersion = 1
+
+// MessageType identifies the payload of a Message.
+type MessageType string
+
+const (
+	// MessageHello is sent by a client when it connects. It must be the first message.
+	MessageHello MessageType = "hello"
+	// MessageLobby is sent by the server to waiting clients whenever the lobby changes.
+	MessageLobby MessageType = "lobby"
+	// MessageStart is sent by the server to both clients when a match begins.
+	MessageStart MessageType = "start"
+	// MessageInput is relayed to the opponent for each input performed by a player.
+	MessageInput MessageType = "input"
+	// MessageSnapshot is relayed to the opponent whenever a player's board changes.
+	MessageSnapshot MessageType = "snapshot"
+	// MessageGarbage is relayed to the opponent when a player sends garbage lines.
+	MessageGarbage MessageType = "garbage"
+	// MessageGameOver is relayed to the opponent when a player's game ends.
+	MessageGameOver MessageType = "game_over"
+	// MessageOpponentLeft is sent by the server when the opponent disconnects.
+	MessageOpponentLeft MessageType = "opponent_left"
+	// MessageError is sent by the server when a request is rejected, before closing the connection.
+	MessageError MessageType = "error"
+)
+
+// Message is a single message of the protocol. Messages are encoded as JSON objects, one per line.
+// Only the payload field matching the Type is set.
+type Message struct {
+	Type     MessageType   `json:"type"`
+	Hello    *Hello        `json:"hello,omitempty"`
+	Lobby    *Lobby        `json:"lobby,omitempty"`
+	Start    *Start        `json:"start,omitempty"`
+	Input    *replay.Input `json:"input,omitempty"`
+	Snapshot *Snapshot     `json:"snapshot,omitempty"`
+	Garbage  *Garbage      `json:"garbage,omitempty"`
+	GameOver *GameOver     `json:"game_over,omitempty"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// Validate returns an error if the Message is of an unknown type, or is missing the payload for its type.
+// Messages from the network must be validated before their payload is used.
+func (m *Message) Validate() error {
+	var missing bool
+	switch m.Type {
+	case MessageHello:
+		missing = m.Hello == nil
+	case MessageLobby:
+		missing = m.Lobby == nil
+	case MessageStart:
+		missing = m.Start == nil
+	case MessageInput:
+		missing = m.Input == nil
+	case MessageSnapshot:
+		missing = m.Snapshot == nil
+	case MessageGarbage:
+		missing = m.Garbage == nil
+	case MessageGameOver:
+		missing = m.GameOver == nil
+	case MessageOpponentLeft, MessageError:
+	default:
+		return fmt.Errorf("unknown message type %q", m.Type)
+	}
+	if missing {
+		return fmt.Errorf("%s message is missing its payload", m.Type)
+	}
+	return nil
+}
+
+type Hello struct {
+	Version int    `json:"version"`
+	Name    string `json:"name"`
+}
+
+// Lobby contains the names of the players waiting for an opponent.
+type Lobby struct {
+	Players []string `json:"players"`
+}
+
+// Start begins a match. Both players receive the same seed so that their NextQueues are identical.
+type Start struct {
+	Seed     [2]uint64 `json:"seed"`
+	Opponent string    `json:"opponent"`
+}
+
+// Snapshot is the state of a player's board.
+type Snapshot struct {
+	Matrix         tetris.Matrix `json:"matrix"` // The visible Matrix, including the Tetrimino in play
+	Score          int           `json:"score"`
+	Lines          int           `json:"lines"`
+	Level          int           `json:"level"`
+	PendingGarbage int           `json:"pending_garbage"`
+	LinesSent      int           `json:"lines_sent"`
+}
+
+// Garbage is a number of garbage lines sharing the same hole column.
+type Garbage struct {
+	Lines int `json:"lines"`
+	Hole  int `json:"hole"`
+}
+
+type GameOver struct {
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,46 @@
+package netplay
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/replay"
+)
+
+func TestMessage_Validate(t *testing.T) {
+	tt := map[string]struct {
+		msg     Message
+		wantErr bool
+	}{
+		"hello":             {msg: Message{Type: MessageHello, Hello: &Hello{Name: "alice"}}},
+		"lobby":             {msg: Message{Type: MessageLobby, Lobby: &Lobby{}}},
+		"start":             {msg: Message{Type: MessageStart, Start: &Start{}}},
+		"input":             {msg: Message{Type: MessageInput, Input: &replay.Input{}}},
+		"snapshot":          {msg: Message{Type: MessageSnapshot, Snapshot: &Snapshot{}}},
+		"garbage":           {msg: Message{Type: MessageGarbage, Garbage: &Garbage{}}},
+		"game over":         {msg: Message{Type: MessageGameOver, GameOver: &GameOver{}}},
+		"opponent left":     {msg: Message{Type: MessageOpponentLeft}},
+		"error":             {msg: Message{Type: MessageError, Error: "failed"}},
+		"missing hello":     {msg: Message{Type: MessageHello}, wantErr: true},
+		"missing lobby":     {msg: Message{Type: MessageLobby}, wantErr: true},
+		"missing start":     {msg: Message{Type: MessageStart}, wantErr: true},
+		"missing input":     {msg: Message{Type: MessageInput}, wantErr: true},
+		"missing snapshot":  {msg: Message{Type: MessageSnapshot}, wantErr: true},
+		"missing garbage":   {msg: Message{Type: MessageGarbage}, wantErr: true},
+		"missing game over": {msg: Message{Type: MessageGameOver}, wantErr: true},
+		"wrong payload":     {msg: Message{Type: MessageGarbage, Snapshot: &Snapshot{}}, wantErr: true},
+		"unknown type":      {msg: Message{Type: "unknown"}, wantErr: true},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			err := tc.msg.Validate()
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
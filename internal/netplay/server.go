@@ -0,0 +1,275 @@
+package netplay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// helloTimeout is how long a client has to send MessageHello after connecting.
+	helloTimeout = time.Second * 10
+	// writeTimeout is how long a peer has to accept each Message sent to it before the connection fails.
+	writeTimeout = time.Second * 5
+	// outboxSize is the number of Messages which can be waiting to be sent to a player before they are disconnected.
+	outboxSize = 256
+)
+
+// Server pairs the players which connect to it into matches and relays Messages between opponents.
+// Players wait in a lobby until another player connects.
+type Server struct {
+	writeTimeout time.Duration
+
+	mu    sync.Mutex
+	lobby []*serverPlayer
+	// conns are all the open connections, including those which have not completed the handshake.
+	conns  map[*Conn]struct{}
+	closed bool
+}
+
+type serverPlayer struct {
+	conn     *Conn
+	name     string
+	opponent *serverPlayer
+	// outbox holds the Messages waiting to be sent to the player, so that a slow client cannot block the others.
+	// Messages are queued whilst holding Server.mu, which keeps them in order, and sent after it is released.
+	outbox chan Message
+}
+
+func NewServer() *Server {
+	return &Server{
+		writeTimeout: writeTimeout,
+		conns:        make(map[*Conn]struct{}),
+	}
+}
+
+// Serve accepts connections on the listener until the context is cancelled.
+// The listener and all connections are closed when Serve returns.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		<-ctx.Done()
+		_ = ln.Close()
+	}()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer s.closeAll()
+
+	for {
+		c, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("accepting connection: %w", err)
+		}
+
+		conn := NewConn(c)
+		conn.writeTimeout = s.writeTimeout
+		if !s.track(conn) {
+			_ = conn.Close()
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.handle(conn)
+		}()
+	}
+}
+
+// track records the open connection so it can be closed by closeAll.
+// It returns false if the server has already been closed.
+func (s *Server) track(conn *Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return false
+	}
+	s.conns[conn] = struct{}{}
+	return true
+}
+
+// untrack closes the connection and removes it from those closed by closeAll.
+func (s *Server) untrack(conn *Conn) {
+	s.mu.Lock()
+	delete(s.conns, conn)
+	s.mu.Unlock()
+
+	_ = conn.Close()
+}
+
+// handle performs the handshake with the client then relays its Messages until it disconnects.
+func (s *Server) handle(conn *Conn) {
+	defer s.untrack(conn)
+
+	p, err := s.handshake(conn)
+	if err != nil {
+		_ = conn.Send(Message{Type: MessageError, Error: err.Error()})
+		return
+	}
+
+	written := make(chan struct{})
+	go func() {
+		defer close(written)
+		s.write(p)
+	}()
+	defer func() { <-written }()
+
+	s.join(p)
+	defer s.leave(p)
+
+	for {
+		msg, err := conn.Receive()
+		if err != nil {
+			return
+		}
+
+		if err = msg.Validate(); err != nil {
+			s.reject(p, err.Error())
+			continue
+		}
+
+		switch msg.Type {
+		case MessageInput, MessageSnapshot, MessageGarbage, MessageGameOver:
+			s.relay(p, msg)
+		case MessageHello, MessageLobby, MessageStart, MessageOpponentLeft, MessageError:
+			s.reject(p, fmt.Sprintf("unexpected %s message", msg.Type))
+		}
+	}
+}
+
+func (s *Server) handshake(conn *Conn) (*serverPlayer, error) {
+	if err := conn.conn.SetReadDeadline(time.Now().Add(helloTimeout)); err != nil {
+		return nil, err
+	}
+	msg, err := conn.Receive()
+	if err != nil {
+		return nil, err
+	}
+	if err = conn.conn.SetReadDeadline(time.Time{}); err != nil {
+		return nil, err
+	}
+
+	if msg.Type != MessageHello || msg.Validate() != nil {
+		return nil, fmt.Errorf("expected %s message, got %s", MessageHello, msg.Type)
+	}
+	if msg.Hello.Version != ProtocolVersion {
+		return nil, fmt.Errorf("unsupported protocol version %d, the server uses version %d",
+			msg.Hello.Version, ProtocolVersion)
+	}
+	if msg.Hello.Name == "" {
+		return nil, errors.New("player name is required")
+	}
+	return &serverPlayer{conn: conn, name: msg.Hello.Name, outbox: make(chan Message, outboxSize)}, nil
+}
+
+// join adds the player to the lobby, starting a match if there is another player waiting.
+func (s *Server) join(p *serverPlayer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lobby = append(s.lobby, p)
+	if len(s.lobby) < 2 {
+		s.broadcastLobby()
+		return
+	}
+
+	a, b := s.lobby[0], s.lobby[1]
+	s.lobby = s.lobby[2:]
+	a.opponent, b.opponent = b, a
+
+	//nolint:gosec // This random source is not for any security-related tasks.
+	seed := [2]uint64{rand.Uint64(), rand.Uint64()}
+	s.enqueue(a, Message{Type: MessageStart, Start: &Start{Seed: seed, Opponent: b.name}})
+	s.enqueue(b, Message{Type: MessageStart, Start: &Start{Seed: seed, Opponent: a.name}})
+	s.broadcastLobby()
+}
+
+// leave removes the player from the lobby, or notifies their opponent if they were in a match.
+// No more Messages can be queued for the player afterwards.
+func (s *Server) leave(p *serverPlayer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if i := slices.Index(s.lobby, p); i >= 0 {
+		s.lobby = slices.Delete(s.lobby, i, i+1)
+		s.broadcastLobby()
+	}
+	if p.opponent != nil {
+		s.enqueue(p.opponent, Message{Type: MessageOpponentLeft})
+		p.opponent.opponent = nil
+		p.opponent = nil
+	}
+	close(p.outbox)
+}
+
+// relay sends the Message to the player's opponent, if they have one.
+func (s *Server) relay(p *serverPlayer, msg Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if p.opponent != nil {
+		s.enqueue(p.opponent, msg)
+	}
+}
+
+// reject sends an error to the player for a Message which could not be relayed.
+func (s *Server) reject(p *serverPlayer, reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.enqueue(p, Message{Type: MessageError, Error: reason})
+}
+
+// broadcastLobby sends the players waiting in the lobby to each of them. The caller must hold s.mu.
+func (s *Server) broadcastLobby() {
+	names := make([]string, len(s.lobby))
+	for i, p := range s.lobby {
+		names[i] = p.name
+	}
+	for _, p := range s.lobby {
+		s.enqueue(p, Message{Type: MessageLobby, Lobby: &Lobby{Players: names}})
+	}
+}
+
+// enqueue queues the Message to be sent to the player without blocking. The caller must hold s.mu.
+// A player whose outbox is full is not keeping up, so they are disconnected.
+func (s *Server) enqueue(p *serverPlayer, msg Message) {
+	select {
+	case p.outbox <- msg:
+	default:
+		_ = p.conn.Close()
+	}
+}
+
+// write sends the Messages queued for the player until leave is called.
+// A client which fails to accept a Message in time is disconnected, since the connection can no longer be used.
+func (s *Server) write(p *serverPlayer) {
+	for msg := range p.outbox {
+		if err := p.conn.Send(msg); err != nil {
+			_ = p.conn.Close()
+		}
+	}
+}
+
+// closeAll closes every open connection, including those which have not completed the handshake.
+func (s *Server) closeAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+}
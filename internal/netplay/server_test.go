@@ -0,0 +1,286 @@
+package netplay
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/replay"
+)
+
+// startServer runs a Server on a loopback listener until the test ends, returning its address.
+func startServer(t *testing.T) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- NewServer().Serve(ctx, ln)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+	})
+	return ln.Addr().String()
+}
+
+func dial(t *testing.T, addr, name string) *Conn {
+	t.Helper()
+
+	conn, err := Dial(context.Background(), addr, name)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func receive(t *testing.T, conn *Conn, want MessageType) Message {
+	t.Helper()
+
+	require.NoError(t, conn.conn.SetReadDeadline(time.Now().Add(time.Second*5)))
+	msg, err := conn.Receive()
+	require.NoError(t, err)
+	require.Equal(t, want, msg.Type, "error: %q", msg.Error)
+	return msg
+}
+
+// startMatch connects two players and waits for their match to start.
+func startMatch(t *testing.T, addr string) (*Conn, *Conn) {
+	t.Helper()
+
+	a := dial(t, addr, "alice")
+	msg := receive(t, a, MessageLobby)
+	assert.Equal(t, []string{"alice"}, msg.Lobby.Players)
+
+	b := dial(t, addr, "bob")
+	startA := receive(t, a, MessageStart)
+	startB := receive(t, b, MessageStart)
+	assert.Equal(t, "bob", startA.Start.Opponent)
+	assert.Equal(t, "alice", startB.Start.Opponent)
+	assert.Equal(t, startA.Start.Seed, startB.Start.Seed)
+	return a, b
+}
+
+func TestServer_Relay(t *testing.T) {
+	addr := startServer(t)
+	a, b := startMatch(t, addr)
+
+	tt := map[string]Message{
+		"input": {
+			Type:  MessageInput,
+			Input: &replay.Input{Time: time.Second, Kind: replay.InputHardDrop},
+		},
+		"snapshot": {
+			Type:     MessageSnapshot,
+			Snapshot: &Snapshot{Score: 100, Lines: 2, Level: 1, PendingGarbage: 3, LinesSent: 1},
+		},
+		"garbage": {
+			Type:    MessageGarbage,
+			Garbage: &Garbage{Lines: 4, Hole: 7},
+		},
+	}
+
+	for name, msg := range tt {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, a.Send(msg))
+			assert.Equal(t, msg, receive(t, b, msg.Type))
+
+			require.NoError(t, b.Send(msg))
+			assert.Equal(t, msg, receive(t, a, msg.Type))
+		})
+	}
+}
+
+func TestServer_InvalidMessage(t *testing.T) {
+	addr := startServer(t)
+	a, b := startMatch(t, addr)
+
+	tt := map[string]struct {
+		msg     Message
+		wantErr string
+	}{
+		"missing input":     {msg: Message{Type: MessageInput}, wantErr: "input message is missing its payload"},
+		"missing snapshot":  {msg: Message{Type: MessageSnapshot}, wantErr: "snapshot message is missing its payload"},
+		"missing garbage":   {msg: Message{Type: MessageGarbage}, wantErr: "garbage message is missing its payload"},
+		"missing game over": {msg: Message{Type: MessageGameOver}, wantErr: "game_over message is missing its payload"},
+		"unknown type":      {msg: Message{Type: "unknown"}, wantErr: `unknown message type "unknown"`},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, a.Send(tc.msg))
+			msg := receive(t, a, MessageError)
+			assert.Equal(t, tc.wantErr, msg.Error)
+
+			// The invalid Message is not relayed, so the next Message Bob receives is the valid one.
+			valid := Message{Type: MessageGarbage, Garbage: &Garbage{Lines: 1}}
+			require.NoError(t, a.Send(valid))
+			assert.Equal(t, valid, receive(t, b, MessageGarbage))
+		})
+	}
+}
+
+func TestServer_OpponentLeft(t *testing.T) {
+	addr := startServer(t)
+	a, b := startMatch(t, addr)
+
+	require.NoError(t, a.Close())
+	receive(t, b, MessageOpponentLeft)
+}
+
+func TestServer_LeaveLobby(t *testing.T) {
+	addr := startServer(t)
+
+	a := dial(t, addr, "alice")
+	receive(t, a, MessageLobby)
+
+	// Bob is paired with Carol since Alice left before they connected.
+	require.NoError(t, a.Close())
+	time.Sleep(time.Millisecond * 50)
+
+	b := dial(t, addr, "bob")
+	msg := receive(t, b, MessageLobby)
+	assert.Equal(t, []string{"bob"}, msg.Lobby.Players)
+
+	c := dial(t, addr, "carol")
+	msg = receive(t, c, MessageStart)
+	assert.Equal(t, "bob", msg.Start.Opponent)
+}
+
+func TestServer_Handshake(t *testing.T) {
+	tt := map[string]struct {
+		msg     Message
+		wantErr string
+	}{
+		"version mismatch": {
+			msg:     Message{Type: MessageHello, Hello: &Hello{Version: ProtocolVersion + 1, Name: "alice"}},
+			wantErr: "unsupported protocol version 2, the server uses version 1",
+		},
+		"missing name": {
+			msg:     Message{Type: MessageHello, Hello: &Hello{Version: ProtocolVersion}},
+			wantErr: "player name is required",
+		},
+		"not hello": {
+			msg:     Message{Type: MessageGarbage, Garbage: &Garbage{Lines: 1}},
+			wantErr: "expected hello message, got garbage",
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			addr := startServer(t)
+
+			c, err := net.Dial("tcp", addr)
+			require.NoError(t, err)
+			conn := NewConn(c)
+			t.Cleanup(func() { _ = conn.Close() })
+
+			require.NoError(t, conn.Send(tc.msg))
+			msg := receive(t, conn, MessageError)
+			assert.Equal(t, tc.wantErr, msg.Error)
+
+			_, err = conn.Receive()
+			require.Error(t, err)
+		})
+	}
+}
+
+// pipeListener is a net.Listener whose connections are synchronous in-memory pipes, so a client which does not
+// read blocks every write to it.
+type pipeListener struct {
+	conns     chan net.Conn
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newPipeListener() *pipeListener {
+	return &pipeListener{conns: make(chan net.Conn), done: make(chan struct{})}
+}
+
+func (l *pipeListener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.conns:
+		return c, nil
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *pipeListener) Close() error {
+	l.closeOnce.Do(func() { close(l.done) })
+	return nil
+}
+
+func (l *pipeListener) Addr() net.Addr {
+	return &net.UnixAddr{Name: "pipe", Net: "pipe"}
+}
+
+// dial connects a client to the listener.
+func (l *pipeListener) dial(t *testing.T) *Conn {
+	t.Helper()
+
+	client, server := net.Pipe()
+	l.conns <- server
+	conn := NewConn(client)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestServer_StalledClient(t *testing.T) {
+	ln := newPipeListener()
+	s := NewServer()
+	s.writeTimeout = time.Millisecond * 100
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Serve(ctx, ln)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	// Alice joins the lobby but never reads any messages.
+	a := ln.dial(t)
+	require.NoError(t, a.Send(Message{Type: MessageHello, Hello: &Hello{Version: ProtocolVersion, Name: "alice"}}))
+
+	// Bob's match starts without waiting for Alice, then Alice is disconnected once a write to her times out.
+	b := ln.dial(t)
+	require.NoError(t, b.Send(Message{Type: MessageHello, Hello: &Hello{Version: ProtocolVersion, Name: "bob"}}))
+	msg := receive(t, b, MessageStart)
+	assert.Equal(t, "alice", msg.Start.Opponent)
+	receive(t, b, MessageOpponentLeft)
+}
+
+func TestServer_ShutdownDuringHandshake(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- NewServer().Serve(ctx, ln)
+	}()
+
+	// The client connects but never sends MessageHello.
+	c, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+	time.Sleep(time.Millisecond * 50)
+
+	cancel()
+	select {
+	case err = <-done:
+		require.NoError(t, err)
+	case <-time.After(helloTimeout / 2):
+		t.Fatal("Timeout waiting for the server to close the connection")
+	}
+}
@@ -0,0 +1,95 @@
+package replay
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris/modes/single"
+)
+
+// Player re-drives a game using the Inputs of a Replay.
+type Player struct {
+	replay  *Replay
+	game    *single.Game
+	next    int
+	elapsed time.Duration
+}
+
+// NewPlayer creates a Player positioned at the start of the Replay.
+func NewPlayer(r *Replay) (*Player, error) {
+	p := &Player{replay: r}
+	if err := p.restart(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Game returns the game being driven by the Player.
+func (p *Player) Game() *single.Game {
+	return p.game
+}
+
+// Replay returns the Replay being played.
+func (p *Player) Replay() *Replay {
+	return p.replay
+}
+
+// Elapsed returns the current playback position.
+func (p *Player) Elapsed() time.Duration {
+	return p.elapsed
+}
+
+// IsFinished returns true if all Inputs have been applied.
+func (p *Player) IsFinished() bool {
+	return p.next >= len(p.replay.Inputs)
+}
+
+// Advance moves the playback position forward by the given duration,
+// applying any Inputs that occurred in that time.
+func (p *Player) Advance(d time.Duration) error {
+	p.elapsed += d
+	for !p.IsFinished() && p.replay.Inputs[p.next].Time <= p.elapsed {
+		in := p.replay.Inputs[p.next]
+		p.next++
+
+		if p.game.IsGameOver() {
+			continue
+		}
+		if _, err := Apply(p.game, in); err != nil {
+			return fmt.Errorf("applying input %d (%s): %w", p.next-1, in.Kind, err)
+		}
+	}
+
+	if p.IsFinished() {
+		p.elapsed = min(p.elapsed, p.replay.Duration())
+	}
+	return nil
+}
+
+// Seek moves the playback position to the given time.
+// Seeking backwards replays the game from the start since Inputs cannot be undone.
+func (p *Player) Seek(t time.Duration) error {
+	t = max(t, 0)
+	if t < p.elapsed {
+		if err := p.restart(); err != nil {
+			return err
+		}
+	}
+	return p.Advance(t - p.elapsed)
+}
+
+// PlayToEnd applies all remaining Inputs.
+func (p *Player) PlayToEnd() error {
+	return p.Seek(p.replay.Duration())
+}
+
+func (p *Player) restart() error {
+	game, err := p.replay.NewGame()
+	if err != nil {
+		return fmt.Errorf("creating game from replay: %w", err)
+	}
+	p.game = game
+	p.next = 0
+	p.elapsed = 0
+	return nil
+}
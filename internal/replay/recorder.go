@@ -0,0 +1,61 @@
+package replay
+
+import (
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris/modes/single"
+)
+
+// Recorder captures the Inputs of a game as it is played.
+type Recorder struct {
+	replay *Replay
+}
+
+// NewRecorder creates a Recorder for a game created with the given seed and config.
+func NewRecorder(seed [2]uint64, mode, username string, cfg Config) *Recorder {
+	return &Recorder{
+		replay: &Replay{
+			Version:  Version,
+			Seed:     seed,
+			Mode:     mode,
+			Username: username,
+			Config:   cfg,
+		},
+	}
+}
+
+// ResumeRecorder creates a Recorder which continues the given unfinished Replay, as returned by Recording.
+func ResumeRecorder(r *Replay) *Recorder {
+	return &Recorder{
+		replay: r,
+	}
+}
+
+// Recording returns the Replay recorded so far, without a Result.
+func (r *Recorder) Recording() *Replay {
+	return r.replay
+}
+
+// Record captures an Input of the given kind at the given time.
+func (r *Recorder) Record(at time.Duration, kind InputKind) {
+	r.replay.Inputs = append(r.replay.Inputs, Input{Time: at, Kind: kind})
+}
+
+// RecordGravity captures an InputGravity at the given time, along with the time elapsed
+// since the previous gravity Input.
+func (r *Recorder) RecordGravity(at, elapsed time.Duration) {
+	r.replay.Inputs = append(r.replay.Inputs, Input{Time: at, Kind: InputGravity, Elapsed: elapsed})
+}
+
+// Finish records the final state of the game and returns the completed Replay.
+func (r *Recorder) Finish(g *single.Game, at time.Duration) *Replay {
+	r.replay.Result = Result{
+		Score: g.GetTotalScore(),
+		Lines: g.GetLinesCleared(),
+		Level: g.GetLevel(),
+		Time:  at,
+
+		Completed: r.replay.isCompleted(g, at),
+	}
+	return r.replay
+}
@@ -0,0 +1,305 @@
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris"
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris/modes/single"
+)
+
+// Version is the current version of the replay file format. It must be incremented whenever the format changes,
+// including when a field is added to Config, since an older replay would otherwise be loaded with zero values and
+// play out differently.
+const Version = 3
+
+// Replay is a recording of a single player game which can be used to deterministically reproduce it.
+type Replay struct {
+	Version  int       `json:"version"`
+	Seed     [2]uint64 `json:"seed"`
+	Mode     string    `json:"mode"`
+	Username string    `json:"username"`
+	Config   Config    `json:"config"`
+	Inputs   []Input   `json:"inputs"`
+	Result   Result    `json:"result"`
+}
+
+// Config is a snapshot of the settings used to create the game.
+type Config struct {
+	Level         int    `json:"level"`
+	MaxLevel      int    `json:"max_level"`
+	IncreaseLevel bool   `json:"increase_level"`
+	EndOnMaxLevel bool   `json:"end_on_max_level"`
+	MaxLines      int    `json:"max_lines"`
+	EndOnMaxLines bool   `json:"end_on_max_lines"`
+	LockDownMode  string `json:"lock_down_mode"`
+	GhostEnabled  bool   `json:"ghost_enabled"`
+	// Randomizer is the name of the tetris.Randomizer. An empty value is a 7-bag.
+	Randomizer string `json:"randomizer,omitempty"`
+	// Width and Height are the size of the matrix. A value of 0 is the default size.
+	Width  int `json:"width,omitempty"`
+	Height int `json:"height,omitempty"`
+	// SoftDropFactor is how many times faster Soft Drop is. A value of 0 is the default factor.
+	SoftDropFactor int `json:"soft_drop_factor,omitempty"`
+	// GarbageLines is the number of garbage lines the matrix starts with.
+	GarbageLines        int  `json:"garbage_lines,omitempty"`
+	EndOnGarbageCleared bool `json:"end_on_garbage_cleared,omitempty"`
+	// LineGoal is the number of rows to clear before the game ends. A value of 0 is no goal.
+	LineGoal int `json:"line_goal,omitempty"`
+	// Master is whether the game is Master, using the timing of MasterSections.
+	Master         bool                   `json:"master,omitempty"`
+	MasterSections []tetris.MasterSection `json:"master_sections,omitempty"`
+	// TimeLimit is the length of an Ultra, which is completed once the time limit is reached.
+	// A value of 0 is no time limit.
+	TimeLimit time.Duration `json:"time_limit,omitempty"`
+
+	NextQueueLength int `json:"next_queue_length"`
+}
+
+// Result is the final state of the recorded game.
+type Result struct {
+	Score int           `json:"score"`
+	Lines int           `json:"lines"`
+	Level int           `json:"level"`
+	Time  time.Duration `json:"time"`
+	// Completed is whether the game ended by reaching its goal, rather than by topping out or being quit.
+	Completed bool `json:"completed"`
+}
+
+// Input is a single input to the game, timestamped relative to the start of the game (excluding time paused).
+type Input struct {
+	Time time.Duration `json:"t"`
+	Kind InputKind     `json:"k"`
+	// Elapsed is the time passed to the Lock Down timer. This is only used by InputGravity.
+	Elapsed time.Duration `json:"e,omitempty"`
+}
+
+// InputKind identifies the game operation performed by an Input.
+type InputKind string
+
+const (
+	InputMoveLeft        InputKind = "left"
+	InputMoveRight       InputKind = "right"
+	InputRotateClockwise InputKind = "cw"
+	InputRotateCounter   InputKind = "ccw"
+	InputToggleSoftDrop  InputKind = "soft_drop"
+	InputHardDrop        InputKind = "hard_drop"
+	InputHold            InputKind = "hold"
+	InputGravity         InputKind = "gravity"
+	InputGarbage         InputKind = "garbage"
+	InputEndGame         InputKind = "end"
+)
+
+const (
+	fileExtension  = ".json"
+	fileTimeFormat = "20060102T150405"
+)
+
+// NewConfig creates a Config snapshot from the given game input.
+func NewConfig(in *single.Input, nextQueueLength int) Config {
+	var randomizer string
+	if in.Randomizer != nil {
+		randomizer = in.Randomizer.String()
+	}
+
+	return Config{
+		Level:           in.Level,
+		MaxLevel:        in.MaxLevel,
+		IncreaseLevel:   in.IncreaseLevel,
+		EndOnMaxLevel:   in.EndOnMaxLevel,
+		MaxLines:        in.MaxLines,
+		EndOnMaxLines:   in.EndOnMaxLines,
+		LockDownMode:    in.LockDownMode.String(),
+		GhostEnabled:    in.GhostEnabled,
+		Randomizer:      randomizer,
+		Width:           in.Width,
+		Height:          in.Height,
+		SoftDropFactor:  in.SoftDropFactor,
+		NextQueueLength: nextQueueLength,
+
+		GarbageLines:        in.GarbageLines,
+		EndOnGarbageCleared: in.EndOnGarbageCleared,
+		LineGoal:            in.LineGoal,
+		Master:              in.Master,
+		MasterSections:      in.MasterSections,
+	}
+}
+
+// NewGame creates a new game using the seed and config of the Replay.
+func (r *Replay) NewGame() (*single.Game, error) {
+	lockDownMode, err := tetris.ParseLockDownMode(r.Config.LockDownMode)
+	if err != nil {
+		return nil, err
+	}
+
+	var randomizer tetris.Randomizer
+	if r.Config.Randomizer != "" {
+		randomizer, err = tetris.NewRandomizer(r.Config.Randomizer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return single.NewGame(&single.Input{
+		Level:          r.Config.Level,
+		MaxLevel:       r.Config.MaxLevel,
+		IncreaseLevel:  r.Config.IncreaseLevel,
+		EndOnMaxLevel:  r.Config.EndOnMaxLevel,
+		MaxLines:       r.Config.MaxLines,
+		EndOnMaxLines:  r.Config.EndOnMaxLines,
+		LockDownMode:   lockDownMode,
+		GhostEnabled:   r.Config.GhostEnabled,
+		Rand:           NewRand(r.Seed),
+		Randomizer:     randomizer,
+		Width:          r.Config.Width,
+		Height:         r.Config.Height,
+		SoftDropFactor: r.Config.SoftDropFactor,
+
+		GarbageLines:        r.Config.GarbageLines,
+		EndOnGarbageCleared: r.Config.EndOnGarbageCleared,
+		LineGoal:            r.Config.LineGoal,
+		Master:              r.Config.Master,
+		MasterSections:      r.Config.MasterSections,
+	})
+}
+
+// Simulate plays all the Inputs on a new game, without any delays, and returns the final state of the game.
+// The Time of the Result is the timestamp of the last Input.
+func (r *Replay) Simulate() (Result, error) {
+	p, err := NewPlayer(r)
+	if err != nil {
+		return Result{}, err
+	}
+	if err = p.PlayToEnd(); err != nil {
+		return Result{}, err
+	}
+
+	g := p.Game()
+	return Result{
+		Score: g.GetTotalScore(),
+		Lines: g.GetLinesCleared(),
+		Level: g.GetLevel(),
+		Time:  r.Duration(),
+
+		Completed: r.isCompleted(g, r.Duration()),
+	}, nil
+}
+
+// isCompleted returns whether the game ended by reaching the limit of its game mode, or the time limit if it has one.
+func (r *Replay) isCompleted(g *single.Game, at time.Duration) bool {
+	return g.GetGameOverReason() == single.GameOverLimitReached ||
+		(r.Config.TimeLimit > 0 && at >= r.Config.TimeLimit)
+}
+
+// Duration returns the timestamp of the last Input.
+func (r *Replay) Duration() time.Duration {
+	if len(r.Inputs) == 0 {
+		return 0
+	}
+	return r.Inputs[len(r.Inputs)-1].Time
+}
+
+// NewRand creates the random source used for Tetrimino generation from a seed.
+func NewRand(seed [2]uint64) *rand.Rand {
+	//nolint:gosec // This random source is not for any security-related tasks.
+	return rand.New(NewSource(seed))
+}
+
+// NewSource creates the source of NewRand from a seed.
+// Unlike a rand.Rand, the state of the source can be saved (see single.Input.Source).
+func NewSource(seed [2]uint64) *rand.PCG {
+	//nolint:gosec // This random source is not for any security-related tasks.
+	return rand.NewPCG(seed[0], seed[1])
+}
+
+// Apply performs the given Input on the game. If true is returned the game is over.
+func Apply(g *single.Game, in Input) (bool, error) {
+	switch in.Kind {
+	case InputMoveLeft:
+		g.MoveLeft()
+	case InputMoveRight:
+		g.MoveRight()
+	case InputRotateClockwise:
+		return false, g.Rotate(true)
+	case InputRotateCounter:
+		return false, g.Rotate(false)
+	case InputToggleSoftDrop:
+		g.ToggleSoftDrop()
+	case InputHardDrop:
+		return g.HardDrop()
+	case InputHold:
+		return g.Hold()
+	case InputGravity:
+		g.UpdateLockDown(in.Elapsed)
+		return g.TickLower()
+	case InputGarbage:
+		g.AddRandomGarbage(1)
+	case InputEndGame:
+		g.EndGame()
+		return true, nil
+	default:
+		return false, fmt.Errorf("unknown input kind %q", in.Kind)
+	}
+	return false, nil
+}
+
+// Save writes the Replay to a new file in the given directory and returns the path of the file.
+func Save(dir string, r *Replay) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("creating replay directory: %w", err)
+	}
+
+	name := fmt.Sprintf("%s_%s_%s%s",
+		time.Now().UTC().Format(fileTimeFormat),
+		strings.ToLower(r.Mode),
+		sanitizeFileName(r.Username),
+		fileExtension,
+	)
+	path := filepath.Join(dir, name)
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		return "", fmt.Errorf("encoding replay: %w", err)
+	}
+	if err = os.WriteFile(path, b, 0o600); err != nil {
+		return "", fmt.Errorf("writing replay file: %w", err)
+	}
+	return path, nil
+}
+
+// Load reads a Replay from the file at the given path.
+func Load(path string) (*Replay, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading replay file: %w", err)
+	}
+
+	r := new(Replay)
+	if err = json.Unmarshal(b, r); err != nil {
+		return nil, fmt.Errorf("decoding replay: %w", err)
+	}
+	if r.Version != Version {
+		return nil, fmt.Errorf("unsupported replay version %d (expected %d)", r.Version, Version)
+	}
+	if len(r.Inputs) == 0 {
+		return nil, errors.New("replay contains no inputs")
+	}
+	return r, nil
+}
+
+func sanitizeFileName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
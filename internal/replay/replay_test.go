@@ -0,0 +1,265 @@
+package replay
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris"
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris/modes/single"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSeed = [2]uint64{1, 2}
+
+func newTestGame(t *testing.T) (*single.Game, *single.Input) {
+	t.Helper()
+
+	in := &single.Input{
+		Level:         1,
+		MaxLevel:      15,
+		IncreaseLevel: true,
+		LockDownMode:  tetris.LockDownExtended,
+		GhostEnabled:  true,
+		Rand:          NewRand(testSeed),
+	}
+	g, err := single.NewGame(in)
+	require.NoError(t, err)
+	return g, in
+}
+
+// record plays the given inputs on a new game whilst recording them.
+func record(t *testing.T, kinds []InputKind) (*single.Game, *Replay) {
+	t.Helper()
+
+	g, in := newTestGame(t)
+	rec := NewRecorder(testSeed, "Marathon", "tester", NewConfig(in, 5))
+
+	var at time.Duration
+	for _, kind := range kinds {
+		at += 100 * time.Millisecond
+		in := Input{Time: at, Kind: kind}
+		if kind == InputGravity {
+			in.Elapsed = 100 * time.Millisecond
+			rec.RecordGravity(at, in.Elapsed)
+		} else {
+			rec.Record(at, kind)
+		}
+
+		gameOver, err := Apply(g, in)
+		require.NoError(t, err)
+		if gameOver {
+			break
+		}
+	}
+	return g, rec.Finish(g, at)
+}
+
+var testInputs = []InputKind{
+	InputMoveLeft, InputMoveLeft, InputHardDrop,
+	InputRotateClockwise, InputMoveRight, InputMoveRight, InputHardDrop,
+	InputHold, InputGravity, InputGravity, InputRotateCounter, InputHardDrop,
+	InputToggleSoftDrop, InputGravity, InputGravity, InputGravity, InputToggleSoftDrop,
+	InputMoveRight, InputMoveRight, InputMoveRight, InputMoveRight, InputHardDrop,
+	InputEndGame,
+}
+
+func TestPlayer_PlayToEnd(t *testing.T) {
+	want, r := record(t, testInputs)
+
+	p, err := NewPlayer(r)
+	require.NoError(t, err)
+	require.NoError(t, p.PlayToEnd())
+
+	assert.True(t, p.IsFinished())
+	assert.True(t, p.Game().IsGameOver())
+	assert.Equal(t, r.Result.Score, p.Game().GetTotalScore())
+	assert.Equal(t, r.Result.Lines, p.Game().GetLinesCleared())
+
+	wantMatrix, err := want.GetVisibleMatrix()
+	require.NoError(t, err)
+	gotMatrix, err := p.Game().GetVisibleMatrix()
+	require.NoError(t, err)
+	assert.Equal(t, wantMatrix, gotMatrix)
+}
+
+func TestReplay_Simulate(t *testing.T) {
+	_, r := record(t, testInputs)
+
+	got, err := r.Simulate()
+	require.NoError(t, err)
+	assert.Equal(t, r.Result, got)
+
+	r.Inputs = append(r.Inputs[:len(r.Inputs)-1], Input{Time: r.Duration(), Kind: "unknown"})
+	_, err = r.Simulate()
+	assert.Error(t, err)
+}
+
+func TestPlayer_Seek(t *testing.T) {
+	want, r := record(t, testInputs)
+
+	p, err := NewPlayer(r)
+	require.NoError(t, err)
+
+	require.NoError(t, p.Seek(r.Duration()/2))
+	assert.False(t, p.IsFinished())
+	assert.Equal(t, r.Duration()/2, p.Elapsed())
+
+	// Seeking backwards restarts the game.
+	require.NoError(t, p.Seek(0))
+	assert.Equal(t, time.Duration(0), p.Elapsed())
+	assert.Equal(t, 0, p.Game().GetTotalScore())
+
+	require.NoError(t, p.Seek(r.Duration()*2))
+	assert.True(t, p.IsFinished())
+	assert.Equal(t, r.Duration(), p.Elapsed())
+
+	wantMatrix, err := want.GetVisibleMatrix()
+	require.NoError(t, err)
+	gotMatrix, err := p.Game().GetVisibleMatrix()
+	require.NoError(t, err)
+	assert.Equal(t, wantMatrix, gotMatrix)
+}
+
+func TestSaveLoad(t *testing.T) {
+	_, r := record(t, testInputs)
+	r.Username = "some user/../name"
+
+	path, err := Save(t.TempDir(), r)
+	require.NoError(t, err)
+	assert.Contains(t, path, "_marathon_some_user____name.json")
+
+	got, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, r, got)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tt := map[string]struct {
+		modify func(r *Replay)
+	}{
+		"previous version": {
+			modify: func(r *Replay) { r.Version = Version - 1 },
+		},
+		"unsupported version": {
+			modify: func(r *Replay) { r.Version = Version + 1 },
+		},
+		"no inputs": {
+			modify: func(r *Replay) { r.Inputs = nil },
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			_, r := record(t, testInputs)
+			tc.modify(r)
+
+			path, err := Save(t.TempDir(), r)
+			require.NoError(t, err)
+			_, err = Load(path)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	tt := map[string]struct {
+		in           Input
+		wantGameOver bool
+		wantErr      bool
+	}{
+		"move left": {
+			in: Input{Kind: InputMoveLeft},
+		},
+		"garbage": {
+			in: Input{Kind: InputGarbage},
+		},
+		"end": {
+			in:           Input{Kind: InputEndGame},
+			wantGameOver: true,
+		},
+		"unknown": {
+			in:      Input{Kind: "jump"},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			g, _ := newTestGame(t)
+
+			gameOver, err := Apply(g, tc.in)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantGameOver, gameOver)
+		})
+	}
+}
+
+func TestReplay_SimulateGarbage(t *testing.T) {
+	in := &single.Input{
+		Level:               1,
+		LockDownMode:        tetris.LockDownExtended,
+		GarbageLines:        8,
+		EndOnGarbageCleared: true,
+		Rand:                NewRand(testSeed),
+	}
+	g, err := single.NewGame(in)
+	require.NoError(t, err)
+	rec := NewRecorder(testSeed, "Dig", "tester", NewConfig(in, 5))
+
+	kinds := []InputKind{InputHardDrop, InputGarbage, InputMoveLeft, InputHardDrop, InputGarbage, InputHardDrop}
+	var at time.Duration
+	for _, kind := range kinds {
+		at += time.Second
+		rec.Record(at, kind)
+		_, err = Apply(g, Input{Time: at, Kind: kind})
+		require.NoError(t, err)
+	}
+	r := rec.Finish(g, at)
+
+	p, err := NewPlayer(r)
+	require.NoError(t, err)
+	require.NoError(t, p.PlayToEnd())
+	assert.Equal(t, g.GetMatrix(), p.Game().GetMatrix())
+	assert.Equal(t, g.GetGarbageRemaining(), p.Game().GetGarbageRemaining())
+}
+
+func TestReplay_SimulateMaster(t *testing.T) {
+	in := &single.Input{
+		Master:         true,
+		MasterSections: []tetris.MasterSection{{Level: 0, ARE: time.Second, LockDelay: time.Second}},
+		Rand:           NewRand(testSeed),
+	}
+	g, err := single.NewGame(in)
+	require.NoError(t, err)
+	rec := NewRecorder(testSeed, "Master", "tester", NewConfig(in, 5))
+
+	var at time.Duration
+	apply := func(in Input) {
+		at += time.Second
+		in.Time = at
+		if in.Kind == InputGravity {
+			rec.RecordGravity(at, in.Elapsed)
+		} else {
+			rec.Record(at, in.Kind)
+		}
+		_, err = Apply(g, in)
+		require.NoError(t, err)
+	}
+	apply(Input{Kind: InputHardDrop})
+	apply(Input{Kind: InputMoveLeft})
+	apply(Input{Kind: InputGravity, Elapsed: time.Second})
+	apply(Input{Kind: InputMoveLeft})
+	apply(Input{Kind: InputHardDrop})
+	apply(Input{Kind: InputGravity, Elapsed: time.Second})
+	r := rec.Finish(g, at)
+	assert.Equal(t, 2, r.Result.Level, "a level should be gained each time the next Tetrimino spawns")
+
+	got, err := r.Simulate()
+	require.NoError(t, err)
+	assert.Equal(t, r.Result, got)
+}
@@ -0,0 +1,93 @@
+// Package savegame saves and loads single player games which are in progress, so they can be resumed later.
+package savegame
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/replay"
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris/modes/single"
+)
+
+// Version is the current version of the save file format. It must be incremented whenever a field is added or its
+// meaning changes, so that saves from older versions are rejected rather than resumed with missing values.
+const Version = 2
+
+// Save is a single player game which is in progress.
+type Save struct {
+	Version  int    `json:"version"`
+	Mode     string `json:"mode"`
+	Username string `json:"username"`
+	// StartLevel is the level the game started at.
+	StartLevel int `json:"start_level,omitempty"`
+	// LineGoal is the number of lines to clear in a Sprint, or 0 for the default goal.
+	LineGoal int `json:"line_goal,omitempty"`
+	// TimeLimit is the length of an Ultra, or 0 for the default time limit.
+	TimeLimit time.Duration `json:"time_limit,omitempty"`
+	// GarbageLines is the number of garbage lines a Dig started with, or 0 for the default number.
+	GarbageLines int `json:"garbage_lines,omitempty"`
+	// RisingGarbage is whether garbage keeps rising during a Dig.
+	RisingGarbage bool `json:"rising_garbage,omitempty"`
+	// Splits are the split times of a Sprint reached so far.
+	Splits []time.Duration `json:"splits,omitempty"`
+	// Elapsed is the time played so far, excluding time spent paused.
+	Elapsed time.Duration `json:"elapsed"`
+	Game    *single.State `json:"game"`
+	// Replay is the recording of the game so far, if it is being recorded.
+	Replay *replay.Replay `json:"replay,omitempty"`
+}
+
+// Write writes the Save to the file at the given path, replacing any existing save.
+func Write(path string, s *Save) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating save directory: %w", err)
+	}
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("encoding save: %w", err)
+	}
+	if err = os.WriteFile(path, b, 0o600); err != nil {
+		return fmt.Errorf("writing save file: %w", err)
+	}
+	return nil
+}
+
+// Load reads a Save from the file at the given path.
+func Load(path string) (*Save, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading save file: %w", err)
+	}
+
+	s := new(Save)
+	if err = json.Unmarshal(b, s); err != nil {
+		return nil, fmt.Errorf("decoding save: %w", err)
+	}
+	if s.Version != Version {
+		return nil, fmt.Errorf("unsupported save version %d (expected %d)", s.Version, Version)
+	}
+	if s.Game == nil {
+		return nil, errors.New("save contains no game")
+	}
+	return s, nil
+}
+
+// Remove deletes the save file at the given path. It is not an error if the file does not exist.
+func Remove(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("removing save file: %w", err)
+	}
+	return nil
+}
+
+// Exists returns true if there is a save file at the given path.
+func Exists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
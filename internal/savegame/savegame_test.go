@@ -0,0 +1,97 @@
+package savegame
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/replay"
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris/modes/single"
+)
+
+func newTestSave(t *testing.T) *Save {
+	t.Helper()
+
+	g, err := single.NewGame(&single.Input{Level: 1, Source: replay.NewSource([2]uint64{1, 2})})
+	require.NoError(t, err)
+	_, err = g.HardDrop()
+	require.NoError(t, err)
+	state, err := g.State()
+	require.NoError(t, err)
+
+	return &Save{
+		Version:    Version,
+		Mode:       "Sprint",
+		Username:   "tester",
+		StartLevel: 1,
+		LineGoal:   20,
+		Splits:     []time.Duration{45 * time.Second},
+		Elapsed:    90 * time.Second,
+		Game:       state,
+		Replay: &replay.Replay{
+			Version: replay.Version,
+			Seed:    [2]uint64{1, 2},
+			Inputs:  []replay.Input{{Time: time.Second, Kind: replay.InputHardDrop}},
+		},
+	}
+}
+
+func TestWriteLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "save.json")
+	want := newTestSave(t)
+
+	require.False(t, Exists(path))
+	require.NoError(t, Write(path, want))
+	require.True(t, Exists(path))
+
+	got, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	_, err = single.RestoreGame(got.Game)
+	require.NoError(t, err)
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tt := map[string]struct {
+		content string
+	}{
+		"invalid json": {
+			content: "{",
+		},
+		"previous version": {
+			content: `{"version": 1, "game": {}}`,
+		},
+		"unsupported version": {
+			content: `{"version": 3, "game": {}}`,
+		},
+		"missing game": {
+			content: `{"version": 2}`,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "save.json")
+			require.NoError(t, os.WriteFile(path, []byte(tc.content), 0o600))
+
+			_, err := Load(path)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRemove(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.json")
+	require.NoError(t, Write(path, newTestSave(t)))
+
+	require.NoError(t, Remove(path))
+	assert.False(t, Exists(path))
+
+	// Removing a save which does not exist is not an error.
+	require.NoError(t, Remove(path))
+}
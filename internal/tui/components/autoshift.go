@@ -0,0 +1,184 @@
+package components
+
+import (
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// autoShiftTickInterval is how often a held direction is checked for due shifts.
+	autoShiftTickInterval = time.Millisecond * 8
+	// legacyRepeatTimeout is how long a held key may go without a repeat before it is considered released, once it
+	// has repeated (see legacyFirstRepeatTimeout). It is only used when the terminal does not send key release events.
+	legacyRepeatTimeout = time.Millisecond * 100
+	// instantShifts is the number of shifts per tick when the ARR is 0. It is larger than any matrix is wide.
+	instantShifts = 64
+)
+
+var lastID int64
+
+// nextID returns a unique ID for identifying the tick messages of a component.
+func nextID() int {
+	return int(atomic.AddInt64(&lastID, 1))
+}
+
+// ShiftDirection is the horizontal direction a Tetrimino is being shifted in.
+type ShiftDirection int
+
+const (
+	ShiftNone = ShiftDirection(iota)
+	ShiftLeft
+	ShiftRight
+)
+
+// AutoShiftTickMsg is sent periodically whilst a direction is held.
+type AutoShiftTickMsg struct {
+	ID   int
+	Time time.Time
+	tag  int
+}
+
+// AutoShift implements Delayed Auto Shift (DAS) and Auto Repeat Rate (ARR).
+// A press shifts the Tetrimino once. Once the direction has been held for the DAS it shifts again every ARR,
+// independent of the key repeat rate of the terminal.
+//
+// When the terminal does not send key release events, presses arriving in quick succession are treated as the
+// terminal repeating a held key. Each of these shifts once until they have continued for the DAS, after which the
+// ARR takes over until the repeats stop. The DAS is counted from the original press, so the delay before the
+// terminal's first repeat does not add to it.
+type AutoShift struct {
+	das time.Duration
+	arr time.Duration
+
+	id  int
+	tag int
+
+	direction     ShiftDirection
+	pressedAt     time.Time
+	lastSeen      time.Time
+	shiftsDone    int
+	isAuto        bool
+	hasRepeated   bool
+	releaseEvents bool
+}
+
+func NewAutoShift(das, arr time.Duration) *AutoShift {
+	return &AutoShift{
+		das: das,
+		arr: arr,
+		id:  nextID(),
+	}
+}
+
+// ID returns the ID of the AutoShift, used to identify its AutoShiftTickMsg values.
+func (a *AutoShift) ID() int {
+	return a.id
+}
+
+// Direction returns the direction currently being shifted in.
+func (a *AutoShift) Direction() ShiftDirection {
+	return a.direction
+}
+
+// SetReleaseEvents sets whether the terminal sends key release events.
+func (a *AutoShift) SetReleaseEvents(enabled bool) {
+	a.releaseEvents = enabled
+}
+
+// Press handles a press or repeat of the key for the given direction at the given time.
+// It returns the number of shifts to perform now and a command to start ticking, if needed.
+func (a *AutoShift) Press(dir ShiftDirection, now time.Time) (int, tea.Cmd) {
+	if dir == ShiftNone {
+		return 0, nil
+	}
+
+	isRepeat := dir == a.direction &&
+		(a.releaseEvents || now.Sub(a.lastSeen) <= a.repeatTimeout())
+	if !isRepeat {
+		a.direction = dir
+		a.pressedAt = now
+		a.lastSeen = now
+		a.shiftsDone = 1
+		a.isAuto = false
+		a.hasRepeated = false
+		if a.releaseEvents {
+			return 1, a.startTicking()
+		}
+		a.tag++
+		return 1, nil
+	}
+
+	a.lastSeen = now
+	a.hasRepeated = true
+	if a.releaseEvents || a.isAuto {
+		return 0, nil
+	}
+	if now.Sub(a.pressedAt) < a.das {
+		a.shiftsDone++
+		return 1, nil
+	}
+	a.isAuto = true
+	return 0, a.startTicking()
+}
+
+// Release handles a release of the key for the given direction.
+func (a *AutoShift) Release(dir ShiftDirection) {
+	if dir == a.direction {
+		a.Stop()
+	}
+}
+
+// Stop stops shifting until the next press.
+func (a *AutoShift) Stop() {
+	a.direction = ShiftNone
+	a.isAuto = false
+	a.tag++
+}
+
+// Tick handles an AutoShiftTickMsg, returning the number of shifts which are due and a command for the next tick.
+func (a *AutoShift) Tick(msg AutoShiftTickMsg) (int, tea.Cmd) {
+	if msg.ID != a.id || msg.tag != a.tag || a.direction == ShiftNone {
+		return 0, nil
+	}
+
+	if !a.releaseEvents && msg.Time.Sub(a.lastSeen) > a.repeatTimeout() {
+		a.Stop()
+		return 0, nil
+	}
+
+	held := msg.Time.Sub(a.pressedAt)
+	if held < a.das {
+		return 0, a.tick()
+	}
+	if a.arr <= 0 {
+		return instantShifts, a.tick()
+	}
+
+	due := 2 + int((held-a.das)/a.arr)
+	shifts := max(due-a.shiftsDone, 0)
+	a.shiftsDone = max(due, a.shiftsDone)
+	return shifts, a.tick()
+}
+
+// repeatTimeout returns how long the key may go without a repeat before it is considered released.
+// Terminals wait longer before the first repeat than between the following ones.
+func (a *AutoShift) repeatTimeout() time.Duration {
+	if a.hasRepeated {
+		return legacyRepeatTimeout
+	}
+	return legacyFirstRepeatTimeout
+}
+
+func (a *AutoShift) startTicking() tea.Cmd {
+	a.tag++
+	return a.tick()
+}
+
+func (a *AutoShift) tick() tea.Cmd {
+	id, tag := a.id, a.tag
+	return tea.Tick(autoShiftTickInterval, func(t time.Time) tea.Msg {
+		return AutoShiftTickMsg{ID: id, Time: t, tag: tag}
+	})
+}
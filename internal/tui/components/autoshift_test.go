@@ -0,0 +1,179 @@
+package components
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutoShift(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(ms int) time.Time {
+		return start.Add(time.Duration(ms) * time.Millisecond)
+	}
+
+	type step struct {
+		press   ShiftDirection // A press of this direction, if not ShiftNone.
+		release ShiftDirection // A release of this direction, if not ShiftNone.
+		tick    bool           // A tick, if true.
+		at      int            // The time of the step in milliseconds.
+
+		wantShifts  int
+		wantTicking bool
+	}
+
+	tt := map[string]struct {
+		das, arr      time.Duration
+		releaseEvents bool
+		steps         []step
+		wantDirection ShiftDirection
+	}{
+		"tap": {
+			das: 100 * time.Millisecond, arr: 20 * time.Millisecond, releaseEvents: true,
+			steps: []step{
+				{press: ShiftLeft, at: 0, wantShifts: 1, wantTicking: true},
+				{tick: true, at: 50, wantShifts: 0, wantTicking: true},
+				{release: ShiftLeft, at: 60},
+				{tick: true, at: 200, wantShifts: 0, wantTicking: false},
+			},
+			wantDirection: ShiftNone,
+		},
+		"hold": {
+			das: 100 * time.Millisecond, arr: 20 * time.Millisecond, releaseEvents: true,
+			steps: []step{
+				{press: ShiftRight, at: 0, wantShifts: 1, wantTicking: true},
+				{tick: true, at: 99, wantShifts: 0, wantTicking: true},
+				{tick: true, at: 100, wantShifts: 1, wantTicking: true},
+				{tick: true, at: 110, wantShifts: 0, wantTicking: true},
+				{tick: true, at: 165, wantShifts: 3, wantTicking: true},
+			},
+			wantDirection: ShiftRight,
+		},
+		"key repeats are ignored": {
+			das: 100 * time.Millisecond, arr: 20 * time.Millisecond, releaseEvents: true,
+			steps: []step{
+				{press: ShiftLeft, at: 0, wantShifts: 1, wantTicking: true},
+				{press: ShiftLeft, at: 30, wantShifts: 0, wantTicking: false},
+				{press: ShiftLeft, at: 60, wantShifts: 0, wantTicking: false},
+				{tick: true, at: 120, wantShifts: 2, wantTicking: true},
+			},
+			wantDirection: ShiftLeft,
+		},
+		"zero ARR": {
+			das: 100 * time.Millisecond, arr: 0, releaseEvents: true,
+			steps: []step{
+				{press: ShiftLeft, at: 0, wantShifts: 1, wantTicking: true},
+				{tick: true, at: 100, wantShifts: instantShifts, wantTicking: true},
+			},
+			wantDirection: ShiftLeft,
+		},
+		"change direction": {
+			das: 100 * time.Millisecond, arr: 20 * time.Millisecond, releaseEvents: true,
+			steps: []step{
+				{press: ShiftLeft, at: 0, wantShifts: 1, wantTicking: true},
+				{press: ShiftRight, at: 90, wantShifts: 1, wantTicking: true},
+				{release: ShiftLeft, at: 95},
+				{tick: true, at: 150, wantShifts: 0, wantTicking: true},
+				{tick: true, at: 190, wantShifts: 1, wantTicking: true},
+			},
+			wantDirection: ShiftRight,
+		},
+		"legacy taps": {
+			das: 100 * time.Millisecond, arr: 20 * time.Millisecond,
+			steps: []step{
+				{press: ShiftLeft, at: 0, wantShifts: 1, wantTicking: false},
+				{press: ShiftLeft, at: 600, wantShifts: 1, wantTicking: false},
+				{press: ShiftLeft, at: 1200, wantShifts: 1, wantTicking: false},
+			},
+			wantDirection: ShiftLeft,
+		},
+		"legacy first repeat delay": {
+			das: 167 * time.Millisecond, arr: 33 * time.Millisecond,
+			steps: []step{
+				{press: ShiftLeft, at: 0, wantShifts: 1, wantTicking: false},
+				// The first repeat arrives after the DAS, so the ARR takes over immediately.
+				{press: ShiftLeft, at: 500, wantShifts: 0, wantTicking: true},
+				{tick: true, at: 500, wantShifts: 11, wantTicking: true},
+				{press: ShiftLeft, at: 533, wantShifts: 0, wantTicking: false},
+				{tick: true, at: 541, wantShifts: 1, wantTicking: true},
+				{press: ShiftLeft, at: 566, wantShifts: 0, wantTicking: false},
+				{tick: true, at: 574, wantShifts: 1, wantTicking: true},
+			},
+			wantDirection: ShiftLeft,
+		},
+		"legacy no first repeat": {
+			das: 167 * time.Millisecond, arr: 33 * time.Millisecond,
+			steps: []step{
+				{press: ShiftLeft, at: 0, wantShifts: 1, wantTicking: false},
+				{press: ShiftLeft, at: 551, wantShifts: 1, wantTicking: false},
+			},
+			wantDirection: ShiftLeft,
+		},
+		"legacy hold": {
+			das: 100 * time.Millisecond, arr: 20 * time.Millisecond,
+			steps: []step{
+				{press: ShiftLeft, at: 0, wantShifts: 1, wantTicking: false},
+				{press: ShiftLeft, at: 50, wantShifts: 1, wantTicking: false},
+				{press: ShiftLeft, at: 100, wantShifts: 0, wantTicking: true},
+				{tick: true, at: 110, wantShifts: 0, wantTicking: true},
+				{tick: true, at: 145, wantShifts: 2, wantTicking: true},
+				{press: ShiftLeft, at: 150, wantShifts: 0, wantTicking: false},
+				{tick: true, at: 160, wantShifts: 1, wantTicking: true},
+			},
+			wantDirection: ShiftLeft,
+		},
+		"legacy repeats stop": {
+			das: 100 * time.Millisecond, arr: 20 * time.Millisecond,
+			steps: []step{
+				{press: ShiftLeft, at: 0, wantShifts: 1, wantTicking: false},
+				{press: ShiftLeft, at: 50, wantShifts: 1, wantTicking: false},
+				{press: ShiftLeft, at: 100, wantShifts: 0, wantTicking: true},
+				{tick: true, at: 250, wantShifts: 0, wantTicking: false},
+			},
+			wantDirection: ShiftNone,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			a := NewAutoShift(tc.das, tc.arr)
+			a.SetReleaseEvents(tc.releaseEvents)
+
+			for i, s := range tc.steps {
+				switch {
+				case s.press != ShiftNone:
+					shifts, cmd := a.Press(s.press, at(s.at))
+					assert.Equal(t, s.wantShifts, shifts, "step %d", i)
+					assert.Equal(t, s.wantTicking, cmd != nil, "step %d", i)
+				case s.release != ShiftNone:
+					a.Release(s.release)
+				case s.tick:
+					shifts, cmd := a.Tick(AutoShiftTickMsg{ID: a.ID(), Time: at(s.at), tag: a.tag})
+					assert.Equal(t, s.wantShifts, shifts, "step %d", i)
+					assert.Equal(t, s.wantTicking, cmd != nil, "step %d", i)
+				}
+			}
+			assert.Equal(t, tc.wantDirection, a.Direction())
+		})
+	}
+}
+
+func TestAutoShift_StaleTick(t *testing.T) {
+	a := NewAutoShift(0, time.Millisecond)
+	a.SetReleaseEvents(true)
+	start := time.Now()
+
+	_, _ = a.Press(ShiftLeft, start)
+	stale := AutoShiftTickMsg{ID: a.ID(), Time: start.Add(time.Second), tag: a.tag}
+	a.Stop()
+	_, _ = a.Press(ShiftLeft, start)
+
+	shifts, cmd := a.Tick(stale)
+	assert.Zero(t, shifts)
+	assert.Nil(t, cmd)
+
+	shifts, cmd = a.Tick(AutoShiftTickMsg{ID: a.ID() + 1, Time: start.Add(time.Second), tag: a.tag})
+	assert.Zero(t, shifts)
+	assert.Nil(t, cmd)
+}
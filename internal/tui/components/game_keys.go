@@ -35,6 +35,28 @@ func ConstructGameKeyMap(keys *config.Keys) *GameKeyMap {
 	}
 }
 
+// ConstructPlayerKeyMap creates the GameKeyMap for one player of a local versus game.
+// The game controls come from the player's keys and the remaining controls are shared.
+func ConstructPlayerKeyMap(keys *config.Keys, player *config.PlayerKeys) *GameKeyMap {
+	return &GameKeyMap{
+		ForceQuit:        charmutils.ConstructKeyBinding(keys.ForceQuit, "force quit"),
+		Exit:             charmutils.ConstructKeyBinding(keys.Exit, "exit"),
+		Help:             charmutils.ConstructKeyBinding(keys.Help, "help"),
+		Left:             charmutils.ConstructKeyBinding(player.Left, "move left"),
+		Right:            charmutils.ConstructKeyBinding(player.Right, "move right"),
+		Clockwise:        charmutils.ConstructKeyBinding(player.RotateClockwise, "rotate clockwise"),
+		CounterClockwise: charmutils.ConstructKeyBinding(player.RotateCounterClockwise, "rotate counter-clockwise"),
+		SoftDrop:         charmutils.ConstructKeyBinding(player.Down, "toggle soft drop"),
+		HardDrop:         charmutils.ConstructKeyBinding(player.Up, "hard drop"),
+		Hold:             charmutils.ConstructKeyBinding(player.Hold, "hold"),
+	}
+}
+
+// UseHeldSoftDrop updates the help of the soft drop binding for when soft drop lasts whilst the key is held.
+func (k *GameKeyMap) UseHeldSoftDrop() {
+	k.SoftDrop.SetHelp(k.SoftDrop.Help().Key, "soft drop")
+}
+
 func (k *GameKeyMap) ShortHelp() []key.Binding {
 	return []key.Binding{
 		k.Exit,
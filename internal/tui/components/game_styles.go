@@ -3,6 +3,8 @@ package components
 import (
 	"github.com/Broderick-Westrope/tetrigo/internal/config"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris"
 )
 
 type GameStyles struct {
@@ -14,6 +16,9 @@ type GameStyles struct {
 	Information         lipgloss.Style
 	RowIndicator        lipgloss.Style
 	Bag                 lipgloss.Style
+	GarbageMeter        lipgloss.Style
+	Winner              lipgloss.Style
+	Callout             lipgloss.Style
 	CellChar            cellCharacters
 }
 
@@ -41,6 +46,8 @@ func CreateGameStyles(theme *config.Theme) *GameStyles {
 			'Z': lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Colours.TetriminoCells.Z)),
 			'J': lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Colours.TetriminoCells.J)),
 			'L': lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Colours.TetriminoCells.L)),
+
+			tetris.GarbageCell: lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Colours.GarbageCell)),
 		},
 		GhostCell: lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Colours.GhostCell)),
 		Hold: holdStyles{
@@ -53,7 +60,12 @@ func CreateGameStyles(theme *config.Theme) *GameStyles {
 		Information: lipgloss.NewStyle().Width(13).Align(lipgloss.Left, lipgloss.Top),
 		RowIndicator: lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Characters.EmptyCell)).
 			Align(lipgloss.Left).Padding(0, 1, 0),
-		Bag: lipgloss.NewStyle().PaddingTop(1),
+		Bag:          lipgloss.NewStyle().PaddingTop(1),
+		GarbageMeter: lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Colours.TetriminoCells.Z)),
+		Winner: lipgloss.NewStyle().Border(lipgloss.DoubleBorder()).Padding(0, 2).Bold(true).
+			Align(lipgloss.Center),
+		Callout: lipgloss.NewStyle().Foreground(lipgloss.Color(theme.Colours.TetriminoCells.O)).Bold(true).
+			Align(lipgloss.Center),
 		CellChar: cellCharacters{
 			Empty:      theme.Characters.EmptyCell,
 			Ghost:      theme.Characters.GhostCell,
@@ -0,0 +1,108 @@
+package components
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// keyHoldTickInterval is how often a held key is checked for a release when the terminal doesn't report them.
+	keyHoldTickInterval = time.Millisecond * 25
+	// legacyFirstRepeatTimeout is how long a pressed key may go without its first repeat before it is considered
+	// released. Terminals wait longer before the first repeat than between the following ones.
+	legacyFirstRepeatTimeout = time.Millisecond * 550
+)
+
+// KeyHoldTickMsg is sent periodically whilst a key is held on a terminal which doesn't report key releases.
+type KeyHoldTickMsg struct {
+	ID   int
+	Time time.Time
+	tag  int
+}
+
+// KeyHold tracks whether a key is held down.
+//
+// When the terminal does not send key release events, the key is considered released once the terminal stops
+// repeating it.
+type KeyHold struct {
+	id  int
+	tag int
+
+	isHeld        bool
+	hasRepeated   bool
+	lastSeen      time.Time
+	releaseEvents bool
+}
+
+func NewKeyHold() *KeyHold {
+	return &KeyHold{
+		id: nextID(),
+	}
+}
+
+// ID returns the ID of the KeyHold, used to identify its KeyHoldTickMsg values.
+func (h *KeyHold) ID() int {
+	return h.id
+}
+
+// IsHeld returns true if the key is held down.
+func (h *KeyHold) IsHeld() bool {
+	return h.isHeld
+}
+
+// SetReleaseEvents sets whether the terminal sends key release events.
+func (h *KeyHold) SetReleaseEvents(enabled bool) {
+	h.releaseEvents = enabled
+}
+
+// Press handles a press or repeat of the key at the given time.
+// It returns true if the key was not already held, and a command to check for the release of the key, if needed.
+func (h *KeyHold) Press(now time.Time) (bool, tea.Cmd) {
+	h.lastSeen = now
+	if h.isHeld {
+		h.hasRepeated = true
+		return false, nil
+	}
+
+	h.isHeld = true
+	h.hasRepeated = false
+	h.tag++
+	if h.releaseEvents {
+		return true, nil
+	}
+	return true, h.tick()
+}
+
+// Release handles a release of the key, returning true if it was held.
+func (h *KeyHold) Release() bool {
+	if !h.isHeld {
+		return false
+	}
+	h.isHeld = false
+	h.tag++
+	return true
+}
+
+// Tick handles a KeyHoldTickMsg, returning true if the key has been released and a command for the next tick.
+func (h *KeyHold) Tick(msg KeyHoldTickMsg) (bool, tea.Cmd) {
+	if msg.ID != h.id || msg.tag != h.tag || !h.isHeld {
+		return false, nil
+	}
+
+	timeout := legacyRepeatTimeout
+	if !h.hasRepeated {
+		timeout = legacyFirstRepeatTimeout
+	}
+	if msg.Time.Sub(h.lastSeen) > timeout {
+		return h.Release(), nil
+	}
+	return false, h.tick()
+}
+
+func (h *KeyHold) tick() tea.Cmd {
+	id, tag := h.id, h.tag
+	return tea.Tick(keyHoldTickInterval, func(t time.Time) tea.Msg {
+		return KeyHoldTickMsg{ID: id, Time: t, tag: tag}
+	})
+}
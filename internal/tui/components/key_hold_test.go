@@ -0,0 +1,95 @@
+package components
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyHold(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(ms int) time.Time {
+		return start.Add(time.Duration(ms) * time.Millisecond)
+	}
+
+	type step struct {
+		press   bool // A press of the key, if true.
+		release bool // A release of the key, if true.
+		tick    bool // A tick, if true.
+		at      int  // The time of the step in milliseconds.
+
+		wantChanged bool // Whether the press started or the tick/release ended the hold.
+		wantTicking bool
+	}
+
+	tt := map[string]struct {
+		releaseEvents bool
+		steps         []step
+		wantHeld      bool
+	}{
+		"release event": {
+			releaseEvents: true,
+			steps: []step{
+				{press: true, at: 0, wantChanged: true, wantTicking: false},
+				{press: true, at: 600, wantChanged: false, wantTicking: false},
+				{release: true, at: 700, wantChanged: true},
+			},
+			wantHeld: false,
+		},
+		"release without press": {
+			releaseEvents: true,
+			steps: []step{
+				{release: true, at: 0, wantChanged: false},
+			},
+			wantHeld: false,
+		},
+		"legacy waiting for first repeat": {
+			steps: []step{
+				{press: true, at: 0, wantChanged: true, wantTicking: true},
+				{tick: true, at: 500, wantChanged: false, wantTicking: true},
+			},
+			wantHeld: true,
+		},
+		"legacy tap": {
+			steps: []step{
+				{press: true, at: 0, wantChanged: true, wantTicking: true},
+				{tick: true, at: 600, wantChanged: true, wantTicking: false},
+			},
+			wantHeld: false,
+		},
+		"legacy repeats": {
+			steps: []step{
+				{press: true, at: 0, wantChanged: true, wantTicking: true},
+				{press: true, at: 500, wantChanged: false, wantTicking: false},
+				{press: true, at: 530, wantChanged: false, wantTicking: false},
+				{tick: true, at: 600, wantChanged: false, wantTicking: true},
+				{tick: true, at: 640, wantChanged: true, wantTicking: false},
+			},
+			wantHeld: false,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			h := NewKeyHold()
+			h.SetReleaseEvents(tc.releaseEvents)
+
+			for i, s := range tc.steps {
+				switch {
+				case s.press:
+					started, cmd := h.Press(at(s.at))
+					assert.Equal(t, s.wantChanged, started, "step %d", i)
+					assert.Equal(t, s.wantTicking, cmd != nil, "step %d", i)
+				case s.release:
+					assert.Equal(t, s.wantChanged, h.Release(), "step %d", i)
+				case s.tick:
+					released, cmd := h.Tick(KeyHoldTickMsg{ID: h.ID(), Time: at(s.at), tag: h.tag})
+					assert.Equal(t, s.wantChanged, released, "step %d", i)
+					assert.Equal(t, s.wantTicking, cmd != nil, "step %d", i)
+				}
+			}
+			assert.Equal(t, tc.wantHeld, h.IsHeld())
+		})
+	}
+}
@@ -0,0 +1,289 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// The kitty keyboard protocol (https://sw.kovidgoyal.net/kitty/keyboard-protocol/) lets terminals report key
+// release events. Bubble Tea does not understand these sequences, so they arrive as unknown CSI sequences which
+// TranslateKeyboardMsg converts into KeyMsg and KeyReleaseMsg values.
+
+const (
+	// keyboardFlagDisambiguate makes the terminal encode ambiguous keys (eg. Esc, Alt+key) as CSI u sequences.
+	keyboardFlagDisambiguate = 1
+	// keyboardFlagEventTypes makes the terminal report key repeat and release events.
+	keyboardFlagEventTypes = 2
+
+	keyEventPress   = 1
+	keyEventRepeat  = 2
+	keyEventRelease = 3
+
+	modShift = 1
+	modAlt   = 2
+	modCtrl  = 4
+)
+
+// KeyReleaseMsg is sent when a key is released.
+// It is only sent by terminals supporting the kitty keyboard protocol.
+type KeyReleaseMsg struct {
+	tea.Key
+}
+
+// KeyboardEnhancementsMsg reports the kitty keyboard protocol flags enabled in the terminal.
+type KeyboardEnhancementsMsg struct {
+	Flags int
+}
+
+// SupportsKeyReleases returns true if the terminal sends KeyReleaseMsg values.
+func (msg KeyboardEnhancementsMsg) SupportsKeyReleases() bool {
+	return msg.Flags&keyboardFlagEventTypes != 0
+}
+
+// QueryKeyboardEnhancementsCmd asks the terminal which kitty keyboard protocol flags are enabled.
+// Terminals supporting the protocol respond with a sequence which TranslateKeyboardMsg handles.
+func QueryKeyboardEnhancementsCmd(w io.Writer) tea.Cmd {
+	return writeSequenceCmd(w, "\x1b[?u")
+}
+
+// EnableKeyboardEnhancementsCmd enables the kitty keyboard protocol flags used by the game.
+// The flags are pushed to the stack of the current screen, so they are discarded when the alt screen is exited.
+func EnableKeyboardEnhancementsCmd(w io.Writer) tea.Cmd {
+	return writeSequenceCmd(w, fmt.Sprintf("\x1b[>%du", keyboardFlagDisambiguate|keyboardFlagEventTypes))
+}
+
+func writeSequenceCmd(w io.Writer, seq string) tea.Cmd {
+	return func() tea.Msg {
+		if _, err := io.WriteString(w, seq); err != nil {
+			return FatalErrorMsg(fmt.Errorf("writing to terminal: %w", err))
+		}
+		return nil
+	}
+}
+
+// TranslateKeyboardMsg converts kitty keyboard protocol sequences into KeyMsg, KeyReleaseMsg,
+// and KeyboardEnhancementsMsg values. Any other message is returned unchanged.
+func TranslateKeyboardMsg(msg tea.Msg) tea.Msg {
+	seq, ok := unknownCSISequence(msg)
+	if !ok {
+		return msg
+	}
+	if translated, ok := parseKeyboardSequence(seq); ok {
+		return translated
+	}
+	return msg
+}
+
+// unknownCSISequence returns the bytes of the unexported message Bubble Tea sends for CSI sequences it does not
+// recognise. Since this relies on the name of the type, TestTranslateKeyboardMsg_BubbleTea checks it against the
+// version of Bubble Tea in use.
+func unknownCSISequence(msg tea.Msg) (string, bool) {
+	v := reflect.ValueOf(msg)
+	if !v.IsValid() || v.Kind() != reflect.Slice || v.Type().Elem().Kind() != reflect.Uint8 {
+		return "", false
+	}
+	t := v.Type()
+	if t.PkgPath() != reflect.TypeOf(tea.KeyMsg{}).PkgPath() || t.Name() != "unknownCSISequenceMsg" {
+		return "", false
+	}
+	return string(v.Bytes()), true
+}
+
+// parseKeyboardSequence parses a kitty keyboard protocol sequence, returning false if it is not one.
+func parseKeyboardSequence(seq string) (tea.Msg, bool) {
+	body, ok := strings.CutPrefix(seq, "\x1b[")
+	if !ok || len(body) < 1 {
+		return nil, false
+	}
+	final := body[len(body)-1]
+	params := body[:len(body)-1]
+
+	if strings.HasPrefix(params, "?") {
+		if final != 'u' {
+			return nil, false
+		}
+		flags, err := strconv.Atoi(params[1:])
+		if err != nil {
+			return nil, false
+		}
+		return KeyboardEnhancementsMsg{Flags: flags}, true
+	}
+
+	fields := strings.Split(params, ";")
+	mods, event, ok := parseModifiers(fields)
+	if !ok {
+		return nil, false
+	}
+
+	var k tea.Key
+	switch final {
+	case 'u':
+		k, ok = parseCSIuKey(fields, mods)
+	case 'A', 'B', 'C', 'D', 'H', 'F':
+		k, ok = parseLetterKey(fields[0], final, mods)
+	case '~':
+		k, ok = parseTildeKey(fields[0], mods)
+	default:
+		return nil, false
+	}
+	if !ok {
+		return nil, false
+	}
+
+	switch event {
+	case keyEventPress, keyEventRepeat:
+		return tea.KeyMsg(k), true
+	case keyEventRelease:
+		return KeyReleaseMsg{Key: k}, true
+	default:
+		return nil, false
+	}
+}
+
+// parseModifiers parses the "modifiers:event" field, which defaults to no modifiers and a press event.
+func parseModifiers(fields []string) (int, int, bool) {
+	mods, event := 0, keyEventPress
+	if len(fields) < 2 || fields[1] == "" {
+		return mods, event, true
+	}
+
+	modStr, eventStr, hasEvent := strings.Cut(fields[1], ":")
+	if modStr != "" {
+		encoded, err := strconv.Atoi(modStr)
+		if err != nil || encoded < 1 {
+			return 0, 0, false
+		}
+		mods = encoded - 1
+	}
+	if hasEvent {
+		var err error
+		event, err = strconv.Atoi(eventStr)
+		if err != nil {
+			return 0, 0, false
+		}
+	}
+	return mods, event, true
+}
+
+// parseCSIuKey parses the key of a "CSI code[:alternates];modifiers[:event][;text] u" sequence.
+func parseCSIuKey(fields []string, mods int) (tea.Key, bool) {
+	codeStr, _, _ := strings.Cut(fields[0], ":")
+	code, err := strconv.Atoi(codeStr)
+	if err != nil {
+		return tea.Key{}, false
+	}
+
+	k := tea.Key{Alt: mods&modAlt != 0}
+	switch code {
+	case 13, 57414: // Enter and keypad Enter.
+		k.Type = tea.KeyEnter
+	case 9:
+		k.Type = tea.KeyTab
+		if mods&modShift != 0 {
+			k.Type = tea.KeyShiftTab
+		}
+	case 127:
+		k.Type = tea.KeyBackspace
+	case 27:
+		k.Type = tea.KeyEscape
+	case 32:
+		k.Type = tea.KeySpace
+		k.Runes = []rune{' '}
+	default:
+		r := rune(code)
+		if !unicode.IsPrint(r) || unicode.In(r, unicode.Co) {
+			// Functional keys (eg. modifier keys) are encoded in the private use area.
+			return tea.Key{}, false
+		}
+		if mods&modCtrl != 0 {
+			if r >= 'a' && r <= 'z' {
+				k.Type = tea.KeyCtrlA + tea.KeyType(r-'a')
+				return k, true
+			}
+			return tea.Key{}, false
+		}
+		if len(fields) > 2 && fields[2] != "" {
+			r = textRune(fields[2], r)
+		} else if mods&modShift != 0 {
+			r = unicode.ToUpper(r)
+		}
+		k.Type = tea.KeyRunes
+		k.Runes = []rune{r}
+	}
+	return k, true
+}
+
+// textRune returns the first code point of the text field, falling back to r if it cannot be parsed.
+func textRune(field string, r rune) rune {
+	first, _, _ := strings.Cut(field, ":")
+	code, err := strconv.Atoi(first)
+	if err != nil {
+		return r
+	}
+	return rune(code)
+}
+
+// parseLetterKey parses the key of a "CSI 1;modifiers[:event] {ABCDHF}" sequence.
+func parseLetterKey(number string, final byte, mods int) (tea.Key, bool) {
+	if number != "" && number != "1" {
+		return tea.Key{}, false
+	}
+
+	shift, ctrl := mods&modShift != 0, mods&modCtrl != 0
+	k := tea.Key{Alt: mods&modAlt != 0}
+	switch final {
+	case 'A':
+		k.Type = pickKeyType(shift, ctrl, tea.KeyUp, tea.KeyShiftUp, tea.KeyCtrlUp, tea.KeyCtrlShiftUp)
+	case 'B':
+		k.Type = pickKeyType(shift, ctrl, tea.KeyDown, tea.KeyShiftDown, tea.KeyCtrlDown, tea.KeyCtrlShiftDown)
+	case 'C':
+		k.Type = pickKeyType(shift, ctrl, tea.KeyRight, tea.KeyShiftRight, tea.KeyCtrlRight, tea.KeyCtrlShiftRight)
+	case 'D':
+		k.Type = pickKeyType(shift, ctrl, tea.KeyLeft, tea.KeyShiftLeft, tea.KeyCtrlLeft, tea.KeyCtrlShiftLeft)
+	case 'H':
+		k.Type = pickKeyType(shift, ctrl, tea.KeyHome, tea.KeyShiftHome, tea.KeyCtrlHome, tea.KeyCtrlShiftHome)
+	case 'F':
+		k.Type = pickKeyType(shift, ctrl, tea.KeyEnd, tea.KeyShiftEnd, tea.KeyCtrlEnd, tea.KeyCtrlShiftEnd)
+	default:
+		return tea.Key{}, false
+	}
+	return k, true
+}
+
+// parseTildeKey parses the key of a "CSI number;modifiers[:event] ~" sequence.
+func parseTildeKey(number string, mods int) (tea.Key, bool) {
+	shift, ctrl := mods&modShift != 0, mods&modCtrl != 0
+	k := tea.Key{Alt: mods&modAlt != 0}
+	switch number {
+	case "2":
+		k.Type = tea.KeyInsert
+	case "3":
+		k.Type = tea.KeyDelete
+	case "5":
+		k.Type = pickKeyType(shift, ctrl, tea.KeyPgUp, tea.KeyPgUp, tea.KeyCtrlPgUp, tea.KeyCtrlPgUp)
+	case "6":
+		k.Type = pickKeyType(shift, ctrl, tea.KeyPgDown, tea.KeyPgDown, tea.KeyCtrlPgDown, tea.KeyCtrlPgDown)
+	default:
+		return tea.Key{}, false
+	}
+	return k, true
+}
+
+func pickKeyType(shift, ctrl bool, plain, shifted, ctrled, both tea.KeyType) tea.KeyType {
+	switch {
+	case shift && ctrl:
+		return both
+	case ctrl:
+		return ctrled
+	case shift:
+		return shifted
+	default:
+		return plain
+	}
+}
@@ -0,0 +1,178 @@
+package tui
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKeyboardSequence(t *testing.T) {
+	tt := map[string]struct {
+		seq     string
+		wantMsg tea.Msg
+		wantOK  bool
+	}{
+		"flags response": {
+			seq:     "\x1b[?3u",
+			wantMsg: KeyboardEnhancementsMsg{Flags: 3},
+			wantOK:  true,
+		},
+		"rune press": {
+			seq:     "\x1b[97u",
+			wantMsg: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")},
+			wantOK:  true,
+		},
+		"rune repeat": {
+			seq:     "\x1b[97;1:2u",
+			wantMsg: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")},
+			wantOK:  true,
+		},
+		"rune release": {
+			seq:     "\x1b[97;1:3u",
+			wantMsg: KeyReleaseMsg{Key: tea.Key{Type: tea.KeyRunes, Runes: []rune("a")}},
+			wantOK:  true,
+		},
+		"shifted rune with text": {
+			seq:     "\x1b[97:65;2;65u",
+			wantMsg: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("A")},
+			wantOK:  true,
+		},
+		"alt rune": {
+			seq:     "\x1b[97;3u",
+			wantMsg: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a"), Alt: true},
+			wantOK:  true,
+		},
+		"ctrl+c": {
+			seq:     "\x1b[99;5u",
+			wantMsg: tea.KeyMsg{Type: tea.KeyCtrlC},
+			wantOK:  true,
+		},
+		"escape": {
+			seq:     "\x1b[27u",
+			wantMsg: tea.KeyMsg{Type: tea.KeyEscape},
+			wantOK:  true,
+		},
+		"space release": {
+			seq:     "\x1b[32;1:3u",
+			wantMsg: KeyReleaseMsg{Key: tea.Key{Type: tea.KeySpace, Runes: []rune(" ")}},
+			wantOK:  true,
+		},
+		"left arrow repeat": {
+			seq:     "\x1b[1;1:2D",
+			wantMsg: tea.KeyMsg{Type: tea.KeyLeft},
+			wantOK:  true,
+		},
+		"right arrow release": {
+			seq:     "\x1b[1;1:3C",
+			wantMsg: KeyReleaseMsg{Key: tea.Key{Type: tea.KeyRight}},
+			wantOK:  true,
+		},
+		"ctrl+shift up arrow": {
+			seq:     "\x1b[1;6A",
+			wantMsg: tea.KeyMsg{Type: tea.KeyCtrlShiftUp},
+			wantOK:  true,
+		},
+		"delete release": {
+			seq:     "\x1b[3;1:3~",
+			wantMsg: KeyReleaseMsg{Key: tea.Key{Type: tea.KeyDelete}},
+			wantOK:  true,
+		},
+		"modifier key": {
+			seq:    "\x1b[57441;2u",
+			wantOK: false,
+		},
+		"unknown final byte": {
+			seq:    "\x1b[1;1:3Z",
+			wantOK: false,
+		},
+		"invalid modifiers": {
+			seq:    "\x1b[97;x:3u",
+			wantOK: false,
+		},
+		"unknown event": {
+			seq:    "\x1b[97;1:4u",
+			wantOK: false,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			msg, ok := parseKeyboardSequence(tc.seq)
+			assert.Equal(t, tc.wantOK, ok)
+			if tc.wantOK {
+				assert.Equal(t, tc.wantMsg, msg)
+			}
+		})
+	}
+}
+
+func TestTranslateKeyboardMsg_Passthrough(t *testing.T) {
+	msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")}
+	assert.Equal(t, msg, TranslateKeyboardMsg(msg))
+}
+
+// translateModel records the first message which TranslateKeyboardMsg converts from a sequence Bubble Tea does not
+// recognise, then quits.
+type translateModel struct {
+	got tea.Msg
+}
+
+func (m *translateModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *translateModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch translated := TranslateKeyboardMsg(msg); translated.(type) {
+	case KeyReleaseMsg, KeyboardEnhancementsMsg:
+		m.got = translated
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m *translateModel) View() string {
+	return ""
+}
+
+// TestTranslateKeyboardMsg_BubbleTea feeds sequences through a real Bubble Tea program, since the message it sends for
+// unrecognised sequences is unexported. This fails if a Bubble Tea upgrade changes how those sequences are sent.
+func TestTranslateKeyboardMsg_BubbleTea(t *testing.T) {
+	tt := map[string]struct {
+		seq  string
+		want tea.Msg
+	}{
+		"key release": {
+			seq:  "\x1b[97;1:3u",
+			want: KeyReleaseMsg{Key: tea.Key{Type: tea.KeyRunes, Runes: []rune("a")}},
+		},
+		"flags response": {
+			seq:  "\x1b[?3u",
+			want: KeyboardEnhancementsMsg{Flags: 3},
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			m := &translateModel{}
+			p := tea.NewProgram(m,
+				tea.WithContext(ctx),
+				tea.WithInput(strings.NewReader(tc.seq)),
+				tea.WithOutput(io.Discard),
+				tea.WithoutRenderer(),
+				tea.WithoutSignalHandler(),
+			)
+			_, err := p.Run()
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, m.got)
+		})
+	}
+}
@@ -1,9 +1,17 @@
 package tui
 
 import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/Broderick-Westrope/tetrigo/internal/data"
+	"github.com/Broderick-Westrope/tetrigo/internal/replay"
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris"
 )
 
 type SwitchModeMsg struct {
@@ -32,6 +40,12 @@ const (
 	ModeSprint
 	ModeUltra
 	ModeLeaderboard
+	ModeReplay
+	ModeVersus
+	ModeOnline
+	ModeResume
+	ModeDig
+	ModeMaster
 )
 
 var modeToStrMap = map[Mode]string{
@@ -40,30 +54,295 @@ var modeToStrMap = map[Mode]string{
 	ModeSprint:      "Sprint",
 	ModeUltra:       "Ultra",
 	ModeLeaderboard: "Leaderboard",
+	ModeReplay:      "Replay",
+	ModeVersus:      "Versus",
+	ModeOnline:      "Online",
+	ModeResume:      "Resume",
+	ModeDig:         "Dig",
+	ModeMaster:      "Master",
 }
 
 func (m Mode) String() string {
 	return modeToStrMap[m]
 }
 
+// ParseMode returns the Mode with the given name, ignoring case.
+func ParseMode(s string) (Mode, error) {
+	for mode, str := range modeToStrMap {
+		if strings.EqualFold(s, str) {
+			return mode, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid mode %q", s)
+}
+
+// DefaultLineGoal is the number of lines to clear in a Sprint when no goal is chosen.
+const DefaultLineGoal = 40
+
+// LineGoals are the Sprint line goals offered in the menu. Any positive goal can be chosen using the CLI.
+var LineGoals = []int{20, DefaultLineGoal, 100}
+
+// DefaultTimeLimit is the length of an Ultra when no time limit is chosen.
+const DefaultTimeLimit = 2 * time.Minute
+
+// TimeLimits are the Ultra time limits offered in the menu. Any whole number of minutes can be chosen using the CLI.
+var TimeLimits = []time.Duration{time.Minute, DefaultTimeLimit, 3 * time.Minute, 5 * time.Minute}
+
+// DefaultGarbageLines is the number of garbage lines to clear in Dig when no number is chosen.
+const DefaultGarbageLines = 10
+
+// GarbageLineCounts are the numbers of Dig garbage lines offered in the menu. Any positive number less than the
+// visible height of the matrix can be chosen using the CLI.
+var GarbageLineCounts = []int{5, DefaultGarbageLines, 18}
+
+// risingGarbageVariant is the part of the game mode name of a Dig with rising garbage.
+const risingGarbageVariant = "Rising"
+
 // SwitchModeInput values --------------------------------------------------
 
 type SingleInput struct {
 	Mode     Mode
 	Level    int
 	Username string
+	Bot      bool
+
+	// Width and Height override the size of the matrix from the config. 0 means the config value is used.
+	// Since a non-default size is part of the GameModeName, they should be set to the config value before it is used
+	// (see WithDefaultMatrixSize).
+	Width  int
+	Height int
+
+	// LineGoal is the number of lines to clear in a Sprint. 0 means DefaultLineGoal is used.
+	LineGoal int
+	// TimeLimit is the length of an Ultra, which must be a whole number of minutes. 0 means DefaultTimeLimit is used.
+	TimeLimit time.Duration
+	// GarbageLines is the number of garbage lines a Dig starts with. 0 means DefaultGarbageLines is used.
+	GarbageLines int
+	// RisingGarbage is whether more garbage keeps rising during a Dig.
+	RisingGarbage bool
 }
 
-func NewSingleInput(mode Mode, level int, username string) *SingleInput {
-	return &SingleInput{
+func NewSingleInput(mode Mode, level int, username string, opts ...func(*SingleInput)) *SingleInput {
+	in := &SingleInput{
 		Mode:     mode,
 		Level:    level,
 		Username: username,
 	}
+
+	for _, opt := range opts {
+		opt(in)
+	}
+
+	return in
 }
 
 func (in *SingleInput) isSwitchModeInput() {}
 
+// GameModeName returns the name that scores of the game are saved under on the leaderboard.
+// A Sprint with a goal other than DefaultLineGoal, or an Ultra with a time limit other than DefaultTimeLimit, is
+// ranked separately, so its goal or time limit is included (eg. "Sprint 20L" or "Ultra 5min").
+// Likewise a Dig includes the number of garbage lines if it isn't DefaultGarbageLines, and whether garbage is rising
+// (eg. "Dig 18L Rising"). A matrix of any size other than the default is always included last (eg. "Marathon 4x20").
+func (in *SingleInput) GameModeName() string {
+	name := in.Mode.String()
+	switch {
+	case in.Mode == ModeSprint && in.LineGoal != 0 && in.LineGoal != DefaultLineGoal:
+		name += fmt.Sprintf(" %dL", in.LineGoal)
+	case in.Mode == ModeUltra && in.TimeLimit != 0 && in.TimeLimit != DefaultTimeLimit:
+		name += fmt.Sprintf(" %dmin", in.TimeLimit/time.Minute)
+	case in.Mode == ModeDig:
+		if in.GarbageLines != 0 && in.GarbageLines != DefaultGarbageLines {
+			name += fmt.Sprintf(" %dL", in.GarbageLines)
+		}
+		if in.RisingGarbage {
+			name += " " + risingGarbageVariant
+		}
+	}
+
+	width := cmp.Or(in.Width, tetris.DefaultMatrixWidth)
+	height := cmp.Or(in.Height, tetris.DefaultMatrixHeight)
+	if width != tetris.DefaultMatrixWidth || height != tetris.DefaultMatrixHeight {
+		name += fmt.Sprintf(" %dx%d", width, height)
+	}
+	return name
+}
+
+// ParseGameModeName returns a SingleInput with the game options of a name returned by GameModeName, ignoring case.
+func ParseGameModeName(name string) (*SingleInput, error) {
+	fields := strings.Fields(name)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("invalid game mode %q", name)
+	}
+	mode, err := ParseMode(fields[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid game mode %q", name)
+	}
+
+	in := NewSingleInput(mode, 0, "")
+	variant := fields[1:]
+	if len(variant) > 0 {
+		if width, height, ok := parseMatrixSizeVariant(variant[len(variant)-1]); ok {
+			in.Width, in.Height = width, height
+			variant = variant[:len(variant)-1]
+		}
+	}
+
+	switch mode {
+	case ModeMarathon, ModeMaster:
+		if len(variant) > 0 {
+			return nil, fmt.Errorf("invalid game mode %q", name)
+		}
+	case ModeUltra:
+		if len(variant) == 0 {
+			break
+		}
+		minutes, err := strconv.Atoi(strings.TrimSuffix(strings.ToLower(variant[0]), "min"))
+		if err != nil || minutes <= 0 || len(variant) > 1 {
+			return nil, fmt.Errorf("invalid time limit in game mode %q", name)
+		}
+		in.TimeLimit = time.Duration(minutes) * time.Minute
+	case ModeSprint:
+		if len(variant) == 0 {
+			break
+		}
+		lines, err := strconv.Atoi(strings.TrimSuffix(strings.ToUpper(variant[0]), "L"))
+		if err != nil || lines <= 0 || len(variant) > 1 {
+			return nil, fmt.Errorf("invalid line goal in game mode %q", name)
+		}
+		in.LineGoal = lines
+	case ModeDig:
+		if err = in.parseDigVariant(variant); err != nil {
+			return nil, fmt.Errorf("invalid game mode %q: %w", name, err)
+		}
+	case ModeMenu, ModeLeaderboard, ModeReplay, ModeVersus, ModeOnline, ModeResume:
+		fallthrough
+	default:
+		return nil, fmt.Errorf("%q is not a single player game mode", name)
+	}
+	return in, nil
+}
+
+// parseMatrixSizeVariant parses the size of the matrix from the variant of a game mode name (eg. "4x20").
+// It returns false if the variant is not a size within the supported range.
+func parseMatrixSizeVariant(variant string) (width, height int, ok bool) {
+	w, h, found := strings.Cut(strings.ToLower(variant), "x")
+	if !found {
+		return 0, 0, false
+	}
+	width, err := strconv.Atoi(w)
+	if err != nil || width < tetris.MinMatrixWidth || width > tetris.MaxMatrixWidth {
+		return 0, 0, false
+	}
+	height, err = strconv.Atoi(h)
+	if err != nil || height < tetris.MinMatrixHeight || height > tetris.MaxMatrixHeight {
+		return 0, 0, false
+	}
+	return width, height, true
+}
+
+// parseDigVariant sets the Dig options from the fields of the variant of a game mode name, which are the number
+// of garbage lines and whether garbage is rising, both of which are optional (eg. "18L Rising").
+func (in *SingleInput) parseDigVariant(fields []string) error {
+	if len(fields) > 0 && !strings.EqualFold(fields[0], risingGarbageVariant) {
+		lines, err := strconv.Atoi(strings.TrimSuffix(strings.ToUpper(fields[0]), "L"))
+		if err != nil || lines <= 0 {
+			return fmt.Errorf("invalid garbage lines %q", fields[0])
+		}
+		in.GarbageLines = lines
+		fields = fields[1:]
+	}
+	if len(fields) > 0 && strings.EqualFold(fields[0], risingGarbageVariant) {
+		in.RisingGarbage = true
+		fields = fields[1:]
+	}
+	if len(fields) > 0 {
+		return fmt.Errorf("unexpected option %q", fields[0])
+	}
+	return nil
+}
+
+// WithBot makes a bot play the game instead of the user.
+func WithBot() func(*SingleInput) {
+	return func(in *SingleInput) {
+		in.Bot = true
+	}
+}
+
+// WithMatrixSize sets the width and visible height of the matrix, overriding the config.
+// A value of 0 keeps the config value.
+func WithMatrixSize(width, height int) func(*SingleInput) {
+	return func(in *SingleInput) {
+		in.Width = width
+		in.Height = height
+	}
+}
+
+// WithDefaultMatrixSize sets the width and visible height of the matrix if they have not already been set.
+// This is used to apply the config to the input.
+func WithDefaultMatrixSize(width, height int) func(*SingleInput) {
+	return func(in *SingleInput) {
+		in.Width = cmp.Or(in.Width, width)
+		in.Height = cmp.Or(in.Height, height)
+	}
+}
+
+// WithLineGoal sets the number of lines to clear in a Sprint. A value of 0 uses DefaultLineGoal.
+func WithLineGoal(lines int) func(*SingleInput) {
+	return func(in *SingleInput) {
+		in.LineGoal = lines
+	}
+}
+
+// WithTimeLimit sets the length of an Ultra, which must be a whole number of minutes.
+// A value of 0 uses DefaultTimeLimit.
+func WithTimeLimit(d time.Duration) func(*SingleInput) {
+	return func(in *SingleInput) {
+		in.TimeLimit = d
+	}
+}
+
+// WithGarbageLines sets the number of garbage lines a Dig starts with. A value of 0 uses DefaultGarbageLines.
+func WithGarbageLines(lines int) func(*SingleInput) {
+	return func(in *SingleInput) {
+		in.GarbageLines = lines
+	}
+}
+
+// WithRisingGarbage makes more garbage keep rising during a Dig.
+func WithRisingGarbage() func(*SingleInput) {
+	return func(in *SingleInput) {
+		in.RisingGarbage = true
+	}
+}
+
+type VersusInput struct {
+	Level int
+}
+
+func NewVersusInput(level int) *VersusInput {
+	return &VersusInput{
+		Level: level,
+	}
+}
+
+func (in *VersusInput) isSwitchModeInput() {}
+
+type OnlineInput struct {
+	Addr     string // The address of the server to join.
+	Username string
+	Level    int
+}
+
+func NewOnlineInput(addr, username string, level int) *OnlineInput {
+	return &OnlineInput{
+		Addr:     addr,
+		Username: username,
+		Level:    level,
+	}
+}
+
+func (in *OnlineInput) isSwitchModeInput() {}
+
 type MenuInput struct{}
 
 func NewMenuInput() *MenuInput {
@@ -75,6 +354,8 @@ func (in *MenuInput) isSwitchModeInput() {}
 type LeaderboardInput struct {
 	GameMode string
 	NewEntry *data.Score
+	// Username is the player whose personal bests can be shown. The name of the NewEntry is used if it is empty.
+	Username string
 }
 
 func NewLeaderboardInput(gameMode string, opts ...func(input *LeaderboardInput)) *LeaderboardInput {
@@ -96,3 +377,30 @@ func WithNewEntry(entry *data.Score) func(input *LeaderboardInput) {
 		in.NewEntry = entry
 	}
 }
+
+func WithUsername(username string) func(input *LeaderboardInput) {
+	return func(in *LeaderboardInput) {
+		in.Username = username
+	}
+}
+
+type ReplayInput struct {
+	Replay *replay.Replay
+}
+
+func NewReplayInput(r *replay.Replay) *ReplayInput {
+	return &ReplayInput{
+		Replay: r,
+	}
+}
+
+func (in *ReplayInput) isSwitchModeInput() {}
+
+// ResumeInput resumes the saved single player game.
+type ResumeInput struct{}
+
+func NewResumeInput() *ResumeInput {
+	return &ResumeInput{}
+}
+
+func (in *ResumeInput) isSwitchModeInput() {}
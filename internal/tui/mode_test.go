@@ -0,0 +1,203 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGameModeName(t *testing.T) {
+	tt := map[string]struct {
+		in   *SingleInput
+		want string
+	}{
+		"marathon": {
+			in:   NewSingleInput(ModeMarathon, 1, ""),
+			want: "Marathon",
+		},
+		"sprint with default goal": {
+			in:   NewSingleInput(ModeSprint, 1, ""),
+			want: "Sprint",
+		},
+		"sprint with explicit default goal": {
+			in:   NewSingleInput(ModeSprint, 1, "", WithLineGoal(DefaultLineGoal)),
+			want: "Sprint",
+		},
+		"sprint with goal": {
+			in:   NewSingleInput(ModeSprint, 1, "", WithLineGoal(20)),
+			want: "Sprint 20L",
+		},
+		"ultra with default time limit": {
+			in:   NewSingleInput(ModeUltra, 1, "", WithTimeLimit(DefaultTimeLimit)),
+			want: "Ultra",
+		},
+		"ultra with time limit": {
+			in:   NewSingleInput(ModeUltra, 1, "", WithTimeLimit(5*time.Minute)),
+			want: "Ultra 5min",
+		},
+		"dig with defaults": {
+			in:   NewSingleInput(ModeDig, 1, "", WithGarbageLines(DefaultGarbageLines)),
+			want: "Dig",
+		},
+		"dig with garbage lines": {
+			in:   NewSingleInput(ModeDig, 1, "", WithGarbageLines(18)),
+			want: "Dig 18L",
+		},
+		"dig with rising garbage": {
+			in:   NewSingleInput(ModeDig, 1, "", WithRisingGarbage()),
+			want: "Dig Rising",
+		},
+		"dig with garbage lines and rising garbage": {
+			in:   NewSingleInput(ModeDig, 1, "", WithGarbageLines(5), WithRisingGarbage()),
+			want: "Dig 5L Rising",
+		},
+		"default matrix size": {
+			in:   NewSingleInput(ModeMarathon, 1, "", WithMatrixSize(10, 20)),
+			want: "Marathon",
+		},
+		"matrix width": {
+			in:   NewSingleInput(ModeMarathon, 1, "", WithMatrixSize(4, 0)),
+			want: "Marathon 4x20",
+		},
+		"matrix size with variant": {
+			in:   NewSingleInput(ModeDig, 1, "", WithRisingGarbage(), WithMatrixSize(12, 30)),
+			want: "Dig Rising 12x30",
+		},
+		"config matrix size does not override input": {
+			in: NewSingleInput(ModeSprint, 1, "",
+				WithLineGoal(20), WithMatrixSize(0, 40), WithDefaultMatrixSize(6, 20),
+			),
+			want: "Sprint 20L 6x40",
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := tc.in.GameModeName()
+			assert.Equal(t, tc.want, got)
+
+			parsed, err := ParseGameModeName(got)
+			require.NoError(t, err)
+			assert.Equal(t, got, parsed.GameModeName())
+		})
+	}
+}
+
+func TestParseGameModeName(t *testing.T) {
+	tt := map[string]struct {
+		name     string
+		wantMode Mode
+		wantGoal int
+		wantTime time.Duration
+		// wantGarbage is the number of garbage lines of a Dig.
+		wantGarbage int
+		wantRising  bool
+		// wantWidth and wantHeight are the size of the matrix, or 0 for the default size.
+		wantWidth  int
+		wantHeight int
+		wantErr    bool
+	}{
+		"ignores case": {
+			name:     "sprint 100l",
+			wantMode: ModeSprint,
+			wantGoal: 100,
+		},
+		"without goal": {
+			name:     "ULTRA",
+			wantMode: ModeUltra,
+		},
+		"time limit": {
+			name:     "Ultra 3MIN",
+			wantMode: ModeUltra,
+			wantTime: 3 * time.Minute,
+		},
+		"invalid time limit": {
+			name:    "Ultra 3s",
+			wantErr: true,
+		},
+		"dig": {
+			name:        "dig 18l rising",
+			wantMode:    ModeDig,
+			wantGarbage: 18,
+			wantRising:  true,
+		},
+		"dig with only rising garbage": {
+			name:       "Dig RISING",
+			wantMode:   ModeDig,
+			wantRising: true,
+		},
+		"dig with options out of order": {
+			name:    "Dig Rising 18L",
+			wantErr: true,
+		},
+		"invalid garbage lines": {
+			name:    "Dig -5L",
+			wantErr: true,
+		},
+		"invalid goal": {
+			name:    "Sprint 0L",
+			wantErr: true,
+		},
+		"goal for another mode": {
+			name:    "Marathon 20L",
+			wantErr: true,
+		},
+		"master": {
+			name:     "master",
+			wantMode: ModeMaster,
+		},
+		"matrix size": {
+			name:       "Sprint 20L 4X24",
+			wantMode:   ModeSprint,
+			wantGoal:   20,
+			wantWidth:  4,
+			wantHeight: 24,
+		},
+		"matrix size without variant": {
+			name:       "Master 20x40",
+			wantMode:   ModeMaster,
+			wantWidth:  20,
+			wantHeight: 40,
+		},
+		"matrix size before variant": {
+			name:    "Sprint 4x24 20L",
+			wantErr: true,
+		},
+		"unsupported matrix size": {
+			name:    "Marathon 2x20",
+			wantErr: true,
+		},
+		"master with options": {
+			name:    "Master 20G",
+			wantErr: true,
+		},
+		"multiplayer mode": {
+			name:    "Versus",
+			wantErr: true,
+		},
+		"unknown mode": {
+			name:    "Unknown",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			in, err := ParseGameModeName(tc.name)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantMode, in.Mode)
+			assert.Equal(t, tc.wantGoal, in.LineGoal)
+			assert.Equal(t, tc.wantTime, in.TimeLimit)
+			assert.Equal(t, tc.wantGarbage, in.GarbageLines)
+			assert.Equal(t, tc.wantRising, in.RisingGarbage)
+			assert.Equal(t, tc.wantWidth, in.Width)
+			assert.Equal(t, tc.wantHeight, in.Height)
+		})
+	}
+}
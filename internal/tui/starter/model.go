@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"io"
 	"reflect"
 
 	"github.com/Broderick-Westrope/charmutils"
@@ -12,24 +13,65 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/Broderick-Westrope/tetrigo/internal/config"
+	"github.com/Broderick-Westrope/tetrigo/internal/data"
+	"github.com/Broderick-Westrope/tetrigo/internal/savegame"
 	"github.com/Broderick-Westrope/tetrigo/internal/tui"
 	"github.com/Broderick-Westrope/tetrigo/internal/tui/views"
 )
 
 type Input struct {
-	mode     tui.Mode
-	switchIn tui.SwitchModeInput
-	db       *sql.DB
-	cfg      *config.Config
+	mode      tui.Mode
+	switchIn  tui.SwitchModeInput
+	db        *sql.DB
+	cfg       *config.Config
+	replayDir string
+	savePath  string
+	terminal  io.Writer
 }
 
-func NewInput(mode tui.Mode, switchIn tui.SwitchModeInput, db *sql.DB, cfg *config.Config) *Input {
-	return &Input{
+func NewInput(
+	mode tui.Mode,
+	switchIn tui.SwitchModeInput,
+	db *sql.DB,
+	cfg *config.Config,
+	opts ...func(*Input),
+) *Input {
+	in := &Input{
 		mode:     mode,
 		switchIn: switchIn,
 		db:       db,
 		cfg:      cfg,
 	}
+
+	for _, opt := range opts {
+		opt(in)
+	}
+
+	return in
+}
+
+// WithReplayDir sets the directory that replays of single player games are saved to.
+// If not set, games are not recorded.
+func WithReplayDir(dir string) func(*Input) {
+	return func(in *Input) {
+		in.replayDir = dir
+	}
+}
+
+// WithSavePath sets the file that single player games are saved to when exiting from the pause menu.
+// If not set, games are not saved and cannot be resumed.
+func WithSavePath(path string) func(*Input) {
+	return func(in *Input) {
+		in.savePath = path
+	}
+}
+
+// WithTerminal sets the output of the terminal, which is used to enable key release events
+// on terminals supporting the kitty keyboard protocol.
+func WithTerminal(w io.Writer) func(*Input) {
+	return func(in *Input) {
+		in.terminal = w
+	}
 }
 
 var _ tea.Model = &Model{}
@@ -38,9 +80,15 @@ type Model struct {
 	child        tea.Model
 	db           *sql.DB
 	cfg          *config.Config
+	replayDir    string
+	savePath     string
 	forceQuitKey key.Binding
 	ctx          context.Context
 
+	terminal         io.Writer
+	keyboardEnabling bool
+	keyboard         *tui.KeyboardEnhancementsMsg
+
 	width  int
 	height int
 
@@ -51,6 +99,9 @@ func NewModel(ctx context.Context, in *Input) (*Model, error) {
 	m := &Model{
 		db:           in.db,
 		cfg:          in.cfg,
+		replayDir:    in.replayDir,
+		savePath:     in.savePath,
+		terminal:     in.terminal,
 		forceQuitKey: key.NewBinding(key.WithKeys(in.cfg.Keys.ForceQuit...)),
 		ctx:          ctx,
 	}
@@ -63,11 +114,30 @@ func NewModel(ctx context.Context, in *Input) (*Model, error) {
 }
 
 func (m *Model) Init() tea.Cmd {
-	return m.initChild()
+	if m.terminal == nil {
+		return m.initChild()
+	}
+	return tea.Batch(m.initChild(), tui.QueryKeyboardEnhancementsCmd(m.terminal))
 }
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	msg = tui.TranslateKeyboardMsg(msg)
+
 	switch msg := msg.(type) {
+	case tui.KeyboardEnhancementsMsg:
+		if !msg.SupportsKeyReleases() {
+			// The terminal supports the protocol, so enable the flags we use then query them again to confirm.
+			if m.keyboardEnabling || m.terminal == nil {
+				return m, nil
+			}
+			m.keyboardEnabling = true
+			return m, tea.Sequence(
+				tui.EnableKeyboardEnhancementsCmd(m.terminal),
+				tui.QueryKeyboardEnhancementsCmd(m.terminal),
+			)
+		}
+		m.keyboard = &msg
+
 	case tui.FatalErrorMsg:
 		m.ExitError = msg
 		return m, tea.Quit
@@ -110,19 +180,55 @@ func (m *Model) setChild(ctx context.Context, mode tui.Mode, switchIn tui.Switch
 		if !ok {
 			return fmt.Errorf("switchIn is not a MenuInput: %w", charmutils.ErrInvalidTypeAssertion)
 		}
-		m.child = views.NewMenuModel(menuIn)
+		var opts []func(*views.MenuModel)
+		if m.savePath != "" && savegame.Exists(m.savePath) {
+			opts = append(opts, views.WithResumeOption())
+		}
+		m.child = views.NewMenuModel(menuIn, opts...)
 
-	case tui.ModeMarathon, tui.ModeSprint, tui.ModeUltra:
+	case tui.ModeMarathon, tui.ModeSprint, tui.ModeUltra, tui.ModeDig, tui.ModeMaster:
 		singleIn, ok := switchIn.(*tui.SingleInput)
 		if !ok {
 			return fmt.Errorf("switchIn is not a SingleInput: %w", charmutils.ErrInvalidTypeAssertion)
 		}
-		child, err := views.NewSingleModel(singleIn, m.cfg)
+		child, err := m.singleChild(ctx, singleIn)
 		if err != nil {
 			return fmt.Errorf("creating single model: %w", err)
 		}
 		m.child = child
 
+	case tui.ModeResume:
+		if _, ok := switchIn.(*tui.ResumeInput); !ok {
+			return fmt.Errorf("switchIn is not a ResumeInput: %w", charmutils.ErrInvalidTypeAssertion)
+		}
+		child, err := m.resumeChild(ctx)
+		if err != nil {
+			return fmt.Errorf("resuming saved game: %w", err)
+		}
+		m.child = child
+
+	case tui.ModeVersus:
+		versusIn, ok := switchIn.(*tui.VersusInput)
+		if !ok {
+			return fmt.Errorf("switchIn is not a VersusInput: %w", charmutils.ErrInvalidTypeAssertion)
+		}
+		child, err := views.NewVersusModel(versusIn, m.cfg)
+		if err != nil {
+			return fmt.Errorf("creating versus model: %w", err)
+		}
+		m.child = child
+
+	case tui.ModeOnline:
+		onlineIn, ok := switchIn.(*tui.OnlineInput)
+		if !ok {
+			return fmt.Errorf("switchIn is not an OnlineInput: %w", charmutils.ErrInvalidTypeAssertion)
+		}
+		child, err := views.NewOnlineModel(onlineIn, m.cfg)
+		if err != nil {
+			return fmt.Errorf("creating online model: %w", err)
+		}
+		m.child = child
+
 	case tui.ModeLeaderboard:
 		leaderboardIn, ok := switchIn.(*tui.LeaderboardInput)
 		if !ok {
@@ -134,17 +240,91 @@ func (m *Model) setChild(ctx context.Context, mode tui.Mode, switchIn tui.Switch
 		}
 		m.child = child
 
+	case tui.ModeReplay:
+		replayIn, ok := switchIn.(*tui.ReplayInput)
+		if !ok {
+			return fmt.Errorf("switchIn is not a ReplayInput: %w", charmutils.ErrInvalidTypeAssertion)
+		}
+		child, err := views.NewReplayModel(replayIn, m.cfg)
+		if err != nil {
+			return fmt.Errorf("creating replay model: %w", err)
+		}
+		m.child = child
+
 	default:
 		return errors.New("invalid Mode")
 	}
 	return nil
 }
 
+// singleChild creates the single model for the given input.
+// The splits of a Sprint are compared against the personal best of the player, if they have completed one.
+func (m *Model) singleChild(
+	ctx context.Context, in *tui.SingleInput, opts ...func(*views.SingleModel),
+) (*views.SingleModel, error) {
+	opts = append(opts, views.WithReplayDir(m.replayDir), views.WithSavePath(m.savePath))
+	size := m.cfg.GetMatrixSize(in.Mode.String())
+	tui.WithDefaultMatrixSize(size.Width, size.Height)(in)
+
+	if in.Mode == tui.ModeSprint && in.Username != "" && m.db != nil {
+		filter := data.ScoreFilter{Name: in.Username}
+		bests, err := data.NewLeaderboardRepository(m.db).Page(ctx, in.GameModeName(), filter, 0, 1)
+		if err != nil {
+			return nil, fmt.Errorf("fetching personal best: %w", err)
+		}
+		if len(bests) == 1 && bests[0].Completed {
+			opts = append(opts, views.WithBestSplits(bests[0].Splits))
+		}
+	}
+
+	return views.NewSingleModel(in, m.cfg, opts...)
+}
+
+// resumeChild creates the single model for the saved game. The save is removed so it can only be resumed once.
+func (m *Model) resumeChild(ctx context.Context) (tea.Model, error) {
+	if m.savePath == "" {
+		return nil, errors.New("no save path")
+	}
+	save, err := savegame.Load(m.savePath)
+	if err != nil {
+		return nil, err
+	}
+	mode, err := tui.ParseMode(save.Mode)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []func(*tui.SingleInput){
+		tui.WithLineGoal(save.LineGoal), tui.WithTimeLimit(save.TimeLimit), tui.WithGarbageLines(save.GarbageLines),
+	}
+	if save.RisingGarbage {
+		opts = append(opts, tui.WithRisingGarbage())
+	}
+	if len(save.Game.Matrix) > 0 {
+		// The size of the saved matrix is used, since it is part of the game mode name.
+		opts = append(opts, tui.WithMatrixSize(len(save.Game.Matrix[0]), len(save.Game.Matrix)/2))
+	}
+	singleIn := tui.NewSingleInput(mode, save.Game.Scoring.Level, save.Username, opts...)
+	child, err := m.singleChild(ctx, singleIn, views.WithResume(save))
+	if err != nil {
+		return nil, fmt.Errorf("creating single model: %w", err)
+	}
+
+	if err = savegame.Remove(m.savePath); err != nil {
+		return nil, err
+	}
+	return child, nil
+}
+
 func (m *Model) initChild() tea.Cmd {
 	var cmds []tea.Cmd
 	cmd := m.child.Init()
 	cmds = append(cmds, cmd)
 	m.child, cmd = m.child.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
 	cmds = append(cmds, cmd)
+	if m.keyboard != nil {
+		m.child, cmd = m.child.Update(*m.keyboard)
+		cmds = append(cmds, cmd)
+	}
 	return tea.Batch(cmds...)
 }
@@ -37,7 +37,7 @@ func SetupInMemoryDB(t *testing.T) *sql.DB {
 	require.NoError(t, err)
 
 	// TODO: replace with t.Context after upgrading to Go 1.24
-	err = data.EnsureTablesExist(context.Background(), db)
+	err = data.Migrate(context.Background(), db)
 	require.NoError(t, err)
 	return db
 }
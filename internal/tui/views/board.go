@@ -0,0 +1,136 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/tui/components"
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris"
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris/modes/single"
+)
+
+// boardRenderer renders the Matrix, Hold and Next Queue of a single player game.
+// This is shared by all views that display a game board.
+type boardRenderer struct {
+	styles          *components.GameStyles
+	nextQueueLength int
+}
+
+func newBoardRenderer(styles *components.GameStyles, nextQueueLength int) *boardRenderer {
+	return &boardRenderer{
+		styles:          styles,
+		nextQueueLength: nextQueueLength,
+	}
+}
+
+// view renders the full board with the given information panel beneath the Hold.
+func (b *boardRenderer) view(game *single.Game, information string) (string, error) {
+	matrixView, err := b.matrixView(game)
+	if err != nil {
+		return "", err
+	}
+
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		lipgloss.JoinVertical(lipgloss.Right, b.holdView(game), information),
+		matrixView,
+		b.bagView(game),
+	), nil
+}
+
+func (b *boardRenderer) matrixView(game *single.Game) (string, error) {
+	matrix, err := game.GetVisibleMatrix()
+	if err != nil {
+		return "", fmt.Errorf("getting visible matrix: %w", err)
+	}
+	return b.renderMatrix(matrix), nil
+}
+
+// renderMatrix renders the given visible Matrix with row indicators.
+func (b *boardRenderer) renderMatrix(matrix tetris.Matrix) string {
+	var output strings.Builder
+	for row := range matrix {
+		for col := range matrix[row] {
+			output.WriteString(b.renderCell(matrix[row][col]))
+		}
+		if row < len(matrix)-1 {
+			output.WriteByte('\n')
+		}
+	}
+
+	var rowIndicator strings.Builder
+	for i := 1; i <= len(matrix); i++ {
+		fmt.Fprintf(&rowIndicator, "%d\n", i)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Center,
+		b.styles.Playfield.Render(output.String()),
+		b.styles.RowIndicator.Render(rowIndicator.String()),
+	)
+}
+
+// garbageMeterView renders a column the height of the visible Matrix, filled from the bottom
+// with the number of garbage lines waiting to be received.
+func (b *boardRenderer) garbageMeterView(pending, height int) string {
+	rows := make([]string, height)
+	for i := range rows {
+		if height-i <= pending {
+			rows[i] = b.styles.GarbageMeter.Render(b.styles.CellChar.Tetriminos)
+		} else {
+			rows[i] = strings.Repeat(" ", lipgloss.Width(b.styles.CellChar.Tetriminos))
+		}
+	}
+	return b.styles.Playfield.Render(strings.Join(rows, "\n"))
+}
+
+func (b *boardRenderer) holdView(game *single.Game) string {
+	label := b.styles.Hold.Label.Render("Hold:")
+	item := b.styles.Hold.Item.Render(b.renderTetrimino(game.GetHoldTetrimino(), 1))
+	output := lipgloss.JoinVertical(lipgloss.Top, label, item)
+	return b.styles.Hold.View.Render(output)
+}
+
+func (b *boardRenderer) bagView(game *single.Game) string {
+	var output strings.Builder
+	output.WriteString("Next:\n")
+	for i, t := range game.GetBagTetriminos() {
+		if i >= b.nextQueueLength {
+			break
+		}
+		output.WriteByte('\n')
+		output.WriteString(b.renderTetrimino(&t, 1))
+	}
+	return b.styles.Bag.Render(output.String())
+}
+
+func (b *boardRenderer) renderTetrimino(t *tetris.Tetrimino, background byte) string {
+	var output strings.Builder
+	for row := range t.Cells {
+		for col := range t.Cells[row] {
+			if t.Cells[row][col] {
+				output.WriteString(b.renderCell(t.Value))
+			} else {
+				output.WriteString(b.renderCell(background))
+			}
+		}
+		output.WriteByte('\n')
+	}
+	return output.String()
+}
+
+func (b *boardRenderer) renderCell(cell byte) string {
+	switch cell {
+	case 0:
+		return b.styles.EmptyCell.Render(b.styles.CellChar.Empty)
+	case 1:
+		return "  "
+	case 'G':
+		return b.styles.GhostCell.Render(b.styles.CellChar.Ghost)
+	default:
+		cellStyle, ok := b.styles.TetriminoCellStyles[cell]
+		if ok {
+			return cellStyle.Render(b.styles.CellChar.Tetriminos)
+		}
+	}
+	return "??"
+}
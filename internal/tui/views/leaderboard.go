@@ -1,63 +1,148 @@
 package views
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/Broderick-Westrope/tetrigo/internal/data"
 	"github.com/Broderick-Westrope/tetrigo/internal/tui"
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris"
+)
+
+const (
+	// leaderboardPageSize is the number of scores on each page of the leaderboard.
+	leaderboardPageSize = 20
+	filterFormWidth     = 40
 )
 
 var _ tea.Model = &LeaderboardModel{}
 
 type LeaderboardModel struct {
+	ctx  context.Context
 	keys *leaderboardKeyMap
 	help help.Model
 
 	repo  *data.LeaderboardRepository
 	table table.Model
 
+	// gameModes are the tabs of the leaderboard, with gameModeIndex being the one displayed.
+	gameModes     []string
+	gameModeIndex int
+	page          int
+	// total is the number of scores in the game mode which match the filter.
+	total int
+	// focusID is the ID of the new entry, which is selected whenever it is displayed.
+	focusID int
+
+	filter     data.ScoreFilter
+	filterData leaderboardFilterData
+	// filterForm edits a copy of the filterData, which replaces it once the form is completed.
+	// It is nil unless the filter is being edited.
+	filterForm  *huh.Form
+	filterDraft *leaderboardFilterData
+
+	username          string
+	showPersonalBests bool
+
 	width  int
 	height int
 }
 
+// leaderboardFilterData is the filter as entered by the user, with empty fields not filtering the scores.
+type leaderboardFilterData struct {
+	Name       string
+	StartLevel string
+	From       string
+	To         string
+}
+
 // TODO: replace all test uses of NewLeaderboardModel after upgrading to Go 1.24
 
 func NewLeaderboardModel(ctx context.Context, in *tui.LeaderboardInput, db *sql.DB) (*LeaderboardModel, error) {
-	repo := data.NewLeaderboardRepository(db)
+	m := &LeaderboardModel{
+		ctx:      ctx,
+		keys:     defaultLeaderboardKeyMap(),
+		help:     help.New(),
+		repo:     data.NewLeaderboardRepository(db),
+		username: in.Username,
+	}
+
+	// Variants of the game modes (eg. "Sprint 20L") are only given a tab once they have a score.
+	gameModes, err := m.repo.GameModes(ctx, data.ScoreFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("fetching game modes: %w", err)
+	}
+	variants := slices.DeleteFunc(gameModes, func(gameMode string) bool {
+		return data.RankingFor(gameMode) == data.RankingDefault
+	})
+	slices.SortFunc(variants, func(a, b string) int {
+		// This orders goals like "20L" before "100L".
+		return cmp.Or(cmp.Compare(len(a), len(b)), strings.Compare(strings.ToLower(a), strings.ToLower(b)))
+	})
+	m.gameModes = []string{
+		tui.ModeMarathon.String(), tui.ModeSprint.String(), tui.ModeUltra.String(), tui.ModeDig.String(),
+		tui.ModeMaster.String(),
+	}
+	for _, gameMode := range append(variants, in.GameMode) {
+		m.gameModes = insertGameModeTab(m.gameModes, gameMode)
+	}
+	m.gameModeIndex = slices.IndexFunc(m.gameModes, func(gameMode string) bool {
+		return strings.EqualFold(gameMode, in.GameMode)
+	})
 
-	var err error
-	newEntryID := 0
 	if in.NewEntry != nil {
 		if in.NewEntry.Name == "" {
 			in.NewEntry.Name = "Anonymous"
 		}
 
-		newEntryID, err = repo.Save(ctx, in.NewEntry)
+		m.focusID, err = m.repo.Save(ctx, in.NewEntry)
 		if err != nil {
 			return nil, fmt.Errorf("saving new entry: %w", err)
 		}
+		rank, err := m.repo.Rank(ctx, in.GameMode, m.focusID)
+		if err != nil {
+			return nil, fmt.Errorf("ranking new entry: %w", err)
+		}
+		m.page = (rank - 1) / leaderboardPageSize
+		m.username = cmp.Or(m.username, in.NewEntry.Name)
 	}
+	m.keys.PersonalBests.SetEnabled(m.username != "")
 
-	scores, err := repo.All(ctx, in.GameMode)
-	if err != nil {
+	if err := m.loadScores(); err != nil {
 		return nil, fmt.Errorf("fetching scores: %w", err)
 	}
+	return m, nil
+}
 
-	return &LeaderboardModel{
-		keys:  defaultLeaderboardKeyMap(),
-		help:  help.New(),
-		repo:  repo,
-		table: buildLeaderboardTable(scores, newEntryID),
-	}, nil
+// insertGameModeTab adds a tab for the game mode if there isn't one, ignoring case.
+// Variants of a game mode are placed after the tabs of the same game mode, and other game modes at the end.
+func insertGameModeTab(tabs []string, gameMode string) []string {
+	if slices.ContainsFunc(tabs, func(tab string) bool { return strings.EqualFold(tab, gameMode) }) {
+		return tabs
+	}
+
+	name, _, _ := strings.Cut(gameMode, " ")
+	i := len(tabs)
+	for j, tab := range tabs {
+		if tabName, _, _ := strings.Cut(tab, " "); strings.EqualFold(tabName, name) {
+			i = j + 1
+		}
+	}
+	return slices.Insert(tabs, i, gameMode)
 }
 
 func (m *LeaderboardModel) Init() tea.Cmd {
@@ -65,19 +150,52 @@ func (m *LeaderboardModel) Init() tea.Cmd {
 }
 
 func (m *LeaderboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	}
+	if m.filterForm != nil {
+		return m.filterUpdate(msg)
+	}
+
+	if msg, ok := msg.(tea.KeyMsg); ok {
 		switch {
 		case key.Matches(msg, m.keys.Exit):
 			return m, tui.SwitchModeCmd(tui.ModeMenu, tui.NewMenuInput())
 		case key.Matches(msg, m.keys.Help):
 			m.help.ShowAll = !m.help.ShowAll
+			return m, nil
+		case key.Matches(msg, m.keys.PersonalBests):
+			m.showPersonalBests = !m.showPersonalBests
+			return m, m.reloadScores()
 		}
 
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
-		m.height = msg.Height
-		return m, nil
+		if !m.showPersonalBests {
+			switch {
+			case key.Matches(msg, m.keys.PrevMode, m.keys.NextMode):
+				step := 1
+				if key.Matches(msg, m.keys.PrevMode) {
+					step = len(m.gameModes) - 1
+				}
+				m.gameModeIndex = (m.gameModeIndex + step) % len(m.gameModes)
+				m.page = 0
+				return m, m.reloadScores()
+			case key.Matches(msg, m.keys.PrevPage):
+				m.page = max(m.page-1, 0)
+				return m, m.reloadScores()
+			case key.Matches(msg, m.keys.NextPage):
+				m.page = min(m.page+1, m.pageCount()-1)
+				return m, m.reloadScores()
+			case key.Matches(msg, m.keys.Filter):
+				return m, m.openFilterForm()
+			case key.Matches(msg, m.keys.ClearFilter):
+				m.filter = data.ScoreFilter{}
+				m.filterData = leaderboardFilterData{}
+				m.page = 0
+				return m, m.reloadScores()
+			}
+		}
 	}
 
 	var cmd tea.Cmd
@@ -85,36 +203,298 @@ func (m *LeaderboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+func (m *LeaderboardModel) filterUpdate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, m.keys.Exit) {
+		m.filterForm = nil
+		return m, nil
+	}
+
+	form, cmd := m.filterForm.Update(msg)
+	if f, ok := form.(*huh.Form); ok {
+		m.filterForm = f
+	}
+	if m.filterForm.State != huh.StateCompleted {
+		return m, cmd
+	}
+
+	filter, err := m.filterDraft.parse()
+	if err != nil {
+		// The form validates each field, so this should not happen.
+		return m, tui.FatalErrorCmd(fmt.Errorf("parsing leaderboard filter: %w", err))
+	}
+	m.filter = filter
+	m.filterData = *m.filterDraft
+	m.filterForm = nil
+	m.page = 0
+	return m, m.reloadScores()
+}
+
+func (m *LeaderboardModel) openFilterForm() tea.Cmd {
+	draft := m.filterData
+	m.filterDraft = &draft
+	m.filterForm = huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().Value(&draft.Name).
+				Title("Name:").CharLimit(100),
+			huh.NewInput().Value(&draft.StartLevel).
+				Title("Starting Level:").Placeholder("any").
+				Validate(func(s string) error {
+					_, err := parseFilterLevel(s)
+					return err
+				}),
+			huh.NewInput().Value(&draft.From).
+				Title("From Date:").Placeholder("YYYY-MM-DD").
+				Validate(func(s string) error {
+					_, err := parseFilterDate(s)
+					return err
+				}),
+			huh.NewInput().Value(&draft.To).
+				Title("To Date:").Placeholder("YYYY-MM-DD").
+				Validate(func(s string) error {
+					_, err := parseFilterDate(s)
+					return err
+				}),
+		),
+	).WithKeyMap(m.keys.formKeys).WithWidth(filterFormWidth).WithShowHelp(false)
+	return m.filterForm.Init()
+}
+
+// parse returns the filter for the entered data.
+// The To date is inclusive, so the filter matches scores saved before the end of that day.
+func (d *leaderboardFilterData) parse() (data.ScoreFilter, error) {
+	var filter data.ScoreFilter
+	var err error
+	filter.Name = strings.TrimSpace(d.Name)
+	if filter.StartLevel, err = parseFilterLevel(d.StartLevel); err != nil {
+		return data.ScoreFilter{}, err
+	}
+	if filter.From, err = parseFilterDate(d.From); err != nil {
+		return data.ScoreFilter{}, err
+	}
+	if filter.To, err = parseFilterDate(d.To); err != nil {
+		return data.ScoreFilter{}, err
+	}
+	if !filter.To.IsZero() {
+		filter.To = filter.To.AddDate(0, 0, 1)
+	}
+	return filter, nil
+}
+
+// String returns a summary of the non-empty fields.
+func (d *leaderboardFilterData) String() string {
+	var parts []string
+	if name := strings.TrimSpace(d.Name); name != "" {
+		parts = append(parts, "name "+name)
+	}
+	if d.StartLevel != "" {
+		parts = append(parts, "level "+d.StartLevel)
+	}
+	if d.From != "" {
+		parts = append(parts, "from "+d.From)
+	}
+	if d.To != "" {
+		parts = append(parts, "to "+d.To)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// parseFilterLevel returns the starting level entered into the filter form, or 0 if it is empty.
+func parseFilterLevel(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	level, err := strconv.Atoi(s)
+	if err != nil || level < 1 {
+		return 0, errors.New("level must be a positive number")
+	}
+	return level, nil
+}
+
+// parseFilterDate returns the start of the local date entered into the filter form, or the zero time if it is empty.
+func parseFilterDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.ParseInLocation(time.DateOnly, s, time.Local)
+	if err != nil {
+		return time.Time{}, errors.New("date must be in the format YYYY-MM-DD")
+	}
+	return date, nil
+}
+
+// reloadScores is loadScores for use within Update.
+func (m *LeaderboardModel) reloadScores() tea.Cmd {
+	if err := m.loadScores(); err != nil {
+		return tui.FatalErrorCmd(fmt.Errorf("fetching scores: %w", err))
+	}
+	return nil
+}
+
+// loadScores fetches the current page of scores (or the personal bests) and builds the table to display them.
+func (m *LeaderboardModel) loadScores() error {
+	if m.showPersonalBests {
+		scores, err := m.repo.PersonalBests(m.ctx, m.username)
+		if err != nil {
+			return err
+		}
+		m.table = buildScoreTable(personalBestColumns, scores, m.focusID)
+		return nil
+	}
+
+	gameMode := m.gameModes[m.gameModeIndex]
+	var err error
+	m.total, err = m.repo.Count(m.ctx, gameMode, m.filter)
+	if err != nil {
+		return err
+	}
+	m.page = min(m.page, m.pageCount()-1)
+
+	scores, err := m.repo.Page(m.ctx, gameMode, m.filter, m.page*leaderboardPageSize, leaderboardPageSize)
+	if err != nil {
+		return err
+	}
+	m.table = buildScoreTable(leaderboardColumnsFor(gameMode), scores, m.focusID)
+	return nil
+}
+
+// pageCount returns the number of pages of scores, which is at least 1.
+func (m *LeaderboardModel) pageCount() int {
+	return max((m.total+leaderboardPageSize-1)/leaderboardPageSize, 1)
+}
+
 func (m *LeaderboardModel) View() string {
-	output := m.table.View() + "\n" + m.help.View(m.keys)
+	var sections []string
+	switch {
+	case m.filterForm != nil:
+		sections = append(sections, "Filter "+m.gameModes[m.gameModeIndex]+" Scores", m.filterForm.View())
+
+	case m.showPersonalBests:
+		sections = append(sections, "Personal Bests: "+m.username, m.table.View())
+
+	default:
+		sections = append(sections, m.tabsView())
+		if filter := m.filterData.String(); filter != "" {
+			sections = append(sections, "Filter: "+filter)
+		}
+		sections = append(sections,
+			m.table.View(),
+			fmt.Sprintf("Page %d/%d (%d scores)", m.page+1, m.pageCount(), m.total),
+		)
+	}
+
+	output := strings.Join(append(sections, m.help.View(m.keys)), "\n")
 	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, output)
 }
 
-func buildLeaderboardTable(scores []data.Score, focusID int) table.Model {
-	cols := []table.Column{
-		{Title: "Rank", Width: 4},
-		{Title: "Name", Width: 10},
-		{Title: "Time", Width: 10},
-		{Title: "Score", Width: 10},
-		{Title: "Lines", Width: 5},
-		{Title: "Level", Width: 5},
+func (m *LeaderboardModel) tabsView() string {
+	tabStyle := lipgloss.NewStyle().Padding(0, 1)
+	activeTabStyle := tabStyle.
+		Foreground(lipgloss.Color("229")).
+		Background(lipgloss.Color("57"))
+
+	tabs := make([]string, len(m.gameModes))
+	for i, gameMode := range m.gameModes {
+		if i == m.gameModeIndex {
+			tabs[i] = activeTabStyle.Render("[" + gameMode + "]")
+		} else {
+			tabs[i] = tabStyle.Render(gameMode)
+		}
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, tabs...)
+}
+
+// leaderboardColumn is a column of the leaderboard table, along with how to display a score within it.
+type leaderboardColumn struct {
+	title string
+	width int
+	value func(s *data.Score) string
+}
+
+var (
+	rankColumn = leaderboardColumn{"Rank", 4, func(s *data.Score) string { return strconv.Itoa(s.Rank) }}
+	nameColumn = leaderboardColumn{"Name", 10, func(s *data.Score) string { return s.Name }}
+	timeColumn = leaderboardColumn{"Time", 10, func(s *data.Score) string { return s.Time.String() }}
+	// sprintTimeColumn only displays the time of completed runs, since only these are ranked by it.
+	sprintTimeColumn = leaderboardColumn{"Time", 10, func(s *data.Score) string {
+		if !s.Completed {
+			return "DNF"
+		}
+		return s.Time.String()
+	}}
+	scoreColumn  = leaderboardColumn{"Score", 10, func(s *data.Score) string { return strconv.Itoa(s.Score) }}
+	linesColumn  = leaderboardColumn{"Lines", 5, func(s *data.Score) string { return strconv.Itoa(s.Lines) }}
+	levelColumn  = leaderboardColumn{"Level", 5, func(s *data.Score) string { return strconv.Itoa(s.Level) }}
+	piecesColumn = leaderboardColumn{"Pieces", 6, func(s *data.Score) string { return strconv.Itoa(s.Pieces) }}
+	ppsColumn    = leaderboardColumn{"PPS", 5, func(s *data.Score) string {
+		return strconv.FormatFloat(s.PPS, 'f', 2, 64)
+	}}
+	tetrisesColumn = leaderboardColumn{"Tetrises", 8, func(s *data.Score) string { return strconv.Itoa(s.Tetrises) }}
+	modeColumn     = leaderboardColumn{"Mode", 10, func(s *data.Score) string { return s.GameMode }}
+	gradeColumn    = leaderboardColumn{"Grade", 5, func(s *data.Score) string {
+		return tetris.MasterGradeFor(s.Score, s.Completed).String()
+	}}
+	// replayColumn shows whether each score matched its replay when it was saved, without re-simulating it.
+	replayColumn = leaderboardColumn{"Replay", 8, func(s *data.Score) string {
+		switch s.ReplayCheck {
+		case data.ReplayMatches:
+			return "match"
+		case data.ReplayMismatch:
+			return "MISMATCH"
+		case data.ReplayUnchecked:
+		}
+		return "-"
+	}}
+)
+
+// personalBestColumns are the columns displayed for the personal bests of a player, which span every game mode.
+var personalBestColumns = []leaderboardColumn{
+	modeColumn, rankColumn, timeColumn, scoreColumn, linesColumn, levelColumn, replayColumn,
+}
+
+// leaderboardColumnsFor returns the columns displayed for the given game mode, matching how its scores are ranked.
+func leaderboardColumnsFor(gameMode string) []leaderboardColumn {
+	switch data.RankingFor(gameMode) {
+	case data.RankingMarathon:
+		return []leaderboardColumn{
+			rankColumn, nameColumn, scoreColumn, linesColumn, levelColumn, timeColumn, ppsColumn, replayColumn,
+		}
+	case data.RankingSprint, data.RankingDig:
+		return []leaderboardColumn{
+			rankColumn, nameColumn, sprintTimeColumn, linesColumn, piecesColumn, ppsColumn, replayColumn,
+		}
+	case data.RankingUltra:
+		return []leaderboardColumn{
+			rankColumn, nameColumn, scoreColumn, linesColumn, ppsColumn, tetrisesColumn, replayColumn,
+		}
+	case data.RankingMaster:
+		return []leaderboardColumn{
+			rankColumn, nameColumn, gradeColumn, scoreColumn, levelColumn, timeColumn, replayColumn,
+		}
+	default:
+		return []leaderboardColumn{
+			rankColumn, nameColumn, timeColumn, scoreColumn, linesColumn, levelColumn, replayColumn,
+		}
+	}
+}
+
+func buildScoreTable(columns []leaderboardColumn, scores []data.Score, focusID int) table.Model {
+	cols := make([]table.Column, len(columns))
+	for i, c := range columns {
+		cols[i] = table.Column{Title: c.title, Width: c.width}
 	}
 
 	focusIndex := 0
 	rows := make([]table.Row, len(scores))
-	for i, s := range scores {
-		if s.ID == focusID {
+	for i := range scores {
+		if scores[i].ID == focusID {
 			focusIndex = i
 		}
 
-		rows[i] = table.Row{
-			strconv.Itoa(s.Rank),
-			s.Name,
-			s.Time.String(),
-			strconv.Itoa(s.Score),
-			strconv.Itoa(s.Lines),
-			strconv.Itoa(s.Level),
+		row := make(table.Row, len(columns))
+		for j, c := range columns {
+			row[j] = c.value(&scores[i])
 		}
+		rows[i] = row
 	}
 
 	s := table.DefaultStyles()
@@ -2,26 +2,41 @@ package views
 
 import (
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/huh"
 )
 
 type leaderboardKeyMap struct {
-	Exit  key.Binding
-	Help  key.Binding
-	Left  key.Binding
-	Right key.Binding
-	Up    key.Binding
-	Down  key.Binding
+	Exit          key.Binding
+	Help          key.Binding
+	PrevMode      key.Binding
+	NextMode      key.Binding
+	Up            key.Binding
+	Down          key.Binding
+	PrevPage      key.Binding
+	NextPage      key.Binding
+	Filter        key.Binding
+	ClearFilter   key.Binding
+	PersonalBests key.Binding
+	formKeys      *huh.KeyMap
 }
 
 func defaultLeaderboardKeyMap() *leaderboardKeyMap {
-	return &leaderboardKeyMap{
-		Exit:  key.NewBinding(key.WithKeys("esc"), key.WithHelp("escape", "exit")),
-		Help:  key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
-		Left:  key.NewBinding(key.WithKeys("left"), key.WithHelp("left arrow", "move left")),
-		Right: key.NewBinding(key.WithKeys("right"), key.WithHelp("right arrow", "move right")),
-		Up:    key.NewBinding(key.WithKeys("up"), key.WithHelp("up arrow", "move up")),
-		Down:  key.NewBinding(key.WithKeys("down"), key.WithHelp("down arrow", "move down")),
+	keys := &leaderboardKeyMap{
+		Exit:          key.NewBinding(key.WithKeys("esc"), key.WithHelp("escape", "exit")),
+		Help:          key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
+		PrevMode:      key.NewBinding(key.WithKeys("left", "shift+tab"), key.WithHelp("left arrow", "previous mode")),
+		NextMode:      key.NewBinding(key.WithKeys("right", "tab"), key.WithHelp("right arrow", "next mode")),
+		Up:            key.NewBinding(key.WithKeys("up"), key.WithHelp("up arrow", "move up")),
+		Down:          key.NewBinding(key.WithKeys("down"), key.WithHelp("down arrow", "move down")),
+		PrevPage:      key.NewBinding(key.WithKeys("pgup", "["), key.WithHelp("[", "previous page")),
+		NextPage:      key.NewBinding(key.WithKeys("pgdown", "]"), key.WithHelp("]", "next page")),
+		Filter:        key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "filter")),
+		ClearFilter:   key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "clear filter")),
+		PersonalBests: key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "personal bests")),
+		formKeys:      huh.NewDefaultKeyMap(),
 	}
+	keys.formKeys.Quit.SetEnabled(false)
+	return keys
 }
 
 func (k *leaderboardKeyMap) ShortHelp() []key.Binding {
@@ -38,12 +53,21 @@ func (k *leaderboardKeyMap) FullHelp() [][]key.Binding {
 			k.Help,
 		},
 		{
-			k.Left,
-			k.Right,
+			k.PrevMode,
+			k.NextMode,
 		},
 		{
 			k.Up,
 			k.Down,
 		},
+		{
+			k.PrevPage,
+			k.NextPage,
+		},
+		{
+			k.Filter,
+			k.ClearFilter,
+			k.PersonalBests,
+		},
 	}
 }
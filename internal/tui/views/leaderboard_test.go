@@ -7,10 +7,12 @@ import (
 	"time"
 
 	"github.com/Broderick-Westrope/tetrigo/internal/data"
+	"github.com/Broderick-Westrope/tetrigo/internal/replay"
 	"github.com/Broderick-Westrope/tetrigo/internal/tui"
 	"github.com/Broderick-Westrope/tetrigo/internal/tui/testutils"
 	"github.com/Broderick-Westrope/x/exp/teatest"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -61,6 +63,53 @@ func TestLeaderboard_TableEntries(t *testing.T) {
 	}
 }
 
+func TestLeaderboard_ModeColumns(t *testing.T) {
+	tt := map[string]struct {
+		gameMode string
+	}{
+		"marathon": {gameMode: "Marathon"},
+		"sprint":   {gameMode: "Sprint"},
+		"ultra":    {gameMode: "Ultra"},
+		"dig":      {gameMode: "Dig"},
+		"master":   {gameMode: "Master"},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			db := testutils.SetupInMemoryDB(t)
+			repo := data.NewLeaderboardRepository(db)
+			ctx := context.TODO()
+
+			for i := range 3 {
+				_, err := repo.Save(ctx, &data.Score{
+					GameMode:  tc.gameMode,
+					Name:      fmt.Sprintf("user-%d", i),
+					Time:      time.Second * time.Duration(30+i*10),
+					Score:     (i + 1) * 1000,
+					Lines:     20 + i*10,
+					Level:     i + 2,
+					Completed: i > 0,
+					Pieces:    50 + i*25,
+					PPS:       float64(50+i*25) / float64(30+i*10),
+					Tetrises:  i,
+				})
+				require.NoError(t, err)
+			}
+
+			m, err := NewLeaderboardModel(ctx, &tui.LeaderboardInput{
+				GameMode: tc.gameMode,
+			}, db)
+			require.NoError(t, err)
+
+			tm := teatest.NewTestModel(t, m)
+			tm.Send(tea.Quit())
+
+			outBytes := []byte(tm.FinalModel(t).View())
+			teatest.RequireEqualOutput(t, outBytes)
+		})
+	}
+}
+
 func TestLeaderboard_NewEntryInEmptyTable(t *testing.T) {
 	db := testutils.SetupInMemoryDB(t)
 
@@ -154,3 +203,270 @@ func TestLeaderboard_SwitchModeMsg(t *testing.T) {
 		t.Fatal("Timeout waiting for switch mode message")
 	}
 }
+
+func TestLeaderboard_ModeTabs(t *testing.T) {
+	db := testutils.SetupInMemoryDB(t)
+	repo := data.NewLeaderboardRepository(db)
+	ctx := context.TODO()
+
+	for _, gameMode := range []string{"Marathon", "Sprint", "Ultra", "Dig", "Master"} {
+		_, err := repo.Save(ctx, &data.Score{GameMode: gameMode, Name: "user-" + gameMode, Lines: 40, Completed: true})
+		require.NoError(t, err)
+	}
+
+	// The game mode is matched to its tab ignoring case.
+	m, err := NewLeaderboardModel(ctx, &tui.LeaderboardInput{GameMode: "marathon"}, db)
+	require.NoError(t, err)
+	require.Len(t, m.gameModes, 5)
+
+	for _, tc := range []struct {
+		msg      tea.KeyMsg
+		wantMode string
+	}{
+		{msg: tea.KeyMsg{Type: tea.KeyRight}, wantMode: "Sprint"},
+		{msg: tea.KeyMsg{Type: tea.KeyTab}, wantMode: "Ultra"},
+		{msg: tea.KeyMsg{Type: tea.KeyRight}, wantMode: "Dig"},
+		{msg: tea.KeyMsg{Type: tea.KeyRight}, wantMode: "Master"},
+		{msg: tea.KeyMsg{Type: tea.KeyRight}, wantMode: "Marathon"},
+		{msg: tea.KeyMsg{Type: tea.KeyLeft}, wantMode: "Master"},
+		{msg: tea.KeyMsg{Type: tea.KeyShiftTab}, wantMode: "Dig"},
+	} {
+		_, cmd := m.Update(tc.msg)
+		require.Nil(t, cmd)
+		assert.Equal(t, tc.wantMode, m.gameModes[m.gameModeIndex])
+		require.Len(t, m.table.Rows(), 1)
+		assert.Equal(t, "user-"+tc.wantMode, m.table.Rows()[0][1])
+	}
+}
+
+func TestLeaderboard_VariantTabs(t *testing.T) {
+	db := testutils.SetupInMemoryDB(t)
+	repo := data.NewLeaderboardRepository(db)
+	ctx := context.TODO()
+
+	for _, gameMode := range []string{"Sprint 100L", "Marathon", "Dig Rising", "Sprint 20L", "unknown"} {
+		_, err := repo.Save(ctx, &data.Score{GameMode: gameMode, Name: "testuser"})
+		require.NoError(t, err)
+	}
+
+	m, err := NewLeaderboardModel(ctx, &tui.LeaderboardInput{GameMode: "Custom"}, db)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"Marathon", "Sprint", "Sprint 20L", "Sprint 100L", "Ultra", "Dig", "Dig Rising", "Master", "Custom",
+	}, m.gameModes)
+	assert.Equal(t, "Custom", m.gameModes[m.gameModeIndex])
+}
+
+func TestLeaderboard_Paging(t *testing.T) {
+	db := testutils.SetupInMemoryDB(t)
+	repo := data.NewLeaderboardRepository(db)
+	ctx := context.TODO()
+
+	for i := range 50 {
+		_, err := repo.Save(ctx, &data.Score{GameMode: t.Name(), Name: fmt.Sprintf("user-%d", i), Score: i})
+		require.NoError(t, err)
+	}
+
+	m, err := NewLeaderboardModel(ctx, &tui.LeaderboardInput{GameMode: t.Name()}, db)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		msg       tea.KeyMsg
+		wantPage  int
+		wantRows  int
+		wantFirst string
+	}{
+		{msg: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("]")}, wantPage: 1, wantRows: 20, wantFirst: "21"},
+		{msg: tea.KeyMsg{Type: tea.KeyPgDown}, wantPage: 2, wantRows: 10, wantFirst: "41"},
+		{msg: tea.KeyMsg{Type: tea.KeyPgDown}, wantPage: 2, wantRows: 10, wantFirst: "41"},
+		{msg: tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("[")}, wantPage: 1, wantRows: 20, wantFirst: "21"},
+		{msg: tea.KeyMsg{Type: tea.KeyPgUp}, wantPage: 0, wantRows: 20, wantFirst: "1"},
+		{msg: tea.KeyMsg{Type: tea.KeyPgUp}, wantPage: 0, wantRows: 20, wantFirst: "1"},
+	} {
+		_, cmd := m.Update(tc.msg)
+		require.Nil(t, cmd)
+		assert.Equal(t, tc.wantPage, m.page)
+		require.Len(t, m.table.Rows(), tc.wantRows)
+		assert.Equal(t, tc.wantFirst, m.table.Rows()[0][0])
+	}
+}
+
+func TestLeaderboard_Filter(t *testing.T) {
+	db := testutils.SetupInMemoryDB(t)
+	repo := data.NewLeaderboardRepository(db)
+	ctx := context.TODO()
+
+	for i := range 10 {
+		_, err := repo.Save(ctx, &data.Score{
+			GameMode:   t.Name(),
+			Name:       fmt.Sprintf("user-%d", i%3),
+			Score:      i * 100,
+			StartLevel: i%2 + 1,
+		})
+		require.NoError(t, err)
+	}
+
+	m, err := NewLeaderboardModel(ctx, &tui.LeaderboardInput{GameMode: t.Name()}, db)
+	require.NoError(t, err)
+	tm := teatest.NewTestModel(t, m)
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	time.Sleep(10 * time.Millisecond)
+
+	// Name
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("USER-1")})
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	time.Sleep(10 * time.Millisecond)
+
+	// Starting level
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")})
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	time.Sleep(10 * time.Millisecond)
+
+	// From and to dates
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	time.Sleep(10 * time.Millisecond)
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	time.Sleep(10 * time.Millisecond)
+
+	tm.Send(tea.Quit())
+
+	// Scores 1, 4 and 7 are by user-1, but only 1 and 7 started at level 2. They keep their overall ranks.
+	outBytes := []byte(tm.FinalModel(t).View())
+	teatest.RequireEqualOutput(t, outBytes)
+}
+
+func TestLeaderboard_ClearFilter(t *testing.T) {
+	db := testutils.SetupInMemoryDB(t)
+	repo := data.NewLeaderboardRepository(db)
+	ctx := context.TODO()
+
+	for i := range 3 {
+		_, err := repo.Save(ctx, &data.Score{GameMode: t.Name(), Name: fmt.Sprintf("user-%d", i)})
+		require.NoError(t, err)
+	}
+
+	m, err := NewLeaderboardModel(ctx, &tui.LeaderboardInput{GameMode: t.Name()}, db)
+	require.NoError(t, err)
+	m.filter = data.ScoreFilter{Name: "user-1"}
+	m.filterData = leaderboardFilterData{Name: "user-1"}
+	require.NoError(t, m.loadScores())
+	require.Len(t, m.table.Rows(), 1)
+	assert.Contains(t, m.View(), "Filter: name user-1")
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	require.Nil(t, cmd)
+	assert.Len(t, m.table.Rows(), 3)
+	assert.NotContains(t, m.View(), "Filter:")
+}
+
+func TestLeaderboardFilterData_Parse(t *testing.T) {
+	tt := map[string]struct {
+		data    leaderboardFilterData
+		want    data.ScoreFilter
+		wantErr bool
+	}{
+		"empty": {
+			want: data.ScoreFilter{},
+		},
+		"all fields": {
+			data: leaderboardFilterData{Name: " alice ", StartLevel: "3", From: "2025-01-02", To: "2025-01-31"},
+			want: data.ScoreFilter{
+				Name:       "alice",
+				StartLevel: 3,
+				From:       time.Date(2025, time.January, 2, 0, 0, 0, 0, time.Local),
+				// The to date is inclusive.
+				To: time.Date(2025, time.February, 1, 0, 0, 0, 0, time.Local),
+			},
+		},
+		"invalid level": {
+			data:    leaderboardFilterData{StartLevel: "zero"},
+			wantErr: true,
+		},
+		"non-positive level": {
+			data:    leaderboardFilterData{StartLevel: "0"},
+			wantErr: true,
+		},
+		"invalid date": {
+			data:    leaderboardFilterData{From: "02/01/2025"},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.data.parse()
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestLeaderboard_PersonalBests(t *testing.T) {
+	db := testutils.SetupInMemoryDB(t)
+	repo := data.NewLeaderboardRepository(db)
+	ctx := context.TODO()
+
+	for _, s := range []data.Score{
+		{GameMode: "Marathon", Name: "user-new", Time: time.Minute, Score: 3000, Lines: 30, Level: 4},
+		{GameMode: "Marathon", Name: "user-other", Time: time.Minute, Score: 9000, Lines: 60, Level: 7},
+		{GameMode: "Sprint", Name: "user-new", Time: 90 * time.Second, Score: 2000, Lines: 40, Level: 5, Completed: true},
+	} {
+		_, err := repo.Save(ctx, &s)
+		require.NoError(t, err)
+	}
+
+	m, err := NewLeaderboardModel(ctx, &tui.LeaderboardInput{
+		GameMode: "Marathon",
+		NewEntry: &data.Score{
+			GameMode: "Marathon",
+			Name:     "user-new",
+			Time:     time.Minute,
+			Score:    1000,
+			Lines:    10,
+			Level:    2,
+		},
+	}, db)
+	require.NoError(t, err)
+
+	tm := teatest.NewTestModel(t, m)
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	time.Sleep(10 * time.Millisecond)
+	tm.Send(tea.Quit())
+
+	outBytes := []byte(tm.FinalModel(t).View())
+	teatest.RequireEqualOutput(t, outBytes)
+}
+
+func TestLeaderboard_PersonalBestsDisabledWithoutUsername(t *testing.T) {
+	db := testutils.SetupInMemoryDB(t)
+
+	m, err := NewLeaderboardModel(context.TODO(), &tui.LeaderboardInput{GameMode: t.Name()}, db)
+	require.NoError(t, err)
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	assert.False(t, m.showPersonalBests)
+}
+
+func TestLeaderboard_ReplayColumn(t *testing.T) {
+	tt := map[string]struct {
+		check data.ReplayCheck
+		want  string
+	}{
+		"matches":   {check: data.ReplayMatches, want: "match"},
+		"mismatch":  {check: data.ReplayMismatch, want: "MISMATCH"},
+		"unchecked": {check: data.ReplayUnchecked, want: "-"},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			// The stored check is shown without re-simulating the replay, which doesn't match this score.
+			s := &data.Score{ReplayCheck: tc.check, Replay: &replay.Replay{}}
+			assert.Equal(t, tc.want, replayColumn.value(s))
+		})
+	}
+}
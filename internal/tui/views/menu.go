@@ -3,6 +3,8 @@ package views
 import (
 	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/Broderick-Westrope/charmutils"
 	"github.com/charmbracelet/bubbles/key"
@@ -23,11 +25,15 @@ const (
 `
 )
 
+// customLineGoal is the value of the line goal option which lets the user enter their own goal.
+const customLineGoal = -1
+
 var _ tea.Model = &MenuModel{}
 
 type MenuModel struct {
 	form                   *huh.Form
 	hasAnnouncedCompletion bool
+	canResume              bool
 	keys                   *menuKeyMap
 	formData               *MenuFormData
 
@@ -39,37 +45,143 @@ type MenuFormData struct {
 	Username string
 	GameMode tui.Mode
 	Level    int
+	// LineGoal is the line goal of a Sprint, or customLineGoal if CustomLineGoal should be used.
+	LineGoal       int
+	CustomLineGoal string
+	// TimeLimit is the time limit of an Ultra.
+	TimeLimit time.Duration
+	// GarbageLines and RisingGarbage are the options of a Dig.
+	GarbageLines  int
+	RisingGarbage bool
 }
 
-func NewMenuModel(_ *tui.MenuInput) *MenuModel {
-	formData := new(MenuFormData)
+func NewMenuModel(_ *tui.MenuInput, opts ...func(*MenuModel)) *MenuModel {
+	formData := &MenuFormData{
+		LineGoal:     tui.DefaultLineGoal,
+		TimeLimit:    tui.DefaultTimeLimit,
+		GarbageLines: tui.DefaultGarbageLines,
+	}
 	keys := defaultMenuKeyMap()
 
-	return &MenuModel{
+	m := &MenuModel{
 		formData: formData,
-		form: huh.NewForm(
-			huh.NewGroup(
-				huh.NewInput().Value(&formData.Username).
-					Title("Username:").CharLimit(100).
-					Validate(func(s string) error {
-						if len(s) == 0 {
-							return errors.New("empty username not allowed")
-						}
-						return nil
-					}),
-				huh.NewSelect[tui.Mode]().Value(&formData.GameMode).
-					Title("Game Mode:").
-					Options(
-						huh.NewOption("Marathon", tui.ModeMarathon),
-						huh.NewOption("Sprint (40 Lines)", tui.ModeSprint),
-						huh.NewOption("Ultra (Time Trial)", tui.ModeUltra),
-					),
-				huh.NewSelect[int]().Value(&formData.Level).
-					Title("Starting Level:").
-					Options(charmutils.HuhIntRangeOptions(1, 15)...),
-			),
-		).WithKeyMap(keys.formKeys),
-		keys: keys,
+		keys:     keys,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	modeOptions := []huh.Option[tui.Mode]{
+		huh.NewOption("Marathon", tui.ModeMarathon),
+		huh.NewOption("Sprint", tui.ModeSprint),
+		huh.NewOption("Ultra (Time Trial)", tui.ModeUltra),
+		huh.NewOption("Dig (Cheese Race)", tui.ModeDig),
+		huh.NewOption("Master (20G)", tui.ModeMaster),
+		huh.NewOption("Versus (Local)", tui.ModeVersus),
+	}
+	if m.canResume {
+		modeOptions = append(modeOptions, huh.NewOption("Resume Saved Game", tui.ModeResume))
+	}
+
+	m.form = huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().Value(&formData.Username).
+				Title("Username:").CharLimit(100).
+				Validate(func(s string) error {
+					if len(s) == 0 {
+						return errors.New("empty username not allowed")
+					}
+					return nil
+				}),
+			huh.NewSelect[tui.Mode]().Value(&formData.GameMode).
+				Title("Game Mode:").
+				Options(modeOptions...),
+			huh.NewSelect[int]().Value(&formData.Level).
+				Title("Starting Level:").
+				Options(charmutils.HuhIntRangeOptions(1, 15)...),
+		),
+		huh.NewGroup(
+			huh.NewSelect[int]().Value(&formData.LineGoal).
+				Title("Line Goal:").
+				Options(lineGoalOptions()...),
+		).WithHideFunc(func() bool {
+			return formData.GameMode != tui.ModeSprint
+		}),
+		huh.NewGroup(
+			huh.NewInput().Value(&formData.CustomLineGoal).
+				Title("Custom Line Goal:").CharLimit(4).
+				Validate(func(s string) error {
+					_, err := parseLineGoal(s)
+					return err
+				}),
+		).WithHideFunc(func() bool {
+			return formData.GameMode != tui.ModeSprint || formData.LineGoal != customLineGoal
+		}),
+		huh.NewGroup(
+			huh.NewSelect[time.Duration]().Value(&formData.TimeLimit).
+				Title("Time Limit:").
+				Options(timeLimitOptions()...),
+		).WithHideFunc(func() bool {
+			return formData.GameMode != tui.ModeUltra
+		}),
+		huh.NewGroup(
+			huh.NewSelect[int]().Value(&formData.GarbageLines).
+				Title("Garbage Lines:").
+				Options(garbageLinesOptions()...),
+			huh.NewConfirm().Value(&formData.RisingGarbage).
+				Title("Rising Garbage:").
+				Affirmative("Yes").
+				Negative("No"),
+		).WithHideFunc(func() bool {
+			return formData.GameMode != tui.ModeDig
+		}),
+	).WithKeyMap(keys.formKeys)
+	return m
+}
+
+func lineGoalOptions() []huh.Option[int] {
+	options := make([]huh.Option[int], 0, len(tui.LineGoals)+1)
+	for _, goal := range tui.LineGoals {
+		options = append(options, huh.NewOption(fmt.Sprintf("%d Lines", goal), goal))
+	}
+	return append(options, huh.NewOption("Custom", customLineGoal))
+}
+
+func timeLimitOptions() []huh.Option[time.Duration] {
+	options := make([]huh.Option[time.Duration], 0, len(tui.TimeLimits))
+	for _, limit := range tui.TimeLimits {
+		minutes := int(limit / time.Minute)
+		label := fmt.Sprintf("%d Minutes", minutes)
+		if minutes == 1 {
+			label = "1 Minute"
+		}
+		options = append(options, huh.NewOption(label, limit))
+	}
+	return options
+}
+
+func garbageLinesOptions() []huh.Option[int] {
+	options := make([]huh.Option[int], 0, len(tui.GarbageLineCounts))
+	for _, lines := range tui.GarbageLineCounts {
+		options = append(options, huh.NewOption(fmt.Sprintf("%d Lines", lines), lines))
+	}
+	return options
+}
+
+// parseLineGoal returns the custom line goal entered into the form.
+func parseLineGoal(s string) (int, error) {
+	lines, err := strconv.Atoi(s)
+	if err != nil || lines < 1 {
+		return 0, errors.New("line goal must be a positive number")
+	}
+	return lines, nil
+}
+
+// WithResumeOption adds the option to resume the saved game. The username and level of the save are used instead.
+func WithResumeOption() func(*MenuModel) {
+	return func(m *MenuModel) {
+		m.canResume = true
 	}
 }
 
@@ -111,11 +223,42 @@ func (m *MenuModel) announceCompletion() tea.Cmd {
 	m.hasAnnouncedCompletion = true
 
 	switch m.formData.GameMode {
-	case tui.ModeMarathon, tui.ModeSprint, tui.ModeUltra:
+	case tui.ModeMarathon, tui.ModeMaster:
 		in := tui.NewSingleInput(m.formData.GameMode, m.formData.Level, m.formData.Username)
 		return tui.SwitchModeCmd(m.formData.GameMode, in)
 
-	case tui.ModeMenu, tui.ModeLeaderboard:
+	case tui.ModeUltra:
+		in := tui.NewSingleInput(m.formData.GameMode, m.formData.Level, m.formData.Username,
+			tui.WithTimeLimit(m.formData.TimeLimit))
+		return tui.SwitchModeCmd(m.formData.GameMode, in)
+
+	case tui.ModeSprint:
+		lineGoal := m.formData.LineGoal
+		if lineGoal == customLineGoal {
+			var err error
+			if lineGoal, err = parseLineGoal(m.formData.CustomLineGoal); err != nil {
+				// The form validates the custom goal, so this should not happen.
+				return tui.FatalErrorCmd(fmt.Errorf("parsing line goal: %w", err))
+			}
+		}
+		in := tui.NewSingleInput(m.formData.GameMode, m.formData.Level, m.formData.Username, tui.WithLineGoal(lineGoal))
+		return tui.SwitchModeCmd(m.formData.GameMode, in)
+
+	case tui.ModeDig:
+		opts := []func(*tui.SingleInput){tui.WithGarbageLines(m.formData.GarbageLines)}
+		if m.formData.RisingGarbage {
+			opts = append(opts, tui.WithRisingGarbage())
+		}
+		in := tui.NewSingleInput(m.formData.GameMode, m.formData.Level, m.formData.Username, opts...)
+		return tui.SwitchModeCmd(m.formData.GameMode, in)
+
+	case tui.ModeVersus:
+		return tui.SwitchModeCmd(tui.ModeVersus, tui.NewVersusInput(m.formData.Level))
+
+	case tui.ModeResume:
+		return tui.SwitchModeCmd(tui.ModeResume, tui.NewResumeInput())
+
+	case tui.ModeMenu, tui.ModeLeaderboard, tui.ModeReplay, tui.ModeOnline:
 		fallthrough
 	default:
 		return tui.FatalErrorCmd(fmt.Errorf("invalid mode for starting game %q", m.formData.GameMode))
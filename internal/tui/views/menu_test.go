@@ -48,6 +48,10 @@ func TestMenu_SwitchModeMsg(t *testing.T) {
 			return 1
 		case tui.ModeUltra:
 			return 2
+		case tui.ModeDig:
+			return 3
+		case tui.ModeMaster:
+			return 4
 		case tui.ModeMenu:
 			fallthrough
 		case tui.ModeLeaderboard:
@@ -61,6 +65,17 @@ func TestMenu_SwitchModeMsg(t *testing.T) {
 		username string
 		mode     tui.Mode
 		level    int
+		// lineGoalMoveDownCount is how far to move down from the default line goal of a Sprint.
+		lineGoalMoveDownCount int
+		customLineGoal        string
+		wantLineGoal          int
+		// timeLimitMoveDownCount is how far to move down from the default time limit of an Ultra.
+		timeLimitMoveDownCount int
+		wantTimeLimit          time.Duration
+		// garbageMoveDownCount is how far to move down from the default garbage lines of a Dig.
+		garbageMoveDownCount int
+		risingGarbage        bool
+		wantGarbageLines     int
 	}{
 		"marathon; level 1": {
 			username: "testuser",
@@ -68,14 +83,57 @@ func TestMenu_SwitchModeMsg(t *testing.T) {
 			level:    1,
 		},
 		"sprint; level 3": {
-			username: "Perry_Crona@hotmail.com",
-			mode:     tui.ModeSprint,
-			level:    3,
+			username:     "Perry_Crona@hotmail.com",
+			mode:         tui.ModeSprint,
+			level:        3,
+			wantLineGoal: tui.DefaultLineGoal,
+		},
+		"sprint; 100 lines": {
+			username:              "testuser",
+			mode:                  tui.ModeSprint,
+			level:                 1,
+			lineGoalMoveDownCount: 1,
+			wantLineGoal:          100,
+		},
+		"sprint; custom lines": {
+			username:              "testuser",
+			mode:                  tui.ModeSprint,
+			level:                 1,
+			lineGoalMoveDownCount: 2,
+			customLineGoal:        "25",
+			wantLineGoal:          25,
 		},
 		"ultra; level 15": {
+			username:      "testuser",
+			mode:          tui.ModeUltra,
+			level:         15,
+			wantTimeLimit: tui.DefaultTimeLimit,
+		},
+		"ultra; 5 minutes": {
+			username:               "testuser",
+			mode:                   tui.ModeUltra,
+			level:                  1,
+			timeLimitMoveDownCount: 2,
+			wantTimeLimit:          5 * time.Minute,
+		},
+		"dig; level 2": {
+			username:         "testuser",
+			mode:             tui.ModeDig,
+			level:            2,
+			wantGarbageLines: tui.DefaultGarbageLines,
+		},
+		"dig; 18 lines rising": {
+			username:             "testuser",
+			mode:                 tui.ModeDig,
+			level:                1,
+			garbageMoveDownCount: 1,
+			risingGarbage:        true,
+			wantGarbageLines:     18,
+		},
+		"master; level 1": {
 			username: "testuser",
-			mode:     tui.ModeUltra,
-			level:    15,
+			mode:     tui.ModeMaster,
+			level:    1,
 		},
 	}
 
@@ -120,6 +178,47 @@ func TestMenu_SwitchModeMsg(t *testing.T) {
 			tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
 			time.Sleep(10 * time.Millisecond)
 
+			// Select line goal
+			if tc.mode == tui.ModeSprint {
+				for range tc.lineGoalMoveDownCount {
+					tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+					time.Sleep(10 * time.Millisecond)
+				}
+				tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+				time.Sleep(10 * time.Millisecond)
+			}
+			if tc.customLineGoal != "" {
+				tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(tc.customLineGoal)})
+				tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			// Select time limit
+			if tc.mode == tui.ModeUltra {
+				for range tc.timeLimitMoveDownCount {
+					tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+					time.Sleep(10 * time.Millisecond)
+				}
+				tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			// Select garbage lines and rising garbage
+			if tc.mode == tui.ModeDig {
+				for range tc.garbageMoveDownCount {
+					tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+					time.Sleep(10 * time.Millisecond)
+				}
+				tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+				time.Sleep(10 * time.Millisecond)
+				if tc.risingGarbage {
+					tm.Send(tea.KeyMsg{Type: tea.KeyLeft})
+					time.Sleep(10 * time.Millisecond)
+				}
+				tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+				time.Sleep(10 * time.Millisecond)
+			}
+
 			// Wait for switch mode message with timeout
 			select {
 			case switchModeMsg := <-switchModeMsgCh:
@@ -131,6 +230,10 @@ func TestMenu_SwitchModeMsg(t *testing.T) {
 				assert.Equal(t, tc.mode, singleInput.Mode)
 				assert.Equal(t, tc.level, singleInput.Level)
 				assert.Equal(t, tc.username, singleInput.Username)
+				assert.Equal(t, tc.wantLineGoal, singleInput.LineGoal)
+				assert.Equal(t, tc.wantTimeLimit, singleInput.TimeLimit)
+				assert.Equal(t, tc.wantGarbageLines, singleInput.GarbageLines)
+				assert.Equal(t, tc.risingGarbage, singleInput.RisingGarbage)
 
 			case <-time.After(time.Second):
 				t.Fatal("Timeout waiting for switch mode message")
@@ -138,3 +241,41 @@ func TestMenu_SwitchModeMsg(t *testing.T) {
 		})
 	}
 }
+
+func TestMenu_ResumeOption(t *testing.T) {
+	m := NewMenuModel(&tui.MenuInput{}, WithResumeOption())
+	tm := teatest.NewTestModel(t, m)
+
+	switchModeMsgCh := make(chan tui.SwitchModeMsg, 1)
+	go testutils.WaitForMsgOfType(t, tm, switchModeMsgCh, time.Second)
+
+	teatest.WaitForOutput(t, tm.Output(), func(bytes []byte) bool {
+		return strings.Contains(string(bytes), "Username:")
+	}, teatest.WithDuration(time.Second))
+
+	// Input username
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("testuser")})
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	time.Sleep(10 * time.Millisecond)
+
+	// Select the resume option, which is last
+	for range 6 {
+		tm.Send(tea.KeyMsg{Type: tea.KeyDown})
+		time.Sleep(10 * time.Millisecond)
+	}
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	time.Sleep(10 * time.Millisecond)
+
+	// Select level
+	tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+	time.Sleep(10 * time.Millisecond)
+
+	select {
+	case switchModeMsg := <-switchModeMsgCh:
+		require.Equal(t, tui.ModeResume, switchModeMsg.Target)
+		assert.IsType(t, &tui.ResumeInput{}, switchModeMsg.Input)
+
+	case <-time.After(time.Second):
+		t.Fatal("Timeout waiting for switch mode message")
+	}
+}
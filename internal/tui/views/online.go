@@ -0,0 +1,593 @@
+package views
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Broderick-Westrope/charmutils"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/stopwatch"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/config"
+	"github.com/Broderick-Westrope/tetrigo/internal/netplay"
+	"github.com/Broderick-Westrope/tetrigo/internal/replay"
+	"github.com/Broderick-Westrope/tetrigo/internal/tui"
+	"github.com/Broderick-Westrope/tetrigo/internal/tui/components"
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris"
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris/modes/single"
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris/modes/versus"
+)
+
+const (
+	onlineWinMessage = `
+YOU WIN!
+
+Press EXIT to return to the menu.
+`
+	onlineLoseMessage = `
+YOU LOSE!
+
+Press EXIT to return to the menu.
+`
+	dialTimeout = time.Second * 10
+)
+
+// onlineState is the stage of an online game.
+type onlineState int
+
+const (
+	onlineConnecting onlineState = iota
+	onlineLobby
+	onlinePlaying
+	onlineFinished
+	onlineFailed
+)
+
+type onlineConnectedMsg struct {
+	conn *netplay.Conn
+}
+
+type onlineReceivedMsg struct {
+	msg netplay.Message
+}
+
+type onlineDisconnectedMsg struct {
+	err error
+}
+
+var _ tea.Model = &OnlineModel{}
+
+// OnlineModel is a versus game against a player connected to the same server.
+// The player's game is simulated locally and the opponent's board is displayed from the snapshots they send.
+type OnlineModel struct {
+	addr     string
+	username string
+	conn     *netplay.Conn
+	state    onlineState
+	err      error
+	lobby    []string
+
+	gameIn        single.Input
+	game          *single.Game
+	attack        *versus.Attack
+	holeRand      *rand.Rand
+	fallStopwatch components.Stopwatch
+	fallElapsed   time.Duration
+	startedAt     time.Time
+	hasWon        bool
+
+	opponent         string
+	opponentSnapshot *netplay.Snapshot
+	opponentInputs   int
+
+	board  *boardRenderer
+	styles *components.GameStyles
+	help   help.Model
+	keys   *components.GameKeyMap
+	// softDropHold tracks the soft drop key when soft drop lasts whilst it is held. It is nil when the key toggles.
+	softDropHold *components.KeyHold
+
+	width  int
+	height int
+}
+
+func NewOnlineModel(in *tui.OnlineInput, cfg *config.Config) (*OnlineModel, error) {
+	lockDownMode, err := tetris.ParseLockDownMode(cfg.LockDownMode)
+	if err != nil {
+		return nil, fmt.Errorf("parsing lock down mode: %w", err)
+	}
+
+	styles := components.CreateGameStyles(cfg.Theme)
+	m := &OnlineModel{
+		addr:     in.Addr,
+		username: in.Username,
+		state:    onlineConnecting,
+		// The default Matrix size and Randomizer are always used so that both players receive the same Tetriminos.
+		gameIn: single.Input{
+			Level:          in.Level,
+			MaxLevel:       cfg.MaxLevel,
+			IncreaseLevel:  true,
+			LockDownMode:   lockDownMode,
+			GhostEnabled:   cfg.GhostEnabled,
+			SoftDropFactor: cfg.SoftDropFactor,
+		},
+		board:  newBoardRenderer(styles, cfg.NextQueueLength),
+		styles: styles,
+		help:   help.New(),
+		keys:   components.ConstructGameKeyMap(cfg.Keys),
+	}
+	if cfg.SoftDropMode == config.SoftDropModeHold {
+		m.softDropHold = components.NewKeyHold()
+		m.keys.UseHeldSoftDrop()
+	}
+	return m, nil
+}
+
+func (m *OnlineModel) Init() tea.Cmd {
+	return connectCmd(m.addr, m.username)
+}
+
+func connectCmd(addr, username string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+		defer cancel()
+
+		conn, err := netplay.Dial(ctx, addr, username)
+		if err != nil {
+			return onlineDisconnectedMsg{err: err}
+		}
+		return onlineConnectedMsg{conn: conn}
+	}
+}
+
+// receiveCmd waits for the next Message from the server. It must be reissued after each Message is received.
+func receiveCmd(conn *netplay.Conn) tea.Cmd {
+	return func() tea.Msg {
+		msg, err := conn.Receive()
+		if err != nil {
+			return onlineDisconnectedMsg{err: err}
+		}
+		return onlineReceivedMsg{msg: msg}
+	}
+}
+
+func (m *OnlineModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	var cmds []tea.Cmd
+
+	// Dependencies
+	if m.fallStopwatch != nil {
+		cmd, err := charmutils.UpdateTypedModel(&m.fallStopwatch, msg)
+		if err != nil {
+			cmds = append(cmds, tui.FatalErrorCmd(err))
+		}
+		cmds = append(cmds, cmd)
+	}
+
+	// Operations that can be performed all the time
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.Help):
+			m.help.ShowAll = !m.help.ShowAll
+			return m, tea.Batch(cmds...)
+		case key.Matches(msg, m.keys.ForceQuit):
+			m.disconnect()
+			return m, tea.Quit
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, tea.Batch(cmds...)
+
+	case tui.KeyboardEnhancementsMsg:
+		if m.softDropHold != nil {
+			m.softDropHold.SetReleaseEvents(msg.SupportsKeyReleases())
+		}
+		return m, tea.Batch(cmds...)
+
+	case onlineConnectedMsg:
+		m.conn = msg.conn
+		m.state = onlineLobby
+		cmds = append(cmds, receiveCmd(m.conn))
+		return m, tea.Batch(cmds...)
+
+	case onlineReceivedMsg:
+		m, cmd = m.receivedUpdate(msg.msg)
+		cmds = append(cmds, cmd)
+		if m.conn != nil {
+			cmds = append(cmds, receiveCmd(m.conn))
+		}
+		return m, tea.Batch(cmds...)
+
+	case onlineDisconnectedMsg:
+		if m.state != onlineFinished && m.state != onlineFailed {
+			m.fail(fmt.Errorf("connection lost: %w", msg.err))
+		}
+		return m, tea.Batch(cmds...)
+	}
+
+	switch m.state {
+	case onlinePlaying:
+		m, cmd = m.playingUpdate(msg)
+	case onlineConnecting, onlineLobby, onlineFinished, onlineFailed:
+		if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, m.keys.Exit) {
+			m.disconnect()
+			cmd = tui.SwitchModeCmd(tui.ModeMenu, tui.NewMenuInput())
+		}
+	}
+	cmds = append(cmds, cmd)
+	return m, tea.Batch(cmds...)
+}
+
+// receivedUpdate handles a Message from the server.
+// Messages relayed from the opponent are not checked by the server, so an invalid Message ends the game.
+func (m *OnlineModel) receivedUpdate(msg netplay.Message) (*OnlineModel, tea.Cmd) {
+	if err := msg.Validate(); err != nil {
+		m.fail(fmt.Errorf("received invalid message: %w", err))
+		return m, nil
+	}
+
+	switch msg.Type {
+	case netplay.MessageLobby:
+		m.lobby = msg.Lobby.Players
+
+	case netplay.MessageStart:
+		return m, m.start(msg.Start)
+
+	case netplay.MessageInput:
+		m.opponentInputs++
+
+	case netplay.MessageSnapshot:
+		m.opponentSnapshot = msg.Snapshot
+
+	case netplay.MessageGarbage:
+		if m.state != onlinePlaying {
+			return m, nil
+		}
+		matrix := m.game.GetMatrix()
+		if msg.Garbage.Hole < 0 || msg.Garbage.Hole >= matrix.GetWidth() {
+			m.fail(fmt.Errorf("received garbage with invalid hole column %d", msg.Garbage.Hole))
+			return m, nil
+		}
+		m.game.AddGarbage(msg.Garbage.Lines, msg.Garbage.Hole)
+		return m, m.sendSnapshot()
+
+	case netplay.MessageGameOver, netplay.MessageOpponentLeft:
+		if m.state == onlinePlaying {
+			return m, m.finish(true)
+		}
+
+	case netplay.MessageError:
+		m.fail(fmt.Errorf("server error: %s", msg.Error))
+
+	case netplay.MessageHello:
+	}
+	return m, nil
+}
+
+// start begins the match using the seed from the server.
+func (m *OnlineModel) start(start *netplay.Start) tea.Cmd {
+	m.gameIn.Rand = replay.NewRand(start.Seed)
+	m.attack = versus.NewAttack()
+	game, err := single.NewGame(&m.gameIn, single.WithEventHandler(m.attack.HandleEvent))
+	if err != nil {
+		return tui.FatalErrorCmd(fmt.Errorf("creating game: %w", err))
+	}
+
+	m.game = game
+	m.state = onlinePlaying
+	m.opponent = start.Opponent
+	//nolint:gosec // This random source is not for any security-related tasks.
+	m.holeRand = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	m.fallStopwatch = components.NewStopwatchWithInterval(m.game.GetDefaultFallInterval())
+	m.startedAt = time.Now()
+
+	return tea.Batch(m.fallStopwatch.Init(), m.sendSnapshot())
+}
+
+func (m *OnlineModel) playingUpdate(msg tea.Msg) (*OnlineModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		return m.playingKeyMsgUpdate(msg)
+	case tui.KeyReleaseMsg:
+		if m.softDropHold != nil && key.Matches(msg, m.keys.SoftDrop) && m.softDropHold.Release() {
+			return m, m.setSoftDrop(false)
+		}
+	case components.KeyHoldTickMsg:
+		if m.softDropHold == nil {
+			break
+		}
+		released, cmd := m.softDropHold.Tick(msg)
+		if released {
+			return m, m.setSoftDrop(false)
+		}
+		return m, cmd
+	case stopwatch.TickMsg:
+		if msg.ID != m.fallStopwatch.ID() {
+			break
+		}
+		return m, m.fallStopwatchTick()
+	}
+	return m, nil
+}
+
+func (m *OnlineModel) playingKeyMsgUpdate(msg tea.KeyMsg) (*OnlineModel, tea.Cmd) {
+	var kind replay.InputKind
+	var err error
+
+	switch {
+	case key.Matches(msg, m.keys.Exit):
+		// There is no pausing an online game, so exiting forfeits.
+		m.game.EndGame()
+		kind = replay.InputEndGame
+	case key.Matches(msg, m.keys.Left):
+		m.game.MoveLeft()
+		kind = replay.InputMoveLeft
+	case key.Matches(msg, m.keys.Right):
+		m.game.MoveRight()
+		kind = replay.InputMoveRight
+	case key.Matches(msg, m.keys.Clockwise):
+		err = m.game.Rotate(true)
+		kind = replay.InputRotateClockwise
+	case key.Matches(msg, m.keys.CounterClockwise):
+		err = m.game.Rotate(false)
+		kind = replay.InputRotateCounter
+	case key.Matches(msg, m.keys.HardDrop):
+		_, err = m.game.HardDrop()
+		kind = replay.InputHardDrop
+	case key.Matches(msg, m.keys.SoftDrop):
+		if m.softDropHold == nil {
+			m.game.ToggleSoftDrop()
+			return m, tea.Batch(m.sendInput(replay.InputToggleSoftDrop), m.fallStopwatchTick())
+		}
+		started, cmd := m.softDropHold.Press(time.Now())
+		if !started {
+			return m, cmd
+		}
+		return m, tea.Batch(cmd, m.setSoftDrop(true))
+	case key.Matches(msg, m.keys.Hold):
+		_, err = m.game.Hold()
+		kind = replay.InputHold
+	default:
+		return m, nil
+	}
+	if err != nil {
+		return m, tui.FatalErrorCmd(fmt.Errorf("performing %s input: %w", kind, err))
+	}
+
+	cmds := []tea.Cmd{m.sendInput(kind), m.afterOperation()}
+	if kind == replay.InputHardDrop {
+		cmds = append(cmds, m.fallStopwatch.Reset())
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// setSoftDrop turns soft drop on or off, sending the change to the opponent as a toggle.
+func (m *OnlineModel) setSoftDrop(enabled bool) tea.Cmd {
+	if !m.game.SetSoftDrop(enabled) {
+		return nil
+	}
+	return tea.Batch(m.sendInput(replay.InputToggleSoftDrop), m.fallStopwatchTick())
+}
+
+func (m *OnlineModel) fallStopwatchTick() tea.Cmd {
+	elapsed := m.fallStopwatch.Elapsed() - m.fallElapsed
+	if elapsed < 0 {
+		// The stopwatch has been reset since the last tick.
+		elapsed = m.fallStopwatch.Elapsed()
+	}
+	m.fallElapsed = m.fallStopwatch.Elapsed()
+	m.game.UpdateLockDown(elapsed)
+
+	if _, err := m.game.TickLower(); err != nil {
+		return tui.FatalErrorCmd(fmt.Errorf("lowering tetrimino (tick): %w", err))
+	}
+	m.fallStopwatch.SetInterval(m.game.GetFallInterval())
+	return m.afterOperation()
+}
+
+// afterOperation sends any garbage from the last lock down and the new state of the board to the opponent.
+func (m *OnlineModel) afterOperation() tea.Cmd {
+	if lines := m.attack.Resolve(m.game); lines > 0 {
+		matrix := m.game.GetMatrix()
+		garbage := &netplay.Garbage{Lines: lines, Hole: m.holeRand.IntN(matrix.GetWidth())}
+		if cmd := m.send(netplay.Message{Type: netplay.MessageGarbage, Garbage: garbage}); cmd != nil {
+			return cmd
+		}
+	}
+	if cmd := m.sendSnapshot(); cmd != nil {
+		return cmd
+	}
+
+	if m.game.IsGameOver() {
+		gameOver := &netplay.GameOver{Reason: m.game.GetGameOverReason().String()}
+		if cmd := m.send(netplay.Message{Type: netplay.MessageGameOver, GameOver: gameOver}); cmd != nil {
+			return cmd
+		}
+		return m.finish(false)
+	}
+	return nil
+}
+
+func (m *OnlineModel) sendInput(kind replay.InputKind) tea.Cmd {
+	return m.send(netplay.Message{
+		Type:  netplay.MessageInput,
+		Input: &replay.Input{Time: time.Since(m.startedAt), Kind: kind},
+	})
+}
+
+func (m *OnlineModel) sendSnapshot() tea.Cmd {
+	matrix, err := m.game.GetVisibleMatrix()
+	if err != nil {
+		return tui.FatalErrorCmd(fmt.Errorf("getting visible matrix: %w", err))
+	}
+	return m.send(netplay.Message{
+		Type: netplay.MessageSnapshot,
+		Snapshot: &netplay.Snapshot{
+			Matrix:         matrix,
+			Score:          m.game.GetTotalScore(),
+			Lines:          m.game.GetLinesCleared(),
+			Level:          m.game.GetLevel(),
+			PendingGarbage: m.game.GetPendingGarbage(),
+			LinesSent:      m.attack.GetLinesSent(),
+		},
+	})
+}
+
+// send writes the Message to the server. If this fails the game cannot continue, so the model
+// moves to the failed state and the returned command stops the fall stopwatch.
+func (m *OnlineModel) send(msg netplay.Message) tea.Cmd {
+	if err := m.conn.Send(msg); err != nil {
+		m.fail(err)
+		if m.fallStopwatch != nil {
+			return m.fallStopwatch.Stop()
+		}
+	}
+	return nil
+}
+
+func (m *OnlineModel) finish(hasWon bool) tea.Cmd {
+	m.state = onlineFinished
+	m.hasWon = hasWon
+	if !m.game.IsGameOver() {
+		m.game.EndGame()
+	}
+	m.disconnect()
+	return m.fallStopwatch.Stop()
+}
+
+func (m *OnlineModel) fail(err error) {
+	m.state = onlineFailed
+	m.err = err
+	m.disconnect()
+}
+
+func (m *OnlineModel) disconnect() {
+	if m.conn == nil {
+		return
+	}
+	_ = m.conn.Close()
+	m.conn = nil
+}
+
+func (m *OnlineModel) View() string {
+	var output string
+	switch m.state {
+	case onlineConnecting:
+		output = m.messageView("Connecting", fmt.Sprintf("Connecting to %s...", m.addr))
+	case onlineLobby:
+		output = m.lobbyView()
+	case onlineFailed:
+		output = m.messageView("Error", m.err.Error())
+	case onlinePlaying, onlineFinished:
+		output = m.gameView()
+	}
+
+	output = lipgloss.JoinVertical(lipgloss.Left, output, m.help.View(m.keys))
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, output)
+}
+
+func (m *OnlineModel) messageView(title, body string) string {
+	header := lipgloss.NewStyle().Bold(true).Underline(true).Render(strings.ToUpper(title))
+	return m.styles.Winner.Render(lipgloss.JoinVertical(lipgloss.Center, header, "", body))
+}
+
+func (m *OnlineModel) lobbyView() string {
+	var body strings.Builder
+	fmt.Fprintf(&body, "Server: %s\n\nPlayers waiting:\n", m.addr)
+	for _, name := range m.lobby {
+		body.WriteString(name)
+		if name == m.username {
+			body.WriteString(" (you)")
+		}
+		body.WriteByte('\n')
+	}
+	body.WriteString("\nWaiting for an opponent to join...")
+	return m.messageView("Lobby", body.String())
+}
+
+func (m *OnlineModel) gameView() string {
+	matrixView, err := m.board.matrixView(m.game)
+	if err != nil {
+		return "** FAILED TO BUILD MATRIX VIEW **"
+	}
+	matrix := m.game.GetMatrix()
+	player := lipgloss.JoinHorizontal(lipgloss.Top,
+		lipgloss.JoinVertical(lipgloss.Right, m.board.holdView(m.game), m.playerInformationView()),
+		m.board.garbageMeterView(m.game.GetPendingGarbage(), matrix.GetSkyline()),
+		matrixView,
+		m.board.bagView(m.game),
+	)
+
+	opponentMatrix := matrix.GetVisible()
+	opponent := netplay.Snapshot{Matrix: make(tetris.Matrix, len(opponentMatrix))}
+	for row := range opponent.Matrix {
+		opponent.Matrix[row] = make([]byte, len(opponentMatrix[row]))
+	}
+	if m.opponentSnapshot != nil {
+		opponent = *m.opponentSnapshot
+	}
+	opponentView := lipgloss.JoinHorizontal(lipgloss.Top,
+		m.board.garbageMeterView(opponent.PendingGarbage, len(opponent.Matrix)),
+		m.board.renderMatrix(opponent.Matrix),
+		m.opponentInformationView(&opponent),
+	)
+
+	output := lipgloss.JoinHorizontal(lipgloss.Top, player, "  ", opponentView)
+	if m.state == onlineFinished {
+		message := onlineLoseMessage
+		if m.hasWon {
+			message = onlineWinMessage
+		}
+		output, err = charmutils.OverlayCenter(output, m.styles.Winner.Render(message), false)
+		if err != nil {
+			return "** FAILED TO OVERLAY WINNER MESSAGE **"
+		}
+	}
+	return output
+}
+
+func (m *OnlineModel) playerInformationView() string {
+	return m.informationView(m.username, []onlineStat{
+		{"Sent:", strconv.Itoa(m.attack.GetLinesSent())},
+		{"Combo:", strconv.Itoa(max(m.game.GetCombo(), 0))},
+		{"Lines:", strconv.Itoa(m.game.GetLinesCleared())},
+		{"Level:", strconv.Itoa(m.game.GetLevel())},
+	})
+}
+
+func (m *OnlineModel) opponentInformationView(s *netplay.Snapshot) string {
+	return m.informationView(m.opponent, []onlineStat{
+		{"Sent:", strconv.Itoa(s.LinesSent)},
+		{"Inputs:", strconv.Itoa(m.opponentInputs)},
+		{"Lines:", strconv.Itoa(s.Lines)},
+		{"Level:", strconv.Itoa(s.Level)},
+	})
+}
+
+// onlineStat is a row of the information panel beside a board.
+type onlineStat struct {
+	title string
+	value string
+}
+
+func (m *OnlineModel) informationView(name string, stats []onlineStat) string {
+	width := m.styles.Information.GetWidth()
+	headerStyle := lipgloss.NewStyle().Width(width).AlignHorizontal(lipgloss.Center).Bold(true).Underline(true)
+	header := headerStyle.Render(strings.ToUpper(name))
+
+	var output string
+	for _, stat := range stats {
+		output += fmt.Sprintf("%s%*s\n", stat.title, width-(1+len(stat.title)), stat.value)
+	}
+	return m.styles.Information.Render(lipgloss.JoinVertical(lipgloss.Left, header, output))
+}
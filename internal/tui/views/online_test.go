@@ -0,0 +1,205 @@
+package views
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/config"
+	"github.com/Broderick-Westrope/tetrigo/internal/netplay"
+	"github.com/Broderick-Westrope/tetrigo/internal/tui"
+)
+
+// startTestServer runs a netplay.Server on a loopback listener until the test ends, returning its address.
+func startTestServer(t *testing.T) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- netplay.NewServer().Serve(ctx, ln)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-done)
+	})
+	return ln.Addr().String()
+}
+
+func newTestOnlineModel(t *testing.T, addr, username string) *OnlineModel {
+	t.Helper()
+
+	m, err := NewOnlineModel(
+		tui.NewOnlineInput(addr, username, 1),
+		&config.Config{
+			NextQueueLength: 1,
+			GhostEnabled:    true,
+			LockDownMode:    "Extended",
+			Theme:           config.DefaultTheme(),
+			Keys:            config.DefaultKeys(),
+		},
+	)
+	require.NoError(t, err)
+	t.Cleanup(m.disconnect)
+	return m
+}
+
+// connect runs the connect command of the model and handles the result.
+func connect(t *testing.T, m *OnlineModel) {
+	t.Helper()
+
+	_, _ = m.Update(m.Init()())
+	require.Equal(t, onlineLobby, m.state)
+}
+
+// receiveNext waits for the next Message from the server and handles it.
+func receiveNext(t *testing.T, m *OnlineModel) {
+	t.Helper()
+
+	msgCh := make(chan tea.Msg, 1)
+	go func() {
+		msgCh <- receiveCmd(m.conn)()
+	}()
+
+	select {
+	case msg := <-msgCh:
+		_, _ = m.Update(msg)
+	case <-time.After(time.Second * 5):
+		t.Fatal("timed out waiting for message")
+	}
+}
+
+func TestOnline_Match(t *testing.T) {
+	addr := startTestServer(t)
+
+	alice := newTestOnlineModel(t, addr, "alice")
+	connect(t, alice)
+	receiveNext(t, alice)
+	assert.Equal(t, []string{"alice"}, alice.lobby)
+	assert.True(t, strings.Contains(alice.View(), "Waiting for an opponent"))
+
+	bob := newTestOnlineModel(t, addr, "bob")
+	connect(t, bob)
+
+	// Both players receive the start message with the same seed.
+	receiveNext(t, alice)
+	receiveNext(t, bob)
+	require.Equal(t, onlinePlaying, alice.state)
+	require.Equal(t, onlinePlaying, bob.state)
+	assert.Equal(t, "bob", alice.opponent)
+	assert.Equal(t, "alice", bob.opponent)
+	assert.Equal(t, alice.game.GetBagTetriminos(), bob.game.GetBagTetriminos())
+	assert.Equal(t, alice.game.GetTetriminoInPlay(), bob.game.GetTetriminoInPlay())
+
+	// Each player sends a snapshot when the match starts.
+	receiveNext(t, alice)
+	receiveNext(t, bob)
+	require.NotNil(t, alice.opponentSnapshot)
+	require.NotNil(t, bob.opponentSnapshot)
+
+	// Bob receives Alice's input and the snapshot of her new board.
+	_, _ = alice.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	receiveNext(t, bob)
+	assert.Equal(t, 1, bob.opponentInputs)
+	receiveNext(t, bob)
+	aliceMatrix, err := alice.game.GetVisibleMatrix()
+	require.NoError(t, err)
+	assert.Equal(t, aliceMatrix, bob.opponentSnapshot.Matrix)
+
+	// Alice forfeits by exiting, so Bob wins.
+	_, _ = alice.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Equal(t, onlineFinished, alice.state)
+	assert.False(t, alice.hasWon)
+	assert.True(t, strings.Contains(alice.View(), "YOU LOSE!"))
+
+	for bob.state == onlinePlaying {
+		receiveNext(t, bob)
+	}
+	assert.Equal(t, onlineFinished, bob.state)
+	assert.True(t, bob.hasWon)
+	assert.True(t, strings.Contains(bob.View(), "YOU WIN!"))
+
+	_, cmd := bob.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	require.NotNil(t, cmd)
+	assert.Equal(t, tui.SwitchModeMsg{Target: tui.ModeMenu, Input: tui.NewMenuInput()}, cmd())
+}
+
+func TestOnline_ConnectionFailed(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	require.NoError(t, ln.Close())
+
+	m := newTestOnlineModel(t, addr, "alice")
+	_, _ = m.Update(m.Init()())
+	assert.Equal(t, onlineFailed, m.state)
+	assert.True(t, strings.Contains(m.View(), "ERROR"))
+}
+
+// startRawServer runs a server on a loopback listener which accepts one client, reads its hello, then writes the
+// given raw lines to it. It returns the server's address.
+func startRawServer(t *testing.T, lines ...string) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		c, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		t.Cleanup(func() { _ = c.Close() })
+
+		if _, err = bufio.NewReader(c).ReadString('\n'); err != nil {
+			return
+		}
+		for _, line := range lines {
+			if _, err = c.Write([]byte(line + "\n")); err != nil {
+				return
+			}
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestOnline_InvalidMessage(t *testing.T) {
+	start := `{"type":"start","start":{"seed":[1,2],"opponent":"bob"}}`
+
+	tt := map[string]struct {
+		lines []string
+	}{
+		"lobby without payload":     {lines: []string{`{"type":"lobby"}`}},
+		"start without payload":     {lines: []string{`{"type":"start"}`}},
+		"input without payload":     {lines: []string{start, `{"type":"input"}`}},
+		"snapshot without payload":  {lines: []string{start, `{"type":"snapshot"}`}},
+		"garbage without payload":   {lines: []string{start, `{"type":"garbage"}`}},
+		"game over without payload": {lines: []string{start, `{"type":"game_over"}`}},
+		"unknown type":              {lines: []string{start, `{"type":"unknown"}`}},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			addr := startRawServer(t, tc.lines...)
+			m := newTestOnlineModel(t, addr, "alice")
+			connect(t, m)
+
+			for range tc.lines {
+				require.NotPanics(t, func() { receiveNext(t, m) })
+			}
+			assert.Equal(t, onlineFailed, m.state)
+			assert.True(t, strings.Contains(m.View(), "received invalid message"))
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package views
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Broderick-Westrope/charmutils"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/stopwatch"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/config"
+	"github.com/Broderick-Westrope/tetrigo/internal/replay"
+	"github.com/Broderick-Westrope/tetrigo/internal/tui"
+	"github.com/Broderick-Westrope/tetrigo/internal/tui/components"
+)
+
+const replaySeekStep = time.Second * 5
+
+// replaySpeeds are the playback speed multipliers that can be selected, from slowest to fastest.
+var replaySpeeds = []float64{0.25, 0.5, 1, 2, 4, 8}
+
+var _ tea.Model = &ReplayModel{}
+
+// ReplayModel plays back a recorded single player game.
+type ReplayModel struct {
+	player     *replay.Player
+	board      *boardRenderer
+	styles     *components.GameStyles
+	keys       *replayKeyMap
+	help       help.Model
+	stopwatch  components.Stopwatch
+	speedIndex int
+	isPaused   bool
+
+	width  int
+	height int
+}
+
+func NewReplayModel(in *tui.ReplayInput, cfg *config.Config) (*ReplayModel, error) {
+	player, err := replay.NewPlayer(in.Replay)
+	if err != nil {
+		return nil, fmt.Errorf("creating replay player: %w", err)
+	}
+
+	styles := components.CreateGameStyles(cfg.Theme)
+	return &ReplayModel{
+		player:     player,
+		board:      newBoardRenderer(styles, in.Replay.Config.NextQueueLength),
+		styles:     styles,
+		keys:       defaultReplayKeyMap(),
+		help:       help.New(),
+		stopwatch:  components.NewStopwatchWithInterval(timerUpdateInterval),
+		speedIndex: 2,
+	}, nil
+}
+
+func (m *ReplayModel) Init() tea.Cmd {
+	return m.stopwatch.Init()
+}
+
+func (m *ReplayModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	cmd, err := charmutils.UpdateTypedModel(&m.stopwatch, msg)
+	if err != nil {
+		return m, tui.FatalErrorCmd(err)
+	}
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		return m, tea.Batch(cmd, m.keyMsgUpdate(msg))
+
+	case stopwatch.TickMsg:
+		if msg.ID != m.stopwatch.ID() {
+			break
+		}
+		playback := time.Duration(float64(timerUpdateInterval) * replaySpeeds[m.speedIndex])
+		if err = m.player.Advance(playback); err != nil {
+			return m, tui.FatalErrorCmd(fmt.Errorf("advancing replay: %w", err))
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	return m, cmd
+}
+
+func (m *ReplayModel) keyMsgUpdate(msg tea.KeyMsg) tea.Cmd {
+	var err error
+	switch {
+	case key.Matches(msg, m.keys.Exit):
+		return tui.SwitchModeCmd(tui.ModeMenu, tui.NewMenuInput())
+	case key.Matches(msg, m.keys.Help):
+		m.help.ShowAll = !m.help.ShowAll
+	case key.Matches(msg, m.keys.PlayPause):
+		m.isPaused = !m.isPaused
+		return m.stopwatch.Toggle()
+	case key.Matches(msg, m.keys.SeekBack):
+		err = m.player.Seek(m.player.Elapsed() - replaySeekStep)
+	case key.Matches(msg, m.keys.SeekForward):
+		err = m.player.Seek(m.player.Elapsed() + replaySeekStep)
+	case key.Matches(msg, m.keys.SpeedUp):
+		m.speedIndex = min(m.speedIndex+1, len(replaySpeeds)-1)
+	case key.Matches(msg, m.keys.SpeedDown):
+		m.speedIndex = max(m.speedIndex-1, 0)
+	}
+
+	if err != nil {
+		return tui.FatalErrorCmd(fmt.Errorf("seeking replay: %w", err))
+	}
+	return nil
+}
+
+func (m *ReplayModel) View() string {
+	output, err := m.board.view(m.player.Game(), m.informationView())
+	if err != nil {
+		return "** FAILED TO BUILD MATRIX VIEW **"
+	}
+
+	output = lipgloss.JoinVertical(lipgloss.Left, output, m.help.View(m.keys))
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, output)
+}
+
+func (m *ReplayModel) informationView() string {
+	width := m.styles.Information.GetWidth()
+	headerStyle := lipgloss.NewStyle().Width(width).AlignHorizontal(lipgloss.Center).Bold(true).Underline(true)
+
+	var header string
+	switch {
+	case m.player.IsFinished():
+		header = headerStyle.Render("FINISHED")
+	case m.isPaused:
+		header = headerStyle.Render("PAUSED")
+	default:
+		header = headerStyle.Render("REPLAY")
+	}
+
+	toFixedWidth := func(title, value string) string {
+		return fmt.Sprintf("%s%*s\n", title, width-(1+len(title)), value)
+	}
+
+	r := m.player.Replay()
+	game := m.player.Game()
+
+	var output string
+	output += fmt.Sprintln(strings.ToUpper(r.Mode))
+	output += fmt.Sprintf("%*s\n", width-1, r.Username)
+	output += fmt.Sprintln("Score:")
+	output += fmt.Sprintf("%*d\n", width-1, game.GetTotalScore())
+	output += fmt.Sprintln("Time:")
+	output += fmt.Sprintf("%*s\n", width-1, formatGameTime(m.player.Elapsed()))
+	output += toFixedWidth("Lines:", strconv.Itoa(game.GetLinesCleared()))
+	output += toFixedWidth("Level:", strconv.Itoa(game.GetLevel()))
+	output += toFixedWidth("Speed:", strconv.FormatFloat(replaySpeeds[m.speedIndex], 'g', -1, 64)+"x")
+
+	return m.styles.Information.Render(lipgloss.JoinVertical(lipgloss.Left, header, output))
+}
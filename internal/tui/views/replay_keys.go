@@ -0,0 +1,52 @@
+package views
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+)
+
+type replayKeyMap struct {
+	Exit        key.Binding
+	Help        key.Binding
+	PlayPause   key.Binding
+	SeekBack    key.Binding
+	SeekForward key.Binding
+	SpeedUp     key.Binding
+	SpeedDown   key.Binding
+}
+
+func defaultReplayKeyMap() *replayKeyMap {
+	return &replayKeyMap{
+		Exit:        key.NewBinding(key.WithKeys("esc"), key.WithHelp("escape", "exit")),
+		Help:        key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
+		PlayPause:   key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "play/pause")),
+		SeekBack:    key.NewBinding(key.WithKeys("left"), key.WithHelp("left arrow", "seek back")),
+		SeekForward: key.NewBinding(key.WithKeys("right"), key.WithHelp("right arrow", "seek forward")),
+		SpeedUp:     key.NewBinding(key.WithKeys("up"), key.WithHelp("up arrow", "speed up")),
+		SpeedDown:   key.NewBinding(key.WithKeys("down"), key.WithHelp("down arrow", "slow down")),
+	}
+}
+
+func (k *replayKeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{
+		k.Exit,
+		k.Help,
+	}
+}
+
+func (k *replayKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{
+			k.Exit,
+			k.Help,
+			k.PlayPause,
+		},
+		{
+			k.SeekBack,
+			k.SeekForward,
+		},
+		{
+			k.SpeedUp,
+			k.SpeedDown,
+		},
+	}
+}
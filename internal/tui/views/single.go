@@ -1,6 +1,7 @@
 package views
 
 import (
+	"cmp"
 	"fmt"
 	"math/rand/v2"
 	"strconv"
@@ -20,8 +21,11 @@ import (
 
 	"github.com/Broderick-Westrope/tetrigo/internal/config"
 	"github.com/Broderick-Westrope/tetrigo/internal/data"
+	"github.com/Broderick-Westrope/tetrigo/internal/replay"
+	"github.com/Broderick-Westrope/tetrigo/internal/savegame"
 	"github.com/Broderick-Westrope/tetrigo/internal/tui"
 	"github.com/Broderick-Westrope/tetrigo/pkg/tetris"
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris/bot"
 	"github.com/Broderick-Westrope/tetrigo/pkg/tetris/modes/single"
 )
 
@@ -44,25 +48,75 @@ Press PAUSE to continue or HOLD to exit.
             Press EXIT or HOLD to continue.           
 `
 	timerUpdateInterval = time.Millisecond * 13
+	calloutDuration     = time.Second * 2
+	// risingGarbageInterval is how often a garbage line rises during a Dig with rising garbage.
+	risingGarbageInterval = time.Second * 5
 )
 
 var _ tea.Model = &SingleModel{}
 
 type SingleModel struct {
-	username        string
-	game            *single.Game
-	nextQueueLength int
-	fallStopwatch   components.Stopwatch
-	mode            tui.Mode
+	username      string
+	game          *single.Game
+	board         *boardRenderer
+	fallStopwatch components.Stopwatch
+	fallElapsed   time.Duration
+	mode          tui.Mode
+	// gameMode is the name the score is saved under on the leaderboard (see tui.SingleInput.GameModeName).
+	gameMode string
 
 	gameTimer     components.Timer
 	gameStopwatch components.Stopwatch
-
-	styles   *components.GameStyles
-	help     help.Model
-	keys     *components.GameKeyMap
-	isPaused bool
-	rand     *rand.Rand
+	// resumedElapsed is the time played before the game was resumed. It is added to the elapsed time of the
+	// gameStopwatch, whereas the gameTimer is resumed with the time which remained.
+	resumedElapsed time.Duration
+
+	styles    *components.GameStyles
+	help      help.Model
+	keys      *components.GameKeyMap
+	autoShift *components.AutoShift
+	// softDropHold tracks the soft drop key when soft drop lasts whilst it is held. It is nil when the key toggles.
+	softDropHold *components.KeyHold
+	isPaused     bool
+	rand         *rand.Rand
+	source       *rand.PCG
+
+	// perfectClearUntil is the game time until which the Perfect Clear callout is displayed.
+	perfectClearUntil time.Duration
+	// finalTime is the time played when the game ended.
+	finalTime time.Duration
+	// startLevel is the level the game started at, or 0 if it is unknown.
+	startLevel int
+	// completed is whether the game ended by reaching the goal of its mode, rather than topping out or being exited.
+	completed bool
+
+	// lineGoal is the number of rows to clear in a Sprint, or 0 for other modes.
+	lineGoal int
+	// timeLimit is the length of an Ultra, or 0 for other modes.
+	timeLimit time.Duration
+	// garbageLines is the number of garbage lines a Dig starts with, or 0 for other modes.
+	garbageLines int
+	// risingGarbage is whether a garbage line rises every risingGarbageInterval.
+	risingGarbage bool
+	// garbageRisen is the number of garbage lines which have risen so far.
+	garbageRisen int
+	// splits are the times at which each of the splitLines of the Sprint were reached so far.
+	splits []time.Duration
+	// bestSplits are the splits of the personal best of the player, which the splits are compared against.
+	bestSplits []time.Duration
+
+	seed      *[2]uint64
+	replayDir string
+	recorder  *replay.Recorder
+	// finalReplay is the completed recording of the game, set on game over if the game was recorded.
+	finalReplay *replay.Replay
+	savePath    string
+	resume      *savegame.Save
+
+	bot           bot.Bot
+	botMoves      []bot.Move
+	botPiece      int
+	isBotPlanning bool
 
 	width  int
 	height int
@@ -73,23 +127,51 @@ func NewSingleModel(
 	cfg *config.Config,
 	opts ...func(*SingleModel),
 ) (*SingleModel, error) {
+	size := cfg.GetMatrixSize(in.Mode.String())
+	tui.WithDefaultMatrixSize(size.Width, size.Height)(in)
+
 	// Setup initial model
+	styles := components.CreateGameStyles(cfg.Theme)
+	seed := [2]uint64{rand.Uint64(), rand.Uint64()}
 	m := &SingleModel{
-		username:        in.Username,
-		styles:          components.CreateGameStyles(cfg.Theme),
-		help:            help.New(),
-		keys:            components.ConstructGameKeyMap(cfg.Keys),
-		isPaused:        false,
-		nextQueueLength: cfg.NextQueueLength,
-		mode:            in.Mode,
-		//nolint:gosec // This random source is not for any security-related tasks.
-		rand: rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
+		username:   in.Username,
+		startLevel: in.Level,
+		board:      newBoardRenderer(styles, cfg.NextQueueLength),
+		styles:     styles,
+		help:       help.New(),
+		keys:       components.ConstructGameKeyMap(cfg.Keys),
+		autoShift: components.NewAutoShift(
+			time.Duration(cfg.DAS)*time.Millisecond,
+			time.Duration(cfg.ARR)*time.Millisecond,
+		),
+		isPaused: false,
+		mode:     in.Mode,
+		gameMode: in.GameModeName(),
+		source:   replay.NewSource(seed),
+		seed:     &seed,
+	}
+
+	if in.Bot {
+		m.bot = bot.NewSearch()
+	}
+	if cfg.SoftDropMode == config.SoftDropModeHold {
+		m.softDropHold = components.NewKeyHold()
+		m.keys.UseHeldSoftDrop()
 	}
 
 	for _, opt := range opts {
 		opt(m)
 	}
 
+	lockDownMode, err := tetris.ParseLockDownMode(cfg.LockDownMode)
+	if err != nil {
+		return nil, fmt.Errorf("parsing lock down mode: %w", err)
+	}
+	randomizer, err := tetris.NewRandomizer(cfg.Randomizer)
+	if err != nil {
+		return nil, fmt.Errorf("creating randomizer: %w", err)
+	}
+
 	// Get game input
 	var gameIn *single.Input
 	switch in.Mode {
@@ -105,35 +187,76 @@ func NewSingleModel(
 		m.gameStopwatch = components.NewStopwatchWithInterval(timerUpdateInterval)
 
 	case tui.ModeSprint:
+		if in.LineGoal < 0 {
+			return nil, fmt.Errorf("invalid line goal %d", in.LineGoal)
+		}
+		m.lineGoal = cmp.Or(in.LineGoal, tui.DefaultLineGoal)
+		gameIn = &single.Input{
+			Level:         in.Level,
+			MaxLevel:      cfg.MaxLevel,
+			IncreaseLevel: true,
+			LineGoal:      m.lineGoal,
+			GhostEnabled:  cfg.GhostEnabled,
+		}
+		m.gameStopwatch = components.NewStopwatchWithInterval(timerUpdateInterval)
+
+	case tui.ModeUltra:
+		if in.TimeLimit < 0 || in.TimeLimit%time.Minute != 0 {
+			return nil, fmt.Errorf("invalid time limit %v", in.TimeLimit)
+		}
+		m.timeLimit = cmp.Or(in.TimeLimit, tui.DefaultTimeLimit)
+		gameIn = &single.Input{
+			Level:        in.Level,
+			GhostEnabled: cfg.GhostEnabled,
+		}
+		m.gameTimer = components.NewTimerWithInterval(m.timeLimit, timerUpdateInterval)
+
+	case tui.ModeDig:
+		if in.GarbageLines < 0 {
+			return nil, fmt.Errorf("invalid garbage lines %d", in.GarbageLines)
+		}
+		m.garbageLines = cmp.Or(in.GarbageLines, tui.DefaultGarbageLines)
+		m.risingGarbage = in.RisingGarbage
 		gameIn = &single.Input{
 			Level:         in.Level,
 			MaxLevel:      cfg.MaxLevel,
 			IncreaseLevel: true,
 
-			MaxLines:      40,
-			EndOnMaxLines: true,
+			GarbageLines:        m.garbageLines,
+			EndOnGarbageCleared: true,
 
 			GhostEnabled: cfg.GhostEnabled,
 		}
 		m.gameStopwatch = components.NewStopwatchWithInterval(timerUpdateInterval)
 
-	case tui.ModeUltra:
+	case tui.ModeMaster:
 		gameIn = &single.Input{
-			Level:        in.Level,
+			Master:         true,
+			MasterSections: cfg.GetMasterSections(),
+
 			GhostEnabled: cfg.GhostEnabled,
 		}
-		m.gameTimer = components.NewTimerWithInterval(time.Minute*2, timerUpdateInterval)
+		m.gameStopwatch = components.NewStopwatchWithInterval(timerUpdateInterval)
 
-	case tui.ModeMenu, tui.ModeLeaderboard:
+	case tui.ModeMenu, tui.ModeLeaderboard, tui.ModeReplay, tui.ModeVersus, tui.ModeOnline, tui.ModeResume:
 		fallthrough
 	default:
 		return nil, fmt.Errorf("invalid single player game mode: %v", in.Mode)
 	}
 	gameIn.Rand = m.rand
+	gameIn.Source = m.source
+	gameIn.LockDownMode = lockDownMode
+	gameIn.Randomizer = randomizer
+	gameIn.SoftDropFactor = cfg.SoftDropFactor
+	gameIn.Width = in.Width
+	gameIn.Height = in.Height
 
 	// Create game
-	var err error
-	m.game, err = single.NewGame(gameIn)
+	if m.resume != nil {
+		err = m.restoreGame()
+	} else {
+		m.game, err = single.NewGame(gameIn, single.WithEventHandler(m.handleGameEvent))
+	}
 	if err != nil {
 		return nil, fmt.Errorf("creating single player game: %w", err)
 	}
@@ -141,12 +264,89 @@ func NewSingleModel(
 	// Setup game dependents
 	m.fallStopwatch = components.NewStopwatchWithInterval(m.game.GetDefaultFallInterval())
 
+	if m.seed != nil && m.resume == nil {
+		cfg := replay.NewConfig(gameIn, m.board.nextQueueLength)
+		cfg.TimeLimit = m.timeLimit
+		m.recorder = replay.NewRecorder(*m.seed, m.gameMode, m.username, cfg)
+	}
+
 	return m, nil
 }
 
+// restoreGame restores the game and the time played from the saved game being resumed.
+func (m *SingleModel) restoreGame() error {
+	if m.resume.Mode != m.mode.String() {
+		return fmt.Errorf("saved game is for mode %q, not %q", m.resume.Mode, m.mode.String())
+	}
+
+	var err error
+	m.game, err = single.RestoreGame(m.resume.Game, single.WithEventHandler(m.handleGameEvent))
+	if err != nil {
+		return fmt.Errorf("restoring saved game: %w", err)
+	}
+	m.startLevel = m.resume.StartLevel
+	m.splits = m.resume.Splits
+
+	if m.gameTimer != nil {
+		m.gameTimer.SetTimeout(max(m.timeLimit-m.resume.Elapsed, 0))
+	} else {
+		m.resumedElapsed = m.resume.Elapsed
+	}
+	if m.risingGarbage {
+		m.garbageRisen = int(m.resume.Elapsed / risingGarbageInterval)
+	}
+	if m.resume.Replay != nil {
+		m.recorder = replay.ResumeRecorder(m.resume.Replay)
+	}
+	return nil
+}
+
+// WithRandSource sets the random source used for Tetrimino generation.
+// Since the seed of the source is unknown, games using this option are not recorded.
 func WithRandSource(r *rand.Rand) func(*SingleModel) {
 	return func(m *SingleModel) {
 		m.rand = r
+		m.source = nil
+		m.seed = nil
+	}
+}
+
+// WithSeed sets the seed of the random source used for Tetrimino generation.
+func WithSeed(seed [2]uint64) func(*SingleModel) {
+	return func(m *SingleModel) {
+		m.rand = nil
+		m.source = replay.NewSource(seed)
+		m.seed = &seed
+	}
+}
+
+// WithSavePath enables saving the game to the given file when exiting from the pause menu.
+// Games played by a bot are not saved.
+func WithSavePath(path string) func(*SingleModel) {
+	return func(m *SingleModel) {
+		m.savePath = path
+	}
+}
+
+// WithResume resumes the given saved game instead of starting a new one.
+// The game mode of the save must match the mode of the tui.SingleInput.
+func WithResume(s *savegame.Save) func(*SingleModel) {
+	return func(m *SingleModel) {
+		m.resume = s
+	}
+}
+
+// WithBestSplits sets the splits of the personal best of the player, which the splits of a Sprint are compared against.
+func WithBestSplits(splits []time.Duration) func(*SingleModel) {
+	return func(m *SingleModel) {
+		m.bestSplits = splits
+	}
+}
+
+// WithReplayDir enables saving the replay of the game to the given directory on game over.
+func WithReplayDir(dir string) func(*SingleModel) {
+	return func(m *SingleModel) {
+		m.replayDir = dir
 	}
 }
 
@@ -158,7 +358,11 @@ func (m *SingleModel) Init() tea.Cmd {
 		cmd = m.gameStopwatch.Init()
 	}
 
-	return tea.Batch(m.fallStopwatch.Init(), cmd)
+	cmds := []tea.Cmd{m.fallStopwatch.Init(), cmd}
+	if m.bot != nil {
+		cmds = append(cmds, botTickCmd())
+	}
+	return tea.Batch(cmds...)
 }
 
 func (m *SingleModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -184,6 +388,23 @@ func (m *SingleModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		return m, tea.Batch(cmds...)
+
+	case tui.KeyboardEnhancementsMsg:
+		m.autoShift.SetReleaseEvents(msg.SupportsKeyReleases())
+		if m.softDropHold != nil {
+			m.softDropHold.SetReleaseEvents(msg.SupportsKeyReleases())
+		}
+		return m, tea.Batch(cmds...)
+
+	case botTickMsg:
+		m, cmd = m.botTickUpdate()
+		cmds = append(cmds, cmd)
+		return m, tea.Batch(cmds...)
+
+	case botPlanMsg:
+		m, cmd = m.botPlanUpdate(msg)
+		cmds = append(cmds, cmd)
+		return m, tea.Batch(cmds...)
 	}
 
 	// Game Over
@@ -236,18 +457,33 @@ func (m *SingleModel) dependenciesUpdate(msg tea.Msg) (*SingleModel, tea.Cmd) {
 func (m *SingleModel) gameOverUpdate(msg tea.Msg) (*SingleModel, tea.Cmd) {
 	if msg, ok := msg.(tea.KeyMsg); ok {
 		if key.Matches(msg, m.keys.Exit, m.keys.Hold) {
-			modeStr := m.mode.String()
+			stats := m.game.GetStats()
 			newEntry := &data.Score{
-				GameMode: modeStr,
+				GameMode: m.gameMode,
 				Name:     m.username,
+				Time:     m.finalTime,
 				Score:    m.game.GetTotalScore(),
 				Lines:    m.game.GetLinesCleared(),
 				Level:    m.game.GetLevel(),
+
+				Completed:  m.completed,
+				StartLevel: m.startLevel,
+				Pieces:     stats.Pieces,
+				PPS:        piecesPerSecond(stats.Pieces, m.finalTime),
+				MaxCombo:   m.game.GetMaxCombo(),
+				TSpins:     stats.TSpins,
+				MiniTSpins: stats.MiniTSpins,
+				Tetrises:   stats.Tetrises,
+				Splits:     m.splits,
+				Replay:     m.finalReplay,
 			}
 
-			return m, tui.SwitchModeCmd(tui.ModeLeaderboard,
-				tui.NewLeaderboardInput(modeStr, tui.WithNewEntry(newEntry)),
-			)
+			// Games played by a bot are not entered on the leaderboard.
+			var opts []func(*tui.LeaderboardInput)
+			if m.bot == nil {
+				opts = append(opts, tui.WithNewEntry(newEntry))
+			}
+			return m, tui.SwitchModeCmd(tui.ModeLeaderboard, tui.NewLeaderboardInput(m.gameMode, opts...))
 		}
 	}
 
@@ -260,7 +496,15 @@ func (m *SingleModel) pausedUpdate(msg tea.Msg) (*SingleModel, tea.Cmd) {
 		case key.Matches(msg, m.keys.Exit):
 			return m, m.togglePause()
 		case key.Matches(msg, m.keys.Hold):
-			return m, tui.SwitchModeCmd(tui.ModeMenu, tui.NewMenuInput())
+			switchCmd := tui.SwitchModeCmd(tui.ModeMenu, tui.NewMenuInput())
+			if m.savePath == "" || m.bot != nil {
+				return m, switchCmd
+			}
+			save, err := m.save()
+			if err != nil {
+				return nil, tui.FatalErrorCmd(fmt.Errorf("saving game: %w", err))
+			}
+			return m, tea.Sequence(writeSaveCmd(m.savePath, save), switchCmd)
 		}
 	}
 
@@ -272,6 +516,34 @@ func (m *SingleModel) playingUpdate(msg tea.Msg) (*SingleModel, tea.Cmd) {
 	case tea.KeyMsg:
 		return m.playingKeyMsgUpdate(msg)
 
+	case tui.KeyReleaseMsg:
+		switch {
+		case key.Matches(msg, m.keys.Left):
+			m.autoShift.Release(components.ShiftLeft)
+		case key.Matches(msg, m.keys.Right):
+			m.autoShift.Release(components.ShiftRight)
+		case key.Matches(msg, m.keys.SoftDrop):
+			if m.softDropHold != nil && m.softDropHold.Release() {
+				return m, m.setSoftDrop(false)
+			}
+		}
+		return m, nil
+
+	case components.KeyHoldTickMsg:
+		if m.softDropHold == nil {
+			break
+		}
+		released, cmd := m.softDropHold.Tick(msg)
+		if released {
+			return m, m.setSoftDrop(false)
+		}
+		return m, cmd
+
+	case components.AutoShiftTickMsg:
+		shifts, cmd := m.autoShift.Tick(msg)
+		m.shift(m.autoShift.Direction(), shifts)
+		return m, cmd
+
 	case stopwatch.TickMsg:
 		if msg.ID != m.fallStopwatch.ID() {
 			break
@@ -289,16 +561,27 @@ func (m *SingleModel) playingUpdate(msg tea.Msg) (*SingleModel, tea.Cmd) {
 }
 
 func (m *SingleModel) playingKeyMsgUpdate(msg tea.KeyMsg) (*SingleModel, tea.Cmd) {
+	if m.bot != nil {
+		// The bot is playing so only allow pausing.
+		if key.Matches(msg, m.keys.Exit) {
+			return m, m.togglePause()
+		}
+		return m, nil
+	}
+
 	switch {
 	case key.Matches(msg, m.keys.Left):
-		m.game.MoveLeft()
-		return m, nil
+		shifts, cmd := m.autoShift.Press(components.ShiftLeft, time.Now())
+		m.shift(components.ShiftLeft, shifts)
+		return m, cmd
 
 	case key.Matches(msg, m.keys.Right):
-		m.game.MoveRight()
-		return m, nil
+		shifts, cmd := m.autoShift.Press(components.ShiftRight, time.Now())
+		m.shift(components.ShiftRight, shifts)
+		return m, cmd
 
 	case key.Matches(msg, m.keys.Clockwise):
+		m.record(replay.InputRotateClockwise)
 		err := m.game.Rotate(true)
 		if err != nil {
 			return nil, tui.FatalErrorCmd(fmt.Errorf("rotating clockwise: %w", err))
@@ -306,6 +589,7 @@ func (m *SingleModel) playingKeyMsgUpdate(msg tea.KeyMsg) (*SingleModel, tea.Cmd
 		return m, nil
 
 	case key.Matches(msg, m.keys.CounterClockwise):
+		m.record(replay.InputRotateCounter)
 		err := m.game.Rotate(false)
 		if err != nil {
 			return nil, tui.FatalErrorCmd(fmt.Errorf("rotating counter-clockwise: %w", err))
@@ -313,6 +597,7 @@ func (m *SingleModel) playingKeyMsgUpdate(msg tea.KeyMsg) (*SingleModel, tea.Cmd
 		return m, nil
 
 	case key.Matches(msg, m.keys.HardDrop):
+		m.record(replay.InputHardDrop)
 		gameOver, err := m.game.HardDrop()
 		if err != nil {
 			return nil, tui.FatalErrorCmd(fmt.Errorf("hard dropping: %w", err))
@@ -325,10 +610,19 @@ func (m *SingleModel) playingKeyMsgUpdate(msg tea.KeyMsg) (*SingleModel, tea.Cmd
 		return m, tea.Batch(cmds...)
 
 	case key.Matches(msg, m.keys.SoftDrop):
-		m.game.ToggleSoftDrop()
-		return m, m.fallStopwatchTick()
+		if m.softDropHold == nil {
+			m.record(replay.InputToggleSoftDrop)
+			m.game.ToggleSoftDrop()
+			return m, m.fallStopwatchTick()
+		}
+		started, cmd := m.softDropHold.Press(time.Now())
+		if !started {
+			return m, cmd
+		}
+		return m, tea.Batch(cmd, m.setSoftDrop(true))
 
 	case key.Matches(msg, m.keys.Hold):
+		m.record(replay.InputHold)
 		gameOver, err := m.game.Hold()
 		if err != nil {
 			return nil, tui.FatalErrorCmd(fmt.Errorf("holding tetrimino: %w", err))
@@ -345,7 +639,54 @@ func (m *SingleModel) playingKeyMsgUpdate(msg tea.KeyMsg) (*SingleModel, tea.Cmd
 	return m, nil
 }
 
+// shift moves the Tetrimino in play up to n cells in the given direction, stopping once it is blocked.
+// Only the moves which succeed are recorded.
+func (m *SingleModel) shift(dir components.ShiftDirection, n int) {
+	for range n {
+		var moved bool
+		switch dir {
+		case components.ShiftLeft:
+			moved = m.game.MoveLeft()
+			if moved {
+				m.record(replay.InputMoveLeft)
+			}
+		case components.ShiftRight:
+			moved = m.game.MoveRight()
+			if moved {
+				m.record(replay.InputMoveRight)
+			}
+		case components.ShiftNone:
+		}
+		if !moved {
+			return
+		}
+	}
+}
+
+// setSoftDrop turns soft drop on or off, recording the change as a toggle so replays are unaffected by the
+// soft drop mode.
+func (m *SingleModel) setSoftDrop(enabled bool) tea.Cmd {
+	if !m.game.SetSoftDrop(enabled) {
+		return nil
+	}
+	m.record(replay.InputToggleSoftDrop)
+	return m.fallStopwatchTick()
+}
+
 func (m *SingleModel) fallStopwatchTick() tea.Cmd {
+	m.riseGarbage()
+
+	elapsed := m.fallStopwatch.Elapsed() - m.fallElapsed
+	if elapsed < 0 {
+		// The stopwatch has been reset since the last tick.
+		elapsed = m.fallStopwatch.Elapsed()
+	}
+	m.fallElapsed = m.fallStopwatch.Elapsed()
+	if m.recorder != nil {
+		m.recorder.RecordGravity(m.gameElapsed(), elapsed)
+	}
+	m.game.UpdateLockDown(elapsed)
+
 	gameOver, err := m.game.TickLower()
 	if err != nil {
 		return tui.FatalErrorCmd(fmt.Errorf("lowering tetrimino (tick): %w", err))
@@ -357,23 +698,32 @@ func (m *SingleModel) fallStopwatchTick() tea.Cmd {
 	return nil
 }
 
+// riseGarbage queues a garbage line for each risingGarbageInterval played since the last line rose.
+// Like garbage in versus, the lines are inserted when the next Tetrimino locks down without clearing any lines.
+func (m *SingleModel) riseGarbage() {
+	if !m.risingGarbage {
+		return
+	}
+	for ; m.garbageRisen < int(m.gameElapsed()/risingGarbageInterval); m.garbageRisen++ {
+		m.record(replay.InputGarbage)
+		m.game.AddRandomGarbage(1)
+	}
+}
+
 func (m *SingleModel) View() string {
-	matrixView, err := m.matrixView()
+	output, err := m.board.view(m.game, m.informationView())
 	if err != nil {
 		return "** FAILED TO BUILD MATRIX VIEW **"
 	}
 
-	var output = lipgloss.JoinHorizontal(lipgloss.Top,
-		lipgloss.JoinVertical(lipgloss.Right, m.holdView(), m.informationView()),
-		matrixView,
-		m.bagView(),
-	)
-
 	if m.game.IsGameOver() {
 		output, err = charmutils.OverlayCenter(output, gameOverMessage, false)
 		if err != nil {
 			return "** FAILED TO OVERLAY GAME OVER MESSAGE **"
 		}
+		if len(m.splits) > 0 {
+			output = lipgloss.JoinVertical(lipgloss.Center, output, m.splitsView())
+		}
 	} else if m.isPaused {
 		output, err = charmutils.OverlayCenter(output,
 			lipgloss.NewStyle().Margin(0, 1).
@@ -388,32 +738,6 @@ func (m *SingleModel) View() string {
 	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, output)
 }
 
-func (m *SingleModel) matrixView() (string, error) {
-	matrix, err := m.game.GetVisibleMatrix()
-	if err != nil {
-		return "", fmt.Errorf("getting visible matrix: %w", err)
-	}
-
-	var output strings.Builder
-	for row := range matrix {
-		for col := range matrix[row] {
-			output.WriteString(m.renderCell(matrix[row][col]))
-		}
-		if row < len(matrix)-1 {
-			output.WriteByte('\n')
-		}
-	}
-
-	var rowIndicator strings.Builder
-	for i := 1; i <= 20; i++ {
-		fmt.Fprintf(&rowIndicator, "%d\n", i)
-	}
-	return lipgloss.JoinHorizontal(lipgloss.Center,
-		m.styles.Playfield.Render(output.String()),
-		m.styles.RowIndicator.Render(rowIndicator.String()),
-	), nil
-}
-
 func (m *SingleModel) informationView() string {
 	width := m.styles.Information.GetWidth()
 
@@ -425,6 +749,8 @@ func (m *SingleModel) informationView() string {
 		header = headerStyle.Render("GAME OVER")
 	case m.isPaused:
 		header = headerStyle.Render("PAUSED")
+	case m.bot != nil:
+		header = headerStyle.Render(strings.ToUpper(m.mode.String()) + " BOT")
 	default:
 		header = headerStyle.Render(strings.ToUpper(m.mode.String()))
 	}
@@ -433,91 +759,72 @@ func (m *SingleModel) informationView() string {
 		return fmt.Sprintf("%s%*s\n", title, width-(1+len(title)), value)
 	}
 
-	var gameTime float64
+	var gameTime time.Duration
 	if m.gameTimer != nil {
-		gameTime = m.gameTimer.GetTimeout().Seconds()
+		gameTime = m.gameTimer.GetTimeout()
 	} else {
-		gameTime = m.gameStopwatch.Elapsed().Seconds()
-	}
-
-	minutes := int(gameTime) / 60
-
-	var timeStr string
-	if minutes > 0 {
-		seconds := int(gameTime) % 60
-		timeStr += fmt.Sprintf("%02d:%02d", minutes, seconds)
-	} else {
-		timeStr += fmt.Sprintf("%06.3f", gameTime)
+		gameTime = m.gameElapsed()
 	}
+	timeStr := formatGameTime(gameTime)
 
 	var output string
 	output += fmt.Sprintln("Score:")
 	output += fmt.Sprintf("%*d\n", width-1, m.game.GetTotalScore())
 	output += fmt.Sprintln("Time:")
 	output += fmt.Sprintf("%*s\n", width-1, timeStr)
-	output += toFixedWidth("Lines:", strconv.Itoa(m.game.GetLinesCleared()))
-	output += toFixedWidth("Level:", strconv.Itoa(m.game.GetLevel()))
-
-	return m.styles.Information.Render(lipgloss.JoinVertical(lipgloss.Left, header, output))
-}
-
-func (m *SingleModel) holdView() string {
-	label := m.styles.Hold.Label.Render("Hold:")
-	item := m.styles.Hold.Item.Render(m.renderTetrimino(m.game.GetHoldTetrimino(), 1))
-	output := lipgloss.JoinVertical(lipgloss.Top, label, item)
-	return m.styles.Hold.View.Render(output)
-}
-
-func (m *SingleModel) bagView() string {
-	var output strings.Builder
-	output.WriteString("Next:\n")
-	for i, t := range m.game.GetBagTetriminos() {
-		if i >= m.nextQueueLength {
-			break
-		}
-		output.WriteByte('\n')
-		output.WriteString(m.renderTetrimino(&t, 1))
+	// A Sprint counts rows, since the goal lines of other modes depend on the type of line clear.
+	lines := strconv.Itoa(m.game.GetLinesCleared())
+	if m.lineGoal > 0 {
+		lines = strconv.Itoa(m.game.GetStats().Rows) + "/" + strconv.Itoa(m.lineGoal)
 	}
-	return m.styles.Bag.Render(output.String())
-}
-
-func (m *SingleModel) renderTetrimino(t *tetris.Tetrimino, background byte) string {
-	var output strings.Builder
-	for row := range t.Cells {
-		for col := range t.Cells[row] {
-			if t.Cells[row][col] {
-				output.WriteString(m.renderCell(t.Value))
-			} else {
-				output.WriteString(m.renderCell(background))
-			}
-		}
-		output.WriteByte('\n')
+	output += toFixedWidth("Lines:", lines)
+	if m.garbageLines > 0 {
+		output += toFixedWidth("Garbage:", strconv.Itoa(m.game.GetGarbageRemaining()))
 	}
-	return output.String()
-}
-
-func (m *SingleModel) renderCell(cell byte) string {
-	switch cell {
-	case 0:
-		return m.styles.EmptyCell.Render(m.styles.CellChar.Empty)
-	case 1:
-		return "  "
-	case 'G':
-		return m.styles.GhostCell.Render(m.styles.CellChar.Ghost)
-	default:
-		cellStyle, ok := m.styles.TetriminoCellStyles[cell]
-		if ok {
-			return cellStyle.Render(m.styles.CellChar.Tetriminos)
+	if m.game.IsMaster() {
+		// The section stop is included, which can be too wide to fit beside the title.
+		output += fmt.Sprintln("Level:")
+		output += fmt.Sprintf("%*s\n", width-1, fmt.Sprintf("%d/%d", m.game.GetLevel(), m.game.GetSectionStop()))
+		output += toFixedWidth("Grade:", m.game.GetMasterGrade().String())
+	} else {
+		output += toFixedWidth("Level:", strconv.Itoa(m.game.GetLevel()))
+	}
+	output += toFixedWidth("Combo:", strconv.Itoa(max(m.game.GetCombo(), 0)))
+	output += toFixedWidth("Max Combo:", strconv.Itoa(m.game.GetMaxCombo()))
+	if i := len(m.splits) - 1; i >= 0 {
+		output += toFixedWidth("Split:", strconv.Itoa(splitLines(m.lineGoal)[i])+"L")
+		output += fmt.Sprintf("%*s\n", width-1, formatSplitTime(m.splits[i]))
+		if i < len(m.bestSplits) {
+			output += toFixedWidth("vs PB:", formatSplitDelta(m.splits[i]-m.bestSplits[i]))
 		}
 	}
-	return "??"
+	if m.gameElapsed() < m.perfectClearUntil {
+		output += "\n" + m.styles.Callout.Width(width).Render("PERFECT\nCLEAR!")
+	}
+
+	return m.styles.Information.Render(lipgloss.JoinVertical(lipgloss.Left, header, output))
 }
 
 func (m *SingleModel) triggerGameOver() tea.Cmd {
+	if !m.game.IsGameOver() {
+		m.record(replay.InputEndGame)
+	}
+	m.recordSplits()
+	m.completed = m.game.GetGameOverReason() == single.GameOverLimitReached ||
+		(m.gameTimer != nil && m.gameTimer.GetTimeout() <= 0)
 	m.game.EndGame()
+	m.finalTime = m.gameElapsed()
 	m.isPaused = false
+	m.autoShift.Stop()
 
 	var cmds []tea.Cmd
+	if m.recorder != nil {
+		m.finalReplay = m.recorder.Finish(m.game, m.finalTime)
+		m.recorder = nil
+		if m.replayDir != "" {
+			cmds = append(cmds, saveReplayCmd(m.replayDir, m.finalReplay))
+		}
+	}
 	if m.gameTimer != nil {
 		m.gameTimer.SetTimeout(0)
 		cmds = append(cmds, m.gameTimer.Stop())
@@ -530,6 +837,11 @@ func (m *SingleModel) triggerGameOver() tea.Cmd {
 
 func (m *SingleModel) togglePause() tea.Cmd {
 	m.isPaused = !m.isPaused
+	m.autoShift.Stop()
+	if m.isPaused && m.softDropHold != nil && m.softDropHold.Release() && m.game.SetSoftDrop(false) {
+		// The release of the key would be missed whilst paused.
+		m.record(replay.InputToggleSoftDrop)
+	}
 
 	var cmd tea.Cmd
 	if m.gameTimer != nil {
@@ -543,3 +855,100 @@ func (m *SingleModel) togglePause() tea.Cmd {
 		cmd,
 	)
 }
+
+// handleGameEvent discards the bot's plan and records any splits reached once a new Tetrimino is in play,
+// and shows a callout for a Perfect Clear.
+func (m *SingleModel) handleGameEvent(e single.Event) {
+	switch e.Kind {
+	case single.EventPieceSpawned:
+		m.botPiece++
+		m.botMoves = nil
+		// The lines cleared by the previous Tetrimino have been counted by the time the next one spawns.
+		m.recordSplits()
+	case single.EventPerfectClear:
+		m.perfectClearUntil = m.gameElapsed() + calloutDuration
+	case single.EventPieceLocked, single.EventLinesCleared, single.EventBackToBack, single.EventLevelUp,
+		single.EventHold, single.EventGameOver, single.EventGarbageReceived:
+	}
+}
+
+// record captures an input for the replay, if the game is being recorded.
+func (m *SingleModel) record(kind replay.InputKind) {
+	if m.recorder == nil {
+		return
+	}
+	m.recorder.Record(m.gameElapsed(), kind)
+}
+
+// gameElapsed returns the time played so far, excluding time spent paused.
+func (m *SingleModel) gameElapsed() time.Duration {
+	if m.gameTimer != nil {
+		return m.timeLimit - max(m.gameTimer.GetTimeout(), 0)
+	}
+	return m.resumedElapsed + m.gameStopwatch.Elapsed()
+}
+
+// save returns the current state of the game so it can be resumed later.
+func (m *SingleModel) save() (*savegame.Save, error) {
+	state, err := m.game.State()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &savegame.Save{
+		Version:    savegame.Version,
+		Mode:       m.mode.String(),
+		Username:   m.username,
+		StartLevel: m.startLevel,
+		LineGoal:   m.lineGoal,
+		TimeLimit:  m.timeLimit,
+
+		GarbageLines:  m.garbageLines,
+		RisingGarbage: m.risingGarbage,
+		Splits:        m.splits,
+		Elapsed:       m.gameElapsed(),
+		Game:          state,
+	}
+	if m.recorder != nil {
+		s.Replay = m.recorder.Recording()
+	}
+	return s, nil
+}
+
+func writeSaveCmd(path string, s *savegame.Save) tea.Cmd {
+	return func() tea.Msg {
+		if err := savegame.Write(path, s); err != nil {
+			return tui.FatalErrorMsg(fmt.Errorf("saving game: %w", err))
+		}
+		return nil
+	}
+}
+
+func saveReplayCmd(dir string, r *replay.Replay) tea.Cmd {
+	return func() tea.Msg {
+		if _, err := replay.Save(dir, r); err != nil {
+			return tui.FatalErrorMsg(fmt.Errorf("saving replay: %w", err))
+		}
+		return nil
+	}
+}
+
+// piecesPerSecond returns the rate at which the given number of pieces were placed over the duration.
+func piecesPerSecond(pieces int, d time.Duration) float64 {
+	if d <= 0 {
+		return 0
+	}
+	return float64(pieces) / d.Seconds()
+}
+
+// formatGameTime formats the time as minutes and seconds, or seconds with millisecond
+// precision when less than a minute.
+func formatGameTime(d time.Duration) string {
+	gameTime := d.Seconds()
+	minutes := int(gameTime) / 60
+	if minutes > 0 {
+		seconds := int(gameTime) % 60
+		return fmt.Sprintf("%02d:%02d", minutes, seconds)
+	}
+	return fmt.Sprintf("%06.3f", gameTime)
+}
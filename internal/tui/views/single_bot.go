@@ -0,0 +1,102 @@
+package views
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/replay"
+	"github.com/Broderick-Westrope/tetrigo/internal/tui"
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris/bot"
+)
+
+// botMoveInterval is the time between each Move performed by the bot, so that it can be followed visually.
+const botMoveInterval = time.Millisecond * 100
+
+// botMoveToInputMap maps each bot Move to the replay Input which reproduces it.
+var botMoveToInputMap = map[bot.Move]replay.InputKind{
+	bot.MoveLeft:                   replay.InputMoveLeft,
+	bot.MoveRight:                  replay.InputMoveRight,
+	bot.MoveRotateClockwise:        replay.InputRotateClockwise,
+	bot.MoveRotateCounterClockwise: replay.InputRotateCounter,
+	bot.MoveDown:                   replay.InputGravity,
+	bot.MoveHardDrop:               replay.InputHardDrop,
+	bot.MoveHold:                   replay.InputHold,
+}
+
+type botTickMsg struct{}
+
+// botPlanMsg contains the Moves planned by the bot for the Tetrimino with the given sequence number.
+type botPlanMsg struct {
+	piece int
+	moves []bot.Move
+	err   error
+}
+
+func botTickCmd() tea.Cmd {
+	return tea.Tick(botMoveInterval, func(time.Time) tea.Msg {
+		return botTickMsg{}
+	})
+}
+
+// planBotCmd plans the Moves for the Tetrimino in play in the background since searching can be slow.
+func planBotCmd(b bot.Bot, state bot.State, piece int) tea.Cmd {
+	return func() tea.Msg {
+		moves, err := b.Plan(state)
+		return botPlanMsg{piece: piece, moves: moves, err: err}
+	}
+}
+
+// botTickUpdate performs the next Move planned by the bot, or requests a new plan if there are none left.
+func (m *SingleModel) botTickUpdate() (*SingleModel, tea.Cmd) {
+	if m.game.IsGameOver() {
+		return m, nil
+	}
+	if m.isPaused || m.game.IsSpawnPending() {
+		// The bot waits for the next Tetrimino to spawn before planning where to place it.
+		return m, botTickCmd()
+	}
+
+	if len(m.botMoves) == 0 {
+		if m.isBotPlanning {
+			return m, botTickCmd()
+		}
+		m.isBotPlanning = true
+		return m, tea.Batch(botTickCmd(), planBotCmd(m.bot, bot.StateFromGame(m.game), m.botPiece))
+	}
+
+	move := m.botMoves[0]
+	m.botMoves = m.botMoves[1:]
+	return m, tea.Batch(botTickCmd(), m.applyBotMove(move))
+}
+
+func (m *SingleModel) botPlanUpdate(msg botPlanMsg) (*SingleModel, tea.Cmd) {
+	m.isBotPlanning = false
+	if msg.err != nil {
+		return m, tui.FatalErrorCmd(fmt.Errorf("planning bot moves: %w", msg.err))
+	}
+	if msg.piece != m.botPiece {
+		// The Tetrimino in play has changed since the plan was requested.
+		return m, nil
+	}
+	m.botMoves = msg.moves
+	return m, nil
+}
+
+func (m *SingleModel) applyBotMove(move bot.Move) tea.Cmd {
+	m.record(botMoveToInputMap[move])
+	gameOver, err := bot.Apply(m.game, move)
+	if err != nil {
+		return tui.FatalErrorCmd(fmt.Errorf("applying bot move %q: %w", move, err))
+	}
+
+	var cmds []tea.Cmd
+	if gameOver {
+		cmds = append(cmds, m.triggerGameOver())
+	}
+	if move == bot.MoveHardDrop {
+		cmds = append(cmds, m.fallStopwatch.Reset())
+	}
+	return tea.Batch(cmds...)
+}
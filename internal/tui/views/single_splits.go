@@ -0,0 +1,68 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// sprintSplitLines is the number of rows cleared between each split of a Sprint.
+const sprintSplitLines = 10
+
+// splitLines returns the number of rows cleared at each split of a Sprint with the given goal.
+// A split is taken every sprintSplitLines rows, and at the goal.
+func splitLines(goal int) []int {
+	var lines []int
+	for l := sprintSplitLines; l < goal; l += sprintSplitLines {
+		lines = append(lines, l)
+	}
+	return append(lines, goal)
+}
+
+// recordSplits records the current time for each split of the Sprint which has been reached since the last call.
+func (m *SingleModel) recordSplits() {
+	// The first Tetrimino is spawned whilst the game is being created.
+	if m.lineGoal == 0 || m.game == nil {
+		return
+	}
+
+	lines := splitLines(m.lineGoal)
+	cleared := m.game.GetStats().Rows
+	for len(m.splits) < len(lines) && cleared >= lines[len(m.splits)] {
+		m.splits = append(m.splits, m.gameElapsed())
+	}
+}
+
+// splitsView returns a table of the splits, compared against the personal best splits if there are any.
+func (m *SingleModel) splitsView() string {
+	const format = "%-6s%10s%10s%8s"
+
+	rows := []string{fmt.Sprintf(format, "Split", "Time", "PB", "+/-")}
+	for i, lines := range splitLines(m.lineGoal)[:len(m.splits)] {
+		best, delta := "-", "-"
+		if i < len(m.bestSplits) {
+			best = formatSplitTime(m.bestSplits[i])
+			delta = formatSplitDelta(m.splits[i] - m.bestSplits[i])
+		}
+		rows = append(rows, fmt.Sprintf(format, fmt.Sprintf("%dL", lines), formatSplitTime(m.splits[i]), best, delta))
+	}
+
+	return lipgloss.NewStyle().Padding(1, 0).Render(strings.Join(rows, "\n"))
+}
+
+// formatSplitTime formats the time with millisecond precision, including minutes only if there are any.
+func formatSplitTime(d time.Duration) string {
+	minutes := int(d / time.Minute)
+	seconds := (d % time.Minute).Seconds()
+	if minutes > 0 {
+		return fmt.Sprintf("%d:%06.3f", minutes, seconds)
+	}
+	return fmt.Sprintf("%.3f", seconds)
+}
+
+// formatSplitDelta formats the difference from a personal best split in seconds, which is negative when ahead.
+func formatSplitDelta(d time.Duration) string {
+	return fmt.Sprintf("%+.2f", d.Seconds())
+}
@@ -2,14 +2,20 @@ package views
 
 import (
 	"math/rand/v2"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/Broderick-Westrope/tetrigo/internal/config"
 	"github.com/Broderick-Westrope/tetrigo/internal/data"
+	"github.com/Broderick-Westrope/tetrigo/internal/replay"
+	"github.com/Broderick-Westrope/tetrigo/internal/savegame"
 	"github.com/Broderick-Westrope/tetrigo/internal/tui"
 	"github.com/Broderick-Westrope/tetrigo/internal/tui/components"
 	"github.com/Broderick-Westrope/tetrigo/internal/tui/testutils"
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris"
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris/modes/single"
 	"github.com/Broderick-Westrope/x/exp/teatest"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/stretchr/testify/assert"
@@ -29,7 +35,8 @@ func TestSingle_InitialOutput(t *testing.T) {
 		&config.Config{
 			NextQueueLength: 0,
 			GhostEnabled:    true,
-			LockDownMode:    "",
+			LockDownMode:    "Extended",
+			Randomizer:      "7-bag",
 			MaxLevel:        0,
 			EndOnMaxLevel:   false,
 			Theme:           config.DefaultTheme(),
@@ -60,9 +67,12 @@ func TestSingle_Interaction(t *testing.T) {
 		&config.Config{
 			NextQueueLength: 0,
 			GhostEnabled:    true,
-			LockDownMode:    "",
+			LockDownMode:    "Extended",
+			Randomizer:      "7-bag",
 			MaxLevel:        0,
 			EndOnMaxLevel:   false,
+			DAS:             167,
+			ARR:             33,
 			Theme:           config.DefaultTheme(),
 			Keys:            config.DefaultKeys(),
 		},
@@ -133,7 +143,8 @@ func TestSingle_GameOverOutput(t *testing.T) {
 		&config.Config{
 			NextQueueLength: 0,
 			GhostEnabled:    true,
-			LockDownMode:    "",
+			LockDownMode:    "Extended",
+			Randomizer:      "7-bag",
 			MaxLevel:        0,
 			EndOnMaxLevel:   false,
 			Theme:           config.DefaultTheme(),
@@ -172,7 +183,8 @@ func TestSingle_PausedOutput(t *testing.T) {
 		&config.Config{
 			NextQueueLength: 0,
 			GhostEnabled:    true,
-			LockDownMode:    "",
+			LockDownMode:    "Extended",
+			Randomizer:      "7-bag",
 			MaxLevel:        0,
 			EndOnMaxLevel:   false,
 			Theme:           config.DefaultTheme(),
@@ -202,7 +214,8 @@ func TestSingle_GameOverSwitchModeMsg(t *testing.T) {
 		&config.Config{
 			NextQueueLength: 0,
 			GhostEnabled:    true,
-			LockDownMode:    "",
+			LockDownMode:    "Extended",
+			Randomizer:      "7-bag",
 			MaxLevel:        0,
 			EndOnMaxLevel:   false,
 			Theme:           config.DefaultTheme(),
@@ -211,6 +224,11 @@ func TestSingle_GameOverSwitchModeMsg(t *testing.T) {
 		WithRandSource(rand.New(rand.NewPCG(0, 0))),
 	)
 	require.NoError(t, err)
+	mockGameStopwatch := components.NewMockStopwatch(t)
+	mockGameStopwatch.EXPECT().Init().Return(nil)
+	mockGameStopwatch.EXPECT().Update(mock.Anything).Return(mockGameStopwatch, nil)
+	mockGameStopwatch.EXPECT().Elapsed().Return(24 * time.Second)
+	m.gameStopwatch = mockGameStopwatch
 	tm := teatest.NewTestModel(t, m)
 
 	switchModeMsgCh := make(chan tui.SwitchModeMsg, 1)
@@ -240,13 +258,571 @@ func TestSingle_GameOverSwitchModeMsg(t *testing.T) {
 			Rank:     0,
 			GameMode: tui.ModeMarathon.String(),
 			Name:     "testuser",
-			Time:     time.Duration(0),
+			Time:     24 * time.Second,
 			Score:    230,
 			Lines:    0,
 			Level:    1,
+
+			StartLevel: 1,
+			Pieces:     12,
+			PPS:        0.5,
 		}, leaderboardInput.NewEntry)
 
 	case <-time.After(time.Second):
 		t.Fatal("Timeout waiting for switch mode message")
 	}
 }
+
+func TestSingle_BotGameOverNotEntered(t *testing.T) {
+	cfg := &config.Config{
+		GhostEnabled: true,
+		LockDownMode: "Extended",
+		Randomizer:   "7-bag",
+		Theme:        config.DefaultTheme(),
+		Keys:         config.DefaultKeys(),
+	}
+	m, err := NewSingleModel(tui.NewSingleInput(tui.ModeMarathon, 1, "testuser", tui.WithBot()), cfg)
+	require.NoError(t, err)
+	m.triggerGameOver()
+
+	_, cmd := m.gameOverUpdate(tea.KeyMsg{Type: tea.KeyEnter})
+	require.NotNil(t, cmd)
+	switchModeMsg, ok := cmd().(tui.SwitchModeMsg)
+	require.True(t, ok)
+	require.Equal(t, tui.ModeLeaderboard, switchModeMsg.Target)
+
+	leaderboardInput, ok := switchModeMsg.Input.(*tui.LeaderboardInput)
+	require.True(t, ok, "Expected %T, got %T", &tui.LeaderboardInput{}, switchModeMsg.Input)
+	assert.Nil(t, leaderboardInput.NewEntry)
+}
+
+func TestSingle_MatrixSizeGameMode(t *testing.T) {
+	cfg := &config.Config{
+		GhostEnabled: true,
+		LockDownMode: "Extended",
+		Randomizer:   "7-bag",
+		Matrix:       map[string]config.MatrixSize{"sprint": {Width: 4}},
+		Theme:        config.DefaultTheme(),
+		Keys:         config.DefaultKeys(),
+	}
+
+	m, err := NewSingleModel(tui.NewSingleInput(tui.ModeSprint, 1, "testuser"), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "Sprint 4x20", m.gameMode)
+	matrix := m.game.GetMatrix()
+	assert.Equal(t, 4, matrix.GetWidth())
+
+	m, err = NewSingleModel(tui.NewSingleInput(tui.ModeSprint, 1, "testuser", tui.WithMatrixSize(10, 0)), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "Sprint", m.gameMode)
+}
+
+func TestSingle_GameOverEntryVerifies(t *testing.T) {
+	tt := map[string]struct {
+		in   *tui.SingleInput
+		keys string
+		// remaining is how much of the Ultra time limit is left when the game starts.
+		remaining     time.Duration
+		wantCompleted bool
+	}{
+		"marathon top out": {
+			in:   &tui.SingleInput{Mode: tui.ModeMarathon, Level: 1, Username: "testuser"},
+			keys: "awdwqwewwwwwwwwwwww",
+		},
+		"ultra time limit": {
+			in: &tui.SingleInput{
+				Mode:      tui.ModeUltra,
+				Level:     1,
+				Username:  "testuser",
+				TimeLimit: time.Minute,
+			},
+			keys:          "aw",
+			remaining:     200 * time.Millisecond,
+			wantCompleted: true,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			m, err := NewSingleModel(
+				tc.in,
+				&config.Config{
+					NextQueueLength: 0,
+					GhostEnabled:    true,
+					LockDownMode:    "Extended",
+					Randomizer:      "7-bag",
+					Theme:           config.DefaultTheme(),
+					Keys:            config.DefaultKeys(),
+				},
+				WithSeed([2]uint64{1, 2}),
+			)
+			require.NoError(t, err)
+			if tc.remaining > 0 {
+				m.gameTimer.SetTimeout(tc.remaining)
+			}
+			tm := teatest.NewTestModel(t, m)
+
+			switchModeMsgCh := make(chan tui.SwitchModeMsg, 1)
+			go testutils.WaitForMsgOfType(t, tm, switchModeMsgCh, time.Second+2*tc.remaining)
+
+			// move, rotate and hard drop until the game is over
+			for _, r := range tc.keys {
+				tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+				time.Sleep(10 * time.Millisecond)
+			}
+			time.Sleep(2 * tc.remaining)
+
+			// continue past game over message
+			tm.Send(tea.KeyMsg{Type: tea.KeyEnter})
+			time.Sleep(10 * time.Millisecond)
+
+			select {
+			case switchModeMsg := <-switchModeMsgCh:
+				leaderboardInput, ok := switchModeMsg.Input.(*tui.LeaderboardInput)
+				require.True(t, ok, "Expected %T, got %T", &tui.LeaderboardInput{}, switchModeMsg.Input)
+
+				entry := leaderboardInput.NewEntry
+				require.NotNil(t, entry.Replay)
+				assert.Equal(t, [2]uint64{1, 2}, entry.Replay.Seed)
+				assert.NotEmpty(t, entry.Replay.Inputs)
+				assert.Equal(t, tc.wantCompleted, entry.Completed)
+				assert.NoError(t, entry.Verify())
+
+			case <-time.After(time.Second + 2*tc.remaining):
+				t.Fatal("Timeout waiting for switch mode message")
+			}
+		})
+	}
+}
+
+func TestSingle_PerfectClearCallout(t *testing.T) {
+	m, err := NewSingleModel(
+		&tui.SingleInput{
+			Mode:     tui.ModeMarathon,
+			Level:    1,
+			Username: "testuser",
+		},
+		&config.Config{
+			NextQueueLength: 1,
+			GhostEnabled:    true,
+			LockDownMode:    "Extended",
+			Randomizer:      "7-bag",
+			Theme:           config.DefaultTheme(),
+			Keys:            config.DefaultKeys(),
+		},
+		WithRandSource(rand.New(rand.NewPCG(0, 0))),
+	)
+	require.NoError(t, err)
+	assert.NotContains(t, m.View(), "PERFECT")
+
+	m.handleGameEvent(single.Event{Kind: single.EventPerfectClear, Action: tetris.Actions.SinglePerfectClear})
+	assert.Contains(t, m.View(), "PERFECT")
+}
+
+func TestSingle_AutoShift(t *testing.T) {
+	m, err := NewSingleModel(
+		&tui.SingleInput{
+			Mode:     tui.ModeMarathon,
+			Level:    1,
+			Username: "testuser",
+		},
+		&config.Config{
+			GhostEnabled: true,
+			LockDownMode: "Extended",
+			Randomizer:   "7-bag",
+			DAS:          0,
+			ARR:          0,
+			Theme:        config.DefaultTheme(),
+			Keys:         config.DefaultKeys(),
+		},
+		WithRandSource(rand.New(rand.NewPCG(0, 0))),
+	)
+	require.NoError(t, err)
+	_, _ = m.Update(tui.KeyboardEnhancementsMsg{Flags: 3})
+	startX := m.game.GetTetriminoInPlay().Position.X
+
+	// The press shifts once and starts the auto shift ticker.
+	_, cmd := m.playingUpdate(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	require.NotNil(t, cmd)
+	assert.Equal(t, startX-1, m.game.GetTetriminoInPlay().Position.X)
+
+	// With no DAS or ARR the first tick shifts to the wall.
+	_, cmd = m.playingUpdate(cmd())
+	require.NotNil(t, cmd)
+	assert.False(t, m.game.MoveLeft())
+
+	// Releasing the key stops the ticker.
+	_, _ = m.playingUpdate(tui.KeyReleaseMsg{Key: tea.Key{Type: tea.KeyRunes, Runes: []rune("a")}})
+	_, cmd = m.playingUpdate(cmd())
+	assert.Nil(t, cmd)
+}
+
+func TestSingle_HeldSoftDrop(t *testing.T) {
+	m, err := NewSingleModel(
+		&tui.SingleInput{
+			Mode:     tui.ModeMarathon,
+			Level:    1,
+			Username: "testuser",
+		},
+		&config.Config{
+			GhostEnabled: true,
+			LockDownMode: "Extended",
+			Randomizer:   "7-bag",
+			SoftDropMode: config.SoftDropModeHold,
+			Theme:        config.DefaultTheme(),
+			Keys:         config.DefaultKeys(),
+		},
+		WithRandSource(rand.New(rand.NewPCG(0, 0))),
+	)
+	require.NoError(t, err)
+	_, _ = m.Update(tui.KeyboardEnhancementsMsg{Flags: 3})
+	softDrop := tea.Key{Type: tea.KeyRunes, Runes: []rune("s")}
+
+	// Soft drop lasts whilst the key is held, including its repeats.
+	_, _ = m.playingUpdate(tea.KeyMsg(softDrop))
+	assert.True(t, m.game.IsSoftDropping())
+	_, _ = m.playingUpdate(tea.KeyMsg(softDrop))
+	assert.True(t, m.game.IsSoftDropping())
+
+	_, _ = m.playingUpdate(tui.KeyReleaseMsg{Key: softDrop})
+	assert.False(t, m.game.IsSoftDropping())
+
+	// Pausing ends the soft drop since the release would be missed.
+	_, _ = m.playingUpdate(tea.KeyMsg(softDrop))
+	require.True(t, m.game.IsSoftDropping())
+	_, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	require.True(t, m.isPaused)
+	assert.False(t, m.game.IsSoftDropping())
+}
+
+func TestSingle_SaveAndResume(t *testing.T) {
+	cfg := &config.Config{
+		GhostEnabled: true,
+		LockDownMode: "Extended",
+		Randomizer:   "7-bag",
+		Theme:        config.DefaultTheme(),
+		Keys:         config.DefaultKeys(),
+	}
+
+	tt := map[string]struct {
+		mode        tui.Mode
+		setElapsed  func(t *testing.T, m *SingleModel)
+		wantElapsed time.Duration
+	}{
+		"marathon": {
+			mode: tui.ModeMarathon,
+			setElapsed: func(t *testing.T, m *SingleModel) {
+				mockGameStopwatch := components.NewMockStopwatch(t)
+				mockGameStopwatch.EXPECT().Elapsed().Return(30 * time.Second)
+				m.gameStopwatch = mockGameStopwatch
+			},
+			wantElapsed: 30 * time.Second,
+		},
+		"ultra": {
+			mode: tui.ModeUltra,
+			setElapsed: func(_ *testing.T, m *SingleModel) {
+				m.gameTimer.SetTimeout(tui.DefaultTimeLimit - 45*time.Second)
+			},
+			wantElapsed: 45 * time.Second,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "save.json")
+			in := tui.NewSingleInput(tc.mode, 1, "testuser")
+
+			m, err := NewSingleModel(in, cfg, WithSeed([2]uint64{1, 2}), WithSavePath(path))
+			require.NoError(t, err)
+			_, _ = m.playingUpdate(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+			_, _ = m.playingUpdate(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+			_, _ = m.playingUpdate(tea.KeyMsg{Type: tea.KeyEsc})
+			require.True(t, m.isPaused)
+			tc.setElapsed(t, m)
+
+			// Exiting from the pause menu saves the game.
+			_, cmd := m.pausedUpdate(tea.KeyMsg{Type: tea.KeySpace})
+			require.NotNil(t, cmd)
+			save, err := m.save()
+			require.NoError(t, err)
+			require.Nil(t, writeSaveCmd(path, save)())
+
+			loaded, err := savegame.Load(path)
+			require.NoError(t, err)
+			assert.Equal(t, tc.mode.String(), loaded.Mode)
+			assert.Equal(t, "testuser", loaded.Username)
+			assert.Equal(t, 1, loaded.StartLevel)
+
+			resumed, err := NewSingleModel(in, cfg, WithResume(loaded))
+			require.NoError(t, err)
+			want, err := m.game.State()
+			require.NoError(t, err)
+			got, err := resumed.game.State()
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+			assert.Equal(t, tc.wantElapsed, resumed.gameElapsed())
+			assert.Equal(t, 1, resumed.startLevel)
+
+			// The save can only be resumed in its own mode.
+			_, err = NewSingleModel(tui.NewSingleInput(tui.ModeSprint, 1, "testuser"), cfg, WithResume(loaded))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSingle_SprintSplits(t *testing.T) {
+	cfg := &config.Config{
+		GhostEnabled: true,
+		LockDownMode: "Extended",
+		Randomizer:   "7-bag",
+		Theme:        config.DefaultTheme(),
+		Keys:         config.DefaultKeys(),
+	}
+	in := tui.NewSingleInput(tui.ModeSprint, 1, "testuser", tui.WithLineGoal(25))
+
+	// The game is resumed part way through so that rows have been cleared.
+	m, err := NewSingleModel(in, cfg, WithSeed([2]uint64{1, 2}))
+	require.NoError(t, err)
+	state, err := m.game.State()
+	require.NoError(t, err)
+	assert.Equal(t, 25, state.LineGoal)
+	state.Stats.Rows = 12
+	save := &savegame.Save{Mode: "Sprint", Username: "testuser", LineGoal: 25, Elapsed: 18 * time.Second, Game: state}
+
+	m, err = NewSingleModel(in, cfg, WithResume(save), WithBestSplits([]time.Duration{20 * time.Second, 45 * time.Second}))
+	require.NoError(t, err)
+	m.recordSplits()
+	require.Equal(t, []time.Duration{18 * time.Second}, m.splits)
+
+	info := m.informationView()
+	assert.Contains(t, info, "Lines: 12/25")
+	assert.Contains(t, info, "Split:   10L")
+	assert.Contains(t, info, "18.000")
+	assert.Contains(t, info, "vs PB: -2.00")
+
+	m.triggerGameOver()
+	assert.Contains(t, m.View(), "10L       18.000    20.000   -2.00")
+	assert.NotContains(t, m.View(), "20L")
+
+	_, cmd := m.gameOverUpdate(tea.KeyMsg{Type: tea.KeyEnter})
+	require.NotNil(t, cmd)
+	switchModeMsg, ok := cmd().(tui.SwitchModeMsg)
+	require.True(t, ok)
+	leaderboardInput, ok := switchModeMsg.Input.(*tui.LeaderboardInput)
+	require.True(t, ok)
+	assert.Equal(t, "Sprint 25L", leaderboardInput.GameMode)
+	assert.Equal(t, "Sprint 25L", leaderboardInput.NewEntry.GameMode)
+	assert.Equal(t, []time.Duration{18 * time.Second}, leaderboardInput.NewEntry.Splits)
+}
+
+func TestSingle_SprintSplitsCountRows(t *testing.T) {
+	cfg := &config.Config{
+		GhostEnabled: true,
+		LockDownMode: "Extended",
+		Randomizer:   "7-bag",
+		Theme:        config.DefaultTheme(),
+		Keys:         config.DefaultKeys(),
+	}
+	in := tui.NewSingleInput(tui.ModeSprint, 1, "testuser", tui.WithLineGoal(25))
+
+	// The game is resumed after a Tetris, with the bottom four rows filled except for a well in the first column
+	// and a vertical I Tetrimino above it.
+	m, err := NewSingleModel(in, cfg, WithSeed([2]uint64{1, 2}))
+	require.NoError(t, err)
+	state, err := m.game.State()
+	require.NoError(t, err)
+	bottom := len(state.Matrix) - 1
+	for row := bottom - 3; row <= bottom; row++ {
+		state.Matrix[row] = "." + strings.Repeat(string(tetris.GarbageCell), len(state.Matrix[row])-1)
+	}
+	state.TetriminoInPlay = single.TetriminoState{
+		Value:            'I',
+		Cells:            []string{"#", "#", "#", "#"},
+		Position:         tetris.Coordinate{X: 0, Y: len(state.Matrix) - tetris.DefaultMatrixHeight},
+		CompassDirection: 1,
+	}
+	state.Stats = single.Stats{Pieces: 1, Rows: 4, Tetrises: 1}
+	state.Scoring.Lines = 8
+	state.Scoring.BackToBack = true
+	save := &savegame.Save{Mode: "Sprint", Username: "testuser", LineGoal: 25, Elapsed: 5 * time.Second, Game: state}
+	m, err = NewSingleModel(in, cfg, WithResume(save))
+	require.NoError(t, err)
+
+	// The Back-to-Back Tetris is worth 12 goal lines, but only clears four rows, so the first split is not reached.
+	_, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	require.Equal(t, 8, m.game.GetStats().Rows)
+	require.Equal(t, 20, m.game.GetLinesCleared())
+	assert.Empty(t, m.splits)
+	assert.Contains(t, m.informationView(), "Lines:  8/25")
+}
+
+func TestSingle_UltraTimeLimit(t *testing.T) {
+	t.Parallel()
+	cfg := &config.Config{
+		GhostEnabled: true,
+		LockDownMode: "Extended",
+		Randomizer:   "7-bag",
+		Theme:        config.DefaultTheme(),
+		Keys:         config.DefaultKeys(),
+	}
+
+	tt := map[string]struct {
+		timeLimit    time.Duration
+		wantTimeout  time.Duration
+		wantGameMode string
+		wantErr      bool
+	}{
+		"default": {
+			wantTimeout:  tui.DefaultTimeLimit,
+			wantGameMode: "Ultra",
+		},
+		"five minutes": {
+			timeLimit:    5 * time.Minute,
+			wantTimeout:  5 * time.Minute,
+			wantGameMode: "Ultra 5min",
+		},
+		"negative": {
+			timeLimit: -time.Minute,
+			wantErr:   true,
+		},
+		"partial minute": {
+			timeLimit: 90 * time.Second,
+			wantErr:   true,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			in := tui.NewSingleInput(tui.ModeUltra, 1, "testuser", tui.WithTimeLimit(tc.timeLimit))
+
+			m, err := NewSingleModel(in, cfg)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantTimeout, m.gameTimer.GetTimeout())
+			assert.Equal(t, tc.wantGameMode, m.gameMode)
+
+			m.gameTimer.SetTimeout(tc.wantTimeout - 50*time.Second)
+			assert.Equal(t, 50*time.Second, m.gameElapsed())
+
+			// The time limit is saved so that the remaining time is restored when the game is resumed.
+			save, err := m.save()
+			require.NoError(t, err)
+			resumed, err := NewSingleModel(
+				tui.NewSingleInput(tui.ModeUltra, 1, "testuser", tui.WithTimeLimit(save.TimeLimit)), cfg, WithResume(save),
+			)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantTimeout-50*time.Second, resumed.gameTimer.GetTimeout())
+		})
+	}
+}
+
+func TestSingle_Dig(t *testing.T) {
+	cfg := &config.Config{
+		GhostEnabled: true,
+		LockDownMode: "Extended",
+		Randomizer:   "7-bag",
+		Theme:        config.DefaultTheme(),
+		Keys:         config.DefaultKeys(),
+	}
+	in := tui.NewSingleInput(tui.ModeDig, 1, "testuser", tui.WithGarbageLines(5), tui.WithRisingGarbage())
+
+	m, err := NewSingleModel(in, cfg, WithSeed([2]uint64{1, 2}))
+	require.NoError(t, err)
+	assert.Equal(t, "Dig 5L Rising", m.gameMode)
+	assert.Equal(t, 5, m.game.GetGarbageRemaining())
+	assert.Contains(t, m.informationView(), "Garbage:   5")
+
+	// A garbage line rises for each interval played.
+	mockGameStopwatch := components.NewMockStopwatch(t)
+	mockGameStopwatch.EXPECT().Elapsed().Return(2*risingGarbageInterval + time.Second)
+	m.gameStopwatch = mockGameStopwatch
+	m.riseGarbage()
+	m.riseGarbage()
+	assert.Equal(t, 2, m.garbageRisen)
+	assert.Equal(t, 2, m.game.GetPendingGarbage())
+	assert.Equal(t, 7, m.game.GetGarbageRemaining())
+
+	save, err := m.save()
+	require.NoError(t, err)
+	assert.Equal(t, 5, save.GarbageLines)
+	assert.True(t, save.RisingGarbage)
+	resumed, err := NewSingleModel(in, cfg, WithResume(save))
+	require.NoError(t, err)
+	assert.Equal(t, 2, resumed.garbageRisen)
+	assert.Equal(t, 7, resumed.game.GetGarbageRemaining())
+
+	// The rising garbage is recorded, so the replay reproduces the game.
+	m.triggerGameOver()
+	require.NotNil(t, m.finalReplay)
+	p, err := replay.NewPlayer(m.finalReplay)
+	require.NoError(t, err)
+	require.NoError(t, p.PlayToEnd())
+	assert.Equal(t, m.game.GetMatrix(), p.Game().GetMatrix())
+	assert.Equal(t, 7, p.Game().GetGarbageRemaining())
+
+	_, err = NewSingleModel(tui.NewSingleInput(tui.ModeDig, 1, "testuser", tui.WithGarbageLines(20)), cfg)
+	assert.Error(t, err)
+}
+
+func TestSingle_Master(t *testing.T) {
+	cfg := &config.Config{
+		GhostEnabled:     true,
+		LockDownMode:     "Extended",
+		Randomizer:       "7-bag",
+		MasterLockDelays: []int{1000},
+		Theme:            config.DefaultTheme(),
+		Keys:             config.DefaultKeys(),
+	}
+	in := tui.NewSingleInput(tui.ModeMaster, 1, "testuser")
+
+	m, err := NewSingleModel(in, cfg, WithSeed([2]uint64{1, 2}))
+	require.NoError(t, err)
+	assert.Equal(t, "Master", m.gameMode)
+	assert.True(t, m.game.IsMaster())
+	assert.Contains(t, m.informationView(), "Level:       \n        0/99 ")
+	assert.Contains(t, m.informationView(), "Grade:     9")
+
+	_, err = m.game.HardDrop()
+	require.NoError(t, err)
+	require.True(t, m.game.IsSpawnPending())
+
+	save, err := m.save()
+	require.NoError(t, err)
+	resumed, err := NewSingleModel(in, cfg, WithResume(save))
+	require.NoError(t, err)
+	assert.True(t, resumed.game.IsMaster())
+	assert.True(t, resumed.game.IsSpawnPending())
+
+	// The configured lock delay is used, and recorded so the replay reproduces the game.
+	m.triggerGameOver()
+	require.NotNil(t, m.finalReplay)
+	require.Len(t, m.finalReplay.Config.MasterSections, len(tetris.DefaultMasterSections))
+	assert.Equal(t, time.Second, m.finalReplay.Config.MasterSections[0].LockDelay)
+	got, err := m.finalReplay.Simulate()
+	require.NoError(t, err)
+	assert.Equal(t, m.finalReplay.Result, got)
+}
+
+func TestSplitLines(t *testing.T) {
+	t.Parallel()
+
+	tt := map[string]struct {
+		goal int
+		want []int
+	}{
+		"less than a split":  {goal: 5, want: []int{5}},
+		"multiple of splits": {goal: 40, want: []int{10, 20, 30, 40}},
+		"between splits":     {goal: 25, want: []int{10, 20, 25}},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, splitLines(tc.goal))
+		})
+	}
+}
@@ -0,0 +1,384 @@
+package views
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Broderick-Westrope/charmutils"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/stopwatch"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/config"
+	"github.com/Broderick-Westrope/tetrigo/internal/replay"
+	"github.com/Broderick-Westrope/tetrigo/internal/tui"
+	"github.com/Broderick-Westrope/tetrigo/internal/tui/components"
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris"
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris/modes/single"
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris/modes/versus"
+)
+
+// winnerMessage is overlaid on the boards once the game is over. It is formatted with the winning player number.
+const winnerMessage = `
+PLAYER %d WINS!
+
+Press EXIT to return to the menu.
+`
+
+var _ tea.Model = &VersusModel{}
+
+// VersusModel is a local two player game where both players share the keyboard.
+type VersusModel struct {
+	game    *versus.Game
+	players [2]*versusPlayer
+	board   *boardRenderer
+	seed    [2]uint64
+
+	styles   *components.GameStyles
+	help     help.Model
+	keys     *versusKeyMap
+	isPaused bool
+
+	width  int
+	height int
+}
+
+// versusPlayer is the state of the view for one player of a VersusModel.
+type versusPlayer struct {
+	player        *versus.Player
+	keys          *components.GameKeyMap
+	fallStopwatch components.Stopwatch
+	fallElapsed   time.Duration
+}
+
+func NewVersusModel(in *tui.VersusInput, cfg *config.Config, opts ...func(*VersusModel)) (*VersusModel, error) {
+	styles := components.CreateGameStyles(cfg.Theme)
+	m := &VersusModel{
+		board:  newBoardRenderer(styles, cfg.NextQueueLength),
+		seed:   [2]uint64{rand.Uint64(), rand.Uint64()},
+		styles: styles,
+		help:   help.New(),
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	lockDownMode, err := tetris.ParseLockDownMode(cfg.LockDownMode)
+	if err != nil {
+		return nil, fmt.Errorf("parsing lock down mode: %w", err)
+	}
+	size := cfg.GetMatrixSize(tui.ModeVersus.String())
+
+	var gameIn versus.Input
+	for i := range gameIn.Players {
+		// Each player has their own randomizer with the same seed so that both receive the same Tetriminos.
+		randomizer, err := tetris.NewRandomizer(cfg.Randomizer)
+		if err != nil {
+			return nil, fmt.Errorf("creating randomizer: %w", err)
+		}
+		gameIn.Players[i] = &single.Input{
+			Level:          in.Level,
+			MaxLevel:       cfg.MaxLevel,
+			IncreaseLevel:  true,
+			LockDownMode:   lockDownMode,
+			Width:          size.Width,
+			Height:         size.Height,
+			GhostEnabled:   cfg.GhostEnabled,
+			Rand:           replay.NewRand(m.seed),
+			Randomizer:     randomizer,
+			SoftDropFactor: cfg.SoftDropFactor,
+		}
+	}
+	gameIn.Rand = replay.NewRand([2]uint64{m.seed[1], m.seed[0]})
+
+	m.game, err = versus.NewGame(&gameIn)
+	if err != nil {
+		return nil, fmt.Errorf("creating versus game: %w", err)
+	}
+
+	playerKeys := [2]*config.PlayerKeys{cfg.Keys.Versus.PlayerOne, cfg.Keys.Versus.PlayerTwo}
+	m.keys = new(versusKeyMap)
+	for i := range m.players {
+		player := m.game.Player(i)
+		m.keys.players[i] = components.ConstructPlayerKeyMap(cfg.Keys, playerKeys[i])
+		m.players[i] = &versusPlayer{
+			player:        player,
+			keys:          m.keys.players[i],
+			fallStopwatch: components.NewStopwatchWithInterval(player.Game().GetDefaultFallInterval()),
+		}
+	}
+
+	return m, nil
+}
+
+// WithVersusSeed sets the seed of the random source used for Tetrimino generation and garbage.
+func WithVersusSeed(seed [2]uint64) func(*VersusModel) {
+	return func(m *VersusModel) {
+		m.seed = seed
+	}
+}
+
+func (m *VersusModel) Init() tea.Cmd {
+	return tea.Batch(
+		m.players[0].fallStopwatch.Init(),
+		m.players[1].fallStopwatch.Init(),
+	)
+}
+
+func (m *VersusModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	var cmds []tea.Cmd
+
+	// Dependencies
+	for _, p := range m.players {
+		cmd, err := charmutils.UpdateTypedModel(&p.fallStopwatch, msg)
+		if err != nil {
+			cmds = append(cmds, tui.FatalErrorCmd(err))
+		}
+		cmds = append(cmds, cmd)
+	}
+
+	// Operations that can be performed all the time
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, m.keys.players[0].Help):
+			m.help.ShowAll = !m.help.ShowAll
+			return m, tea.Batch(cmds...)
+		case key.Matches(msg, m.keys.players[0].ForceQuit):
+			return m, tea.Quit
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, tea.Batch(cmds...)
+	}
+
+	switch {
+	case m.game.IsGameOver():
+		m, cmd = m.gameOverUpdate(msg)
+	case m.isPaused:
+		m, cmd = m.pausedUpdate(msg)
+	default:
+		m, cmd = m.playingUpdate(msg)
+	}
+	cmds = append(cmds, cmd)
+	return m, tea.Batch(cmds...)
+}
+
+func (m *VersusModel) gameOverUpdate(msg tea.Msg) (*VersusModel, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		if key.Matches(msg, m.keys.players[0].Exit) {
+			return m, tui.SwitchModeCmd(tui.ModeMenu, tui.NewMenuInput())
+		}
+	}
+	return m, nil
+}
+
+func (m *VersusModel) pausedUpdate(msg tea.Msg) (*VersusModel, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, m.keys.players[0].Exit):
+			return m, m.togglePause()
+		case key.Matches(msg, m.keys.players[0].Hold, m.keys.players[1].Hold):
+			return m, tui.SwitchModeCmd(tui.ModeMenu, tui.NewMenuInput())
+		}
+	}
+	return m, nil
+}
+
+func (m *VersusModel) playingUpdate(msg tea.Msg) (*VersusModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if key.Matches(msg, m.keys.players[0].Exit) {
+			return m, m.togglePause()
+		}
+		for _, p := range m.players {
+			if cmd, ok := m.playerKeyMsgUpdate(p, msg); ok {
+				return m, cmd
+			}
+		}
+
+	case stopwatch.TickMsg:
+		for _, p := range m.players {
+			if msg.ID == p.fallStopwatch.ID() {
+				return m, m.fallStopwatchTick(p)
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// playerKeyMsgUpdate performs the game operation for the key, if it is bound for the given player.
+// If the key is not bound for the player false is returned.
+func (m *VersusModel) playerKeyMsgUpdate(p *versusPlayer, msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, p.keys.Left):
+		p.player.MoveLeft()
+		return nil, true
+
+	case key.Matches(msg, p.keys.Right):
+		p.player.MoveRight()
+		return nil, true
+
+	case key.Matches(msg, p.keys.Clockwise):
+		if err := p.player.Rotate(true); err != nil {
+			return tui.FatalErrorCmd(fmt.Errorf("rotating clockwise: %w", err)), true
+		}
+		return nil, true
+
+	case key.Matches(msg, p.keys.CounterClockwise):
+		if err := p.player.Rotate(false); err != nil {
+			return tui.FatalErrorCmd(fmt.Errorf("rotating counter-clockwise: %w", err)), true
+		}
+		return nil, true
+
+	case key.Matches(msg, p.keys.HardDrop):
+		gameOver, err := p.player.HardDrop()
+		if err != nil {
+			return tui.FatalErrorCmd(fmt.Errorf("hard dropping: %w", err)), true
+		}
+		if gameOver {
+			return m.triggerGameOver(), true
+		}
+		return p.fallStopwatch.Reset(), true
+
+	case key.Matches(msg, p.keys.SoftDrop):
+		p.player.ToggleSoftDrop()
+		return m.fallStopwatchTick(p), true
+
+	case key.Matches(msg, p.keys.Hold):
+		gameOver, err := p.player.Hold()
+		if err != nil {
+			return tui.FatalErrorCmd(fmt.Errorf("holding tetrimino: %w", err)), true
+		}
+		if gameOver {
+			return m.triggerGameOver(), true
+		}
+		return nil, true
+	}
+	return nil, false
+}
+
+func (m *VersusModel) fallStopwatchTick(p *versusPlayer) tea.Cmd {
+	elapsed := p.fallStopwatch.Elapsed() - p.fallElapsed
+	if elapsed < 0 {
+		// The stopwatch has been reset since the last tick.
+		elapsed = p.fallStopwatch.Elapsed()
+	}
+	p.fallElapsed = p.fallStopwatch.Elapsed()
+	p.player.UpdateLockDown(elapsed)
+
+	gameOver, err := p.player.TickLower()
+	if err != nil {
+		return tui.FatalErrorCmd(fmt.Errorf("lowering tetrimino (tick): %w", err))
+	}
+	if gameOver {
+		return m.triggerGameOver()
+	}
+	p.fallStopwatch.SetInterval(p.player.Game().GetFallInterval())
+	return nil
+}
+
+func (m *VersusModel) triggerGameOver() tea.Cmd {
+	m.isPaused = false
+	return tea.Batch(
+		m.players[0].fallStopwatch.Stop(),
+		m.players[1].fallStopwatch.Stop(),
+	)
+}
+
+func (m *VersusModel) togglePause() tea.Cmd {
+	m.isPaused = !m.isPaused
+	return tea.Batch(
+		m.players[0].fallStopwatch.Toggle(),
+		m.players[1].fallStopwatch.Toggle(),
+	)
+}
+
+func (m *VersusModel) View() string {
+	boards := make([]string, len(m.players))
+	for i, p := range m.players {
+		board, err := m.playerView(i, p)
+		if err != nil {
+			return "** FAILED TO BUILD MATRIX VIEW **"
+		}
+		boards[i] = board
+	}
+	output := lipgloss.JoinHorizontal(lipgloss.Top, boards[0], "  ", boards[1])
+
+	var err error
+	if m.game.IsGameOver() {
+		output, err = charmutils.OverlayCenter(output,
+			m.styles.Winner.Render(fmt.Sprintf(winnerMessage, m.game.GetWinner()+1)),
+			false)
+		if err != nil {
+			return "** FAILED TO OVERLAY WINNER MESSAGE **"
+		}
+	} else if m.isPaused {
+		output, err = charmutils.OverlayCenter(output,
+			lipgloss.NewStyle().Margin(0, 1).
+				Render(pausedMessage),
+			false)
+		if err != nil {
+			return "** FAILED TO OVERLAY PAUSED MESSAGE **"
+		}
+	}
+
+	output = lipgloss.JoinVertical(lipgloss.Left, output, m.help.View(m.keys))
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, output)
+}
+
+// playerView renders the board of the player with the garbage meter between the Hold and the Matrix.
+func (m *VersusModel) playerView(i int, p *versusPlayer) (string, error) {
+	game := p.player.Game()
+	matrixView, err := m.board.matrixView(game)
+	if err != nil {
+		return "", err
+	}
+	matrix := game.GetMatrix()
+
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		lipgloss.JoinVertical(lipgloss.Right, m.board.holdView(game), m.informationView(i, p)),
+		m.board.garbageMeterView(p.player.GetPendingGarbage(), matrix.GetSkyline()),
+		matrixView,
+		m.board.bagView(game),
+	), nil
+}
+
+func (m *VersusModel) informationView(i int, p *versusPlayer) string {
+	width := m.styles.Information.GetWidth()
+	headerStyle := lipgloss.NewStyle().Width(width).AlignHorizontal(lipgloss.Center).Bold(true).Underline(true)
+
+	var header string
+	switch {
+	case m.game.IsGameOver() && m.game.GetWinner() == i:
+		header = headerStyle.Render("WINNER")
+	case m.game.IsGameOver():
+		header = headerStyle.Render(strings.ToUpper(p.player.Game().GetGameOverReason().String()))
+	case m.isPaused:
+		header = headerStyle.Render("PAUSED")
+	default:
+		header = headerStyle.Render(fmt.Sprintf("PLAYER %d", i+1))
+	}
+
+	toFixedWidth := func(title, value string) string {
+		return fmt.Sprintf("%s%*s\n", title, width-(1+len(title)), value)
+	}
+
+	var output string
+	output += toFixedWidth("Sent:", strconv.Itoa(p.player.GetLinesSent()))
+	output += toFixedWidth("Combo:", strconv.Itoa(max(p.player.GetCombo(), 0)))
+	output += toFixedWidth("Lines:", strconv.Itoa(p.player.Game().GetLinesCleared()))
+	output += toFixedWidth("Level:", strconv.Itoa(p.player.Game().GetLevel()))
+
+	return m.styles.Information.Render(lipgloss.JoinVertical(lipgloss.Left, header, output))
+}
@@ -0,0 +1,32 @@
+package views
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/tui/components"
+)
+
+// versusKeyMap displays the help for both players of a versus game.
+type versusKeyMap struct {
+	players [2]*components.GameKeyMap
+}
+
+func (k *versusKeyMap) ShortHelp() []key.Binding {
+	return k.players[0].ShortHelp()
+}
+
+func (k *versusKeyMap) FullHelp() [][]key.Binding {
+	help := [][]key.Binding{
+		{
+			k.players[0].Exit,
+			k.players[0].Help,
+		},
+	}
+	for _, p := range k.players {
+		help = append(help,
+			[]key.Binding{p.Left, p.Right, p.Clockwise, p.CounterClockwise},
+			[]key.Binding{p.SoftDrop, p.HardDrop, p.Hold},
+		)
+	}
+	return help
+}
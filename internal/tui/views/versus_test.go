@@ -0,0 +1,63 @@
+package views
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Broderick-Westrope/x/exp/teatest"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Broderick-Westrope/tetrigo/internal/config"
+	"github.com/Broderick-Westrope/tetrigo/internal/tui"
+)
+
+func newTestVersusModel(t *testing.T) *VersusModel {
+	t.Helper()
+
+	m, err := NewVersusModel(
+		tui.NewVersusInput(1),
+		&config.Config{
+			NextQueueLength: 1,
+			GhostEnabled:    true,
+			LockDownMode:    "Extended",
+			Randomizer:      "7-bag",
+			Theme:           config.DefaultTheme(),
+			Keys:            config.DefaultKeys(),
+		},
+		WithVersusSeed([2]uint64{0, 0}),
+	)
+	require.NoError(t, err)
+	return m
+}
+
+func TestVersus_InitialOutput(t *testing.T) {
+	tm := teatest.NewTestModel(t, newTestVersusModel(t))
+
+	tm.Send(tea.Quit())
+	outBytes := []byte(tm.FinalModel(t).View())
+	teatest.RequireEqualOutput(t, outBytes)
+}
+
+func TestVersus_Winner(t *testing.T) {
+	m := newTestVersusModel(t)
+	hardDrop := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")}
+
+	// Player one hard drops until they block out.
+	for range 100 {
+		if m.game.IsGameOver() {
+			break
+		}
+		_, _ = m.Update(hardDrop)
+	}
+	require.True(t, m.game.IsGameOver())
+	assert.Equal(t, 1, m.game.GetWinner())
+	assert.True(t, m.players[0].player.Game().IsGameOver())
+	assert.False(t, m.players[1].player.Game().IsGameOver())
+	assert.True(t, strings.Contains(m.View(), "PLAYER 2 WINS!"))
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	require.NotNil(t, cmd)
+	assert.Equal(t, tui.SwitchModeMsg{Target: tui.ModeMenu, Input: tui.NewMenuInput()}, cmd())
+}
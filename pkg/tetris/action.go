@@ -22,6 +22,11 @@ const (
 	actionTSpinSingle
 	actionTSpinDouble
 	actionTSpinTriple
+	actionSinglePerfectClear
+	actionDoublePerfectClear
+	actionTriplePerfectClear
+	actionTetrisPerfectClear
+	actionBackToBackTetrisPerfectClear
 )
 
 var (
@@ -38,6 +43,12 @@ var (
 		actionTSpinSingle:     "TSpinSingle",
 		actionTSpinDouble:     "TSpinDouble",
 		actionTSpinTriple:     "TSpinTriple",
+
+		actionSinglePerfectClear:           "SinglePerfectClear",
+		actionDoublePerfectClear:           "DoublePerfectClear",
+		actionTriplePerfectClear:           "TriplePerfectClear",
+		actionTetrisPerfectClear:           "TetrisPerfectClear",
+		actionBackToBackTetrisPerfectClear: "BackToBackTetrisPerfectClear",
 	}
 
 	strToActionMap = map[string]action{
@@ -53,6 +64,12 @@ var (
 		"TSpinSingle":     actionTSpinSingle,
 		"TSpinDouble":     actionTSpinDouble,
 		"TSpinTriple":     actionTSpinTriple,
+
+		"SinglePerfectClear":           actionSinglePerfectClear,
+		"DoublePerfectClear":           actionDoublePerfectClear,
+		"TriplePerfectClear":           actionTriplePerfectClear,
+		"TetrisPerfectClear":           actionTetrisPerfectClear,
+		"BackToBackTetrisPerfectClear": actionBackToBackTetrisPerfectClear,
 	}
 
 	actionToPointsMap = map[action]int{
@@ -68,6 +85,13 @@ var (
 		actionTSpinSingle:     800,
 		actionTSpinDouble:     1200,
 		actionTSpinTriple:     1600,
+
+		// The points for a Perfect Clear are a bonus, awarded in addition to those of its line clear.
+		actionSinglePerfectClear:           800,
+		actionDoublePerfectClear:           1200,
+		actionTriplePerfectClear:           1800,
+		actionTetrisPerfectClear:           2000,
+		actionBackToBackTetrisPerfectClear: 3200,
 	}
 )
 
@@ -113,23 +137,62 @@ func (a action) GetPoints() int {
 	return actionToPointsMap[a]
 }
 
+// GetRowsCleared returns the number of rows removed from the Matrix by the Action.
+func (a action) GetRowsCleared() int {
+	switch a {
+	case actionSingle, actionMiniTSpinSingle, actionTSpinSingle, actionSinglePerfectClear:
+		return 1
+	case actionDouble, actionTSpinDouble, actionDoublePerfectClear:
+		return 2
+	case actionTriple, actionTSpinTriple, actionTriplePerfectClear:
+		return 3
+	case actionTetris, actionTetrisPerfectClear, actionBackToBackTetrisPerfectClear:
+		return 4
+	case actionUnknown, actionNone, actionMiniTSpin, actionTSpin:
+		return 0
+	default:
+		return 0
+	}
+}
+
+// IsPerfectClear returns true if the Action is the bonus for leaving the Matrix empty (see PerfectClearBonus).
+func (a action) IsPerfectClear() bool {
+	switch a {
+	case actionSinglePerfectClear, actionDoublePerfectClear, actionTriplePerfectClear,
+		actionTetrisPerfectClear, actionBackToBackTetrisPerfectClear:
+		return true
+	case actionUnknown, actionNone, actionSingle, actionDouble, actionTriple, actionTetris, actionMiniTSpin,
+		actionMiniTSpinSingle, actionTSpin, actionTSpinSingle, actionTSpinDouble, actionTSpinTriple:
+		return false
+	default:
+		return false
+	}
+}
+
+// EndsBackToBack returns true if the Action breaks a Back-to-Back sequence.
+// A Perfect Clear never breaks a Back-to-Back sequence.
 func (a action) EndsBackToBack() (bool, error) {
 	switch a {
 	case actionSingle, actionDouble, actionTriple:
 		return true, nil
 	case actionUnknown, actionNone, actionTetris, actionMiniTSpin, actionMiniTSpinSingle,
-		actionTSpin, actionTSpinSingle, actionTSpinDouble, actionTSpinTriple:
+		actionTSpin, actionTSpinSingle, actionTSpinDouble, actionTSpinTriple,
+		actionSinglePerfectClear, actionDoublePerfectClear, actionTriplePerfectClear,
+		actionTetrisPerfectClear, actionBackToBackTetrisPerfectClear:
 		return false, nil
 	default:
 		return false, fmt.Errorf("unknown action: %v", a)
 	}
 }
 
+// StartsBackToBack returns true if the Action starts or continues a Back-to-Back sequence.
 func (a action) StartsBackToBack() (bool, error) {
 	switch a {
-	case actionTetris, actionMiniTSpinSingle, actionTSpinSingle, actionTSpinDouble, actionTSpinTriple:
+	case actionTetris, actionMiniTSpinSingle, actionTSpinSingle, actionTSpinDouble, actionTSpinTriple,
+		actionTetrisPerfectClear, actionBackToBackTetrisPerfectClear:
 		return true, nil
-	case actionUnknown, actionNone, actionSingle, actionDouble, actionTriple, actionMiniTSpin, actionTSpin:
+	case actionUnknown, actionNone, actionSingle, actionDouble, actionTriple, actionMiniTSpin, actionTSpin,
+		actionSinglePerfectClear, actionDoublePerfectClear, actionTriplePerfectClear:
 		return false, nil
 	default:
 		return false, fmt.Errorf("unknown action: %v", a)
@@ -149,6 +212,12 @@ type ActionContainer struct {
 	TSpinSingle     Action
 	TSpinDouble     Action
 	TSpinTriple     Action
+
+	SinglePerfectClear           Action
+	DoublePerfectClear           Action
+	TriplePerfectClear           Action
+	TetrisPerfectClear           Action
+	BackToBackTetrisPerfectClear Action
 }
 
 // Actions is a global instance of ActionContainer that contains all possible values of type Action.
@@ -165,4 +234,10 @@ var Actions = ActionContainer{
 	TSpinSingle:     Action{actionTSpinSingle},
 	TSpinDouble:     Action{actionTSpinDouble},
 	TSpinTriple:     Action{actionTSpinTriple},
+
+	SinglePerfectClear:           Action{actionSinglePerfectClear},
+	DoublePerfectClear:           Action{actionDoublePerfectClear},
+	TriplePerfectClear:           Action{actionTriplePerfectClear},
+	TetrisPerfectClear:           Action{actionTetrisPerfectClear},
+	BackToBackTetrisPerfectClear: Action{actionBackToBackTetrisPerfectClear},
 }
@@ -0,0 +1,166 @@
+// Package bot provides computer players which decide where to place Tetriminos.
+package bot
+
+import (
+	"math"
+
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris"
+)
+
+// Bot decides the Moves to perform for the Tetrimino in play.
+type Bot interface {
+	// Plan returns the Moves to perform with the Tetrimino in play. The sequence ends with either
+	// MoveHardDrop or MoveHold, after which Plan should be called again for the new Tetrimino in play.
+	Plan(state State) ([]Move, error)
+}
+
+// State is the information about a game which is available to a Bot.
+type State struct {
+	// Matrix is the Matrix without the Tetrimino in play.
+	Matrix tetris.Matrix
+	// Current is the Tetrimino in play.
+	Current *tetris.Tetrimino
+	// Hold is the Value of the held Tetrimino, or 0 if nothing is held.
+	Hold byte
+	// CanHold is whether the Tetrimino in play can be held.
+	CanHold bool
+	// Next are the Values of the upcoming Tetriminos, in order.
+	Next []byte
+}
+
+// Search is a Bot which evaluates every reachable Placement of the Tetrimino in play using a Heuristic.
+// It can look ahead at the Placements of upcoming Tetriminos and consider holding.
+type Search struct {
+	heuristic Heuristic
+	previews  int
+	useHold   bool
+}
+
+var _ Bot = &Search{}
+
+// NewSearch creates a Search using the DefaultHeuristic, a single preview, and holding enabled.
+func NewSearch(opts ...func(*Search)) *Search {
+	s := &Search{
+		heuristic: DefaultHeuristic(),
+		previews:  1,
+		useHold:   true,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// WithHeuristic sets the Heuristic used to score Placements.
+func WithHeuristic(h Heuristic) func(*Search) {
+	return func(s *Search) {
+		s.heuristic = h
+	}
+}
+
+// WithPreviews sets the number of upcoming Tetriminos to look ahead at. Each preview multiplies the
+// number of Placements evaluated, so values above 1 are slow.
+func WithPreviews(n int) func(*Search) {
+	return func(s *Search) {
+		s.previews = max(n, 0)
+	}
+}
+
+// WithHold sets whether holding is considered.
+func WithHold(enabled bool) func(*Search) {
+	return func(s *Search) {
+		s.useHold = enabled
+	}
+}
+
+// Plan returns the Moves to reach the best scoring Placement of the Tetrimino in play.
+// If holding would lead to a better score then only MoveHold is returned.
+func (s *Search) Plan(state State) ([]Move, error) {
+	best, score, found, err := s.evaluate(state.Matrix, state.Current, state.Next, s.previews, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.useHold && state.CanHold {
+		held, next := state.Hold, state.Next
+		if held == 0 && len(next) > 0 {
+			held, next = next[0], next[1:]
+		}
+
+		if held != 0 {
+			holdScore, ok, err := s.evaluateSpawn(state.Matrix, held, next, s.previews, 0)
+			if err != nil {
+				return nil, err
+			}
+			if ok && (!found || holdScore > score) {
+				return []Move{MoveHold}, nil
+			}
+		}
+	}
+
+	if !found {
+		return []Move{MoveHardDrop}, nil
+	}
+	return best.Moves, nil
+}
+
+// evaluate finds the best Placement of the Tetrimino, looking ahead at the given number of upcoming
+// Tetriminos. The lines cleared by earlier lock downs are included when scoring the final Matrix.
+// If there are no Placements false is returned.
+func (s *Search) evaluate(
+	matrix tetris.Matrix, tet *tetris.Tetrimino, next []byte, previews, lines int,
+) (Placement, float64, bool, error) {
+	placements, err := Placements(matrix, tet)
+	if err != nil {
+		return Placement{}, 0, false, err
+	}
+
+	var best Placement
+	bestScore := math.Inf(-1)
+	found := false
+	for _, p := range placements {
+		result := matrix.DeepCopy()
+		if err = result.AddTetrimino(p.Tetrimino); err != nil {
+			return Placement{}, 0, false, err
+		}
+		cleared := lines + result.RemoveCompletedLines(p.Tetrimino).GetRowsCleared()
+
+		var score float64
+		if previews > 0 && len(next) > 0 {
+			var ok bool
+			score, ok, err = s.evaluateSpawn(*result, next[0], next[1:], previews-1, cleared)
+			if err != nil {
+				return Placement{}, 0, false, err
+			}
+			if !ok {
+				score = math.Inf(-1)
+			}
+		} else {
+			score = s.heuristic.Score(*result, cleared)
+		}
+
+		if !found || score > bestScore {
+			best, bestScore, found = p, score, true
+		}
+	}
+	return best, bestScore, found, nil
+}
+
+// evaluateSpawn returns the score of the best Placement of the Tetrimino with the given value once it
+// has spawned. If the Tetrimino cannot spawn (ie. the game would be over) false is returned.
+func (s *Search) evaluateSpawn(matrix tetris.Matrix, value byte, next []byte, previews, lines int) (float64, bool, error) {
+	tet, err := tetris.GetTetrimino(value)
+	if err != nil {
+		return 0, false, err
+	}
+	tet.Position = matrix.GetStartingPosition(tet)
+	if !tet.IsValid(matrix, false) {
+		return 0, false, nil
+	}
+	tet.MoveDown(matrix)
+
+	_, score, ok, err := s.evaluate(matrix, tet, next, previews, lines)
+	return score, ok, err
+}
@@ -0,0 +1,158 @@
+package bot
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris"
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris/modes/single"
+)
+
+func spawnTetrimino(t *testing.T, matrix tetris.Matrix, value byte) *tetris.Tetrimino {
+	t.Helper()
+
+	tet, err := tetris.GetTetrimino(value)
+	require.NoError(t, err)
+	tet.Position.Y += matrix.GetSkyline()
+	tet.MoveDown(matrix)
+	return tet
+}
+
+func TestPlacements(t *testing.T) {
+	tt := map[byte]int{
+		'I': 17,
+		'O': 9,
+		'T': 34,
+		'S': 17,
+		'Z': 17,
+		'J': 34,
+		'L': 34,
+	}
+
+	for value, want := range tt {
+		t.Run(string(value), func(t *testing.T) {
+			matrix := tetris.DefaultMatrix()
+
+			placements, err := Placements(matrix, spawnTetrimino(t, matrix, value))
+			require.NoError(t, err)
+			assert.Len(t, placements, want)
+
+			for _, p := range placements {
+				assert.Equal(t, MoveHardDrop, p.Moves[len(p.Moves)-1])
+				assert.NotContains(t, p.Moves, MoveDown)
+			}
+		})
+	}
+}
+
+func TestPlacements_Tuck(t *testing.T) {
+	matrix := tetris.DefaultMatrix()
+	bottom := matrix.GetHeight() - 1
+
+	// A roof over the bottom left corner which can only be reached by moving down then left.
+	for col := 4; col < 10; col++ {
+		matrix[bottom-1][col] = 'X'
+		matrix[bottom][col] = 'X'
+	}
+	matrix[bottom-2][0], matrix[bottom-2][1] = 'X', 'X'
+
+	placements, err := Placements(matrix, spawnTetrimino(t, matrix, 'O'))
+	require.NoError(t, err)
+
+	var tuck *Placement
+	for i, p := range placements {
+		if p.Tetrimino.Position == (tetris.Coordinate{X: 0, Y: bottom - 1}) {
+			tuck = &placements[i]
+		}
+	}
+	require.NotNil(t, tuck, "expected the tucked placement to be found")
+	assert.Contains(t, tuck.Moves, MoveDown)
+	assert.Equal(t, MoveLeft, tuck.Moves[len(tuck.Moves)-2])
+}
+
+func TestHeuristicFeatures(t *testing.T) {
+	matrix := tetris.Matrix{
+		{0, 0, 0, 0},
+		{0, 'X', 0, 0},
+		{'X', 0, 0, 'X'},
+		{'X', 'X', 0, 'X'},
+	}
+
+	heights := ColumnHeights(matrix)
+	assert.Equal(t, []int{2, 3, 0, 2}, heights)
+	assert.Equal(t, 7, AggregateHeight(heights))
+	assert.Equal(t, 1+3+2, Bumpiness(heights))
+	assert.Equal(t, 1, Holes(matrix))
+}
+
+// newWellMatrix returns a Matrix with four full lines except for a well in the rightmost column.
+func newWellMatrix() tetris.Matrix {
+	matrix := tetris.DefaultMatrix()
+	for row := matrix.GetHeight() - 4; row < matrix.GetHeight(); row++ {
+		for col := 0; col < 9; col++ {
+			matrix[row][col] = 'X'
+		}
+	}
+	return matrix
+}
+
+func TestSearch_Plan_Hold(t *testing.T) {
+	matrix := newWellMatrix()
+
+	got, err := NewSearch(WithPreviews(0)).Plan(State{
+		Matrix:  matrix,
+		Current: spawnTetrimino(t, matrix, 'S'),
+		Hold:    'I',
+		CanHold: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []Move{MoveHold}, got)
+}
+
+func TestSearch_Plan_Tetris(t *testing.T) {
+	matrix := newWellMatrix()
+	tet := spawnTetrimino(t, matrix, 'I')
+
+	moves, err := NewSearch().Plan(State{
+		Matrix:  matrix,
+		Current: tet,
+		CanHold: true,
+		Next:    []byte{'S'},
+	})
+	require.NoError(t, err)
+	require.Equal(t, MoveHardDrop, moves[len(moves)-1])
+
+	for _, m := range moves[:len(moves)-1] {
+		_, err = applyToTetrimino(matrix, tet, m)
+		require.NoError(t, err)
+	}
+	for tet.MoveDown(matrix) {
+	}
+	require.NoError(t, matrix.AddTetrimino(tet))
+	assert.Equal(t, tetris.Actions.Tetris, matrix.RemoveCompletedLines(tet))
+}
+
+func TestSearch_PlaysGame(t *testing.T) {
+	game, err := single.NewGame(&single.Input{
+		Level: 1,
+		Rand:  rand.New(rand.NewPCG(0, 0)),
+	})
+	require.NoError(t, err)
+
+	b := NewSearch(WithPreviews(0))
+	for range 100 {
+		moves, err := b.Plan(StateFromGame(game))
+		require.NoError(t, err)
+
+		for _, m := range moves {
+			gameOver, err := Apply(game, m)
+			require.NoError(t, err)
+			require.False(t, gameOver)
+		}
+	}
+
+	assert.Positive(t, game.GetLinesCleared())
+}
@@ -0,0 +1,49 @@
+package bot
+
+import (
+	"fmt"
+
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris/modes/single"
+)
+
+// StateFromGame creates the State of the given single player game.
+func StateFromGame(g *single.Game) State {
+	bag := g.GetBagTetriminos()
+	next := make([]byte, len(bag))
+	for i := range bag {
+		next[i] = bag[i].Value
+	}
+
+	return State{
+		Matrix:  g.GetMatrix(),
+		Current: g.GetTetriminoInPlay(),
+		Hold:    g.GetHoldTetrimino().Value,
+		CanHold: g.CanHold(),
+		Next:    next,
+	}
+}
+
+// Apply performs the Move on the given single player game. If true is returned the game is over.
+// MoveDown is performed as a gravity tick, so it will not lock down the Tetrimino unless the
+// Lock Down timer has expired.
+func Apply(g *single.Game, m Move) (bool, error) {
+	switch m {
+	case MoveLeft:
+		g.MoveLeft()
+	case MoveRight:
+		g.MoveRight()
+	case MoveRotateClockwise:
+		return false, g.Rotate(true)
+	case MoveRotateCounterClockwise:
+		return false, g.Rotate(false)
+	case MoveDown:
+		return g.TickLower()
+	case MoveHardDrop:
+		return g.HardDrop()
+	case MoveHold:
+		return g.Hold()
+	default:
+		return false, fmt.Errorf("unknown move %d", m)
+	}
+	return false, nil
+}
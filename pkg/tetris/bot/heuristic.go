@@ -0,0 +1,105 @@
+package bot
+
+import (
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris"
+)
+
+// Heuristic scores a Matrix after a Tetrimino has been locked down. Higher scores are better.
+type Heuristic interface {
+	Score(matrix tetris.Matrix, linesCleared int) float64
+}
+
+// WeightedHeuristic is a Heuristic which scores a Matrix using a weighted sum of its features.
+type WeightedHeuristic struct {
+	// AggregateHeight is multiplied by the sum of the heights of every column.
+	AggregateHeight float64
+	// LinesCleared is multiplied by the number of lines cleared by the lock down.
+	LinesCleared float64
+	// Holes is multiplied by the number of empty cells which have a Mino above them in the same column.
+	Holes float64
+	// Bumpiness is multiplied by the sum of the absolute height differences between adjacent columns.
+	Bumpiness float64
+}
+
+// DefaultHeuristic returns a WeightedHeuristic with weights that have been tuned for line clearing.
+func DefaultHeuristic() WeightedHeuristic {
+	return WeightedHeuristic{
+		AggregateHeight: -0.510066,
+		LinesCleared:    0.760666,
+		Holes:           -0.35663,
+		Bumpiness:       -0.184483,
+	}
+}
+
+// Score returns the weighted sum of the features of the Matrix.
+func (h WeightedHeuristic) Score(matrix tetris.Matrix, linesCleared int) float64 {
+	heights := ColumnHeights(matrix)
+	return h.AggregateHeight*float64(AggregateHeight(heights)) +
+		h.LinesCleared*float64(linesCleared) +
+		h.Holes*float64(Holes(matrix)) +
+		h.Bumpiness*float64(Bumpiness(heights))
+}
+
+// ColumnHeights returns the height of the highest Mino in each column of the Matrix.
+func ColumnHeights(matrix tetris.Matrix) []int {
+	if len(matrix) == 0 {
+		return nil
+	}
+
+	heights := make([]int, len(matrix[0]))
+	for col := range heights {
+		for row := range matrix {
+			if matrix[row][col] != 0 {
+				heights[col] = len(matrix) - row
+				break
+			}
+		}
+	}
+	return heights
+}
+
+// AggregateHeight returns the sum of the column heights.
+func AggregateHeight(heights []int) int {
+	total := 0
+	for _, h := range heights {
+		total += h
+	}
+	return total
+}
+
+// Bumpiness returns the sum of the absolute differences between the heights of adjacent columns.
+func Bumpiness(heights []int) int {
+	total := 0
+	for i := 1; i < len(heights); i++ {
+		total += abs(heights[i] - heights[i-1])
+	}
+	return total
+}
+
+// Holes returns the number of empty cells which have a Mino somewhere above them in the same column.
+func Holes(matrix tetris.Matrix) int {
+	if len(matrix) == 0 {
+		return 0
+	}
+
+	holes := 0
+	for col := range matrix[0] {
+		covered := false
+		for row := range matrix {
+			switch {
+			case matrix[row][col] != 0:
+				covered = true
+			case covered:
+				holes++
+			}
+		}
+	}
+	return holes
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,29 @@
+package bot
+
+// Move is a single input performed on the Tetrimino in play.
+type Move int
+
+const (
+	MoveLeft Move = iota
+	MoveRight
+	MoveRotateClockwise
+	MoveRotateCounterClockwise
+	// MoveDown moves the Tetrimino down a single row. This is used to reach tucks and spins below overhangs.
+	MoveDown
+	MoveHardDrop
+	MoveHold
+)
+
+var moveToStrMap = map[Move]string{
+	MoveLeft:                   "Left",
+	MoveRight:                  "Right",
+	MoveRotateClockwise:        "RotateClockwise",
+	MoveRotateCounterClockwise: "RotateCounterClockwise",
+	MoveDown:                   "Down",
+	MoveHardDrop:               "HardDrop",
+	MoveHold:                   "Hold",
+}
+
+func (m Move) String() string {
+	return moveToStrMap[m]
+}
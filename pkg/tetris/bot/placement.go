@@ -0,0 +1,163 @@
+package bot
+
+import (
+	"fmt"
+
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris"
+)
+
+// Placement is a final position in which a Tetrimino can be locked down, along with the Moves to reach it.
+type Placement struct {
+	// Tetrimino is the Tetrimino in its final position.
+	Tetrimino *tetris.Tetrimino
+	// Moves are the inputs which take the Tetrimino from its starting position to the Placement.
+	// This always ends with MoveHardDrop.
+	Moves []Move
+}
+
+var (
+	// dropMoves are the Moves used to explore the positions a Tetrimino can reach before it is hard dropped.
+	dropMoves = []Move{MoveLeft, MoveRight, MoveRotateClockwise, MoveRotateCounterClockwise}
+	// searchMoves are the Moves used to explore every position a Tetrimino can reach.
+	searchMoves = append(dropMoves, MoveDown)
+)
+
+type positionKey struct {
+	x, y, direction int
+}
+
+func keyOf(tet *tetris.Tetrimino) positionKey {
+	return positionKey{x: tet.Position.X, y: tet.Position.Y, direction: tet.CompassDirection}
+}
+
+// minosKey is the position of each Mino of a Tetrimino, in row-major order. This is used to identify
+// Placements which occupy the same cells in different orientations (eg. the I, S and Z Tetriminos).
+type minosKey [4]tetris.Coordinate
+
+func minosOf(tet *tetris.Tetrimino) minosKey {
+	var key minosKey
+	i := 0
+	for row := range tet.Cells {
+		for col := range tet.Cells[row] {
+			if !tet.Cells[row][col] || i >= len(key) {
+				continue
+			}
+			key[i] = tetris.Coordinate{X: tet.Position.X + col, Y: tet.Position.Y + row}
+			i++
+		}
+	}
+	return key
+}
+
+// Placements finds every Placement reachable by the given Tetrimino using movement, rotation
+// (including SRS kicks) and moving down. Placements which can be reached by hard dropping without
+// first moving down are preferred, then each remaining Placement (eg. tucks and spins) uses the shortest
+// sequence of Moves which reaches it. Placements which occupy the same cells are only included once.
+func Placements(matrix tetris.Matrix, tet *tetris.Tetrimino) ([]Placement, error) {
+	found := make(map[minosKey]bool)
+	var placements []Placement
+	add := func(final *tetris.Tetrimino, moves []Move) {
+		key := minosOf(final)
+		if found[key] {
+			return
+		}
+		found[key] = true
+		placements = append(placements, Placement{Tetrimino: final, Moves: withHardDrop(moves)})
+	}
+
+	err := search(matrix, tet, dropMoves, func(t *tetris.Tetrimino, moves []Move) {
+		for t.MoveDown(matrix) {
+		}
+		add(t, moves)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = search(matrix, tet, searchMoves, func(t *tetris.Tetrimino, moves []Move) {
+		if !t.DeepCopy().MoveDown(matrix) {
+			add(t, moves)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return placements, nil
+}
+
+// search performs a breadth-first search of the positions the Tetrimino can reach using the given Moves.
+// The visit function is called with a copy of the Tetrimino at each position, along with the shortest
+// sequence of Moves which reaches it.
+func search(matrix tetris.Matrix, tet *tetris.Tetrimino, searchMoves []Move, visit func(*tetris.Tetrimino, []Move)) error {
+	type node struct {
+		tet   *tetris.Tetrimino
+		moves []Move
+	}
+
+	visited := map[positionKey]bool{keyOf(tet): true}
+	queue := []node{{tet: tet.DeepCopy()}}
+
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+
+		for _, move := range searchMoves {
+			next := n.tet.DeepCopy()
+			moved, err := applyToTetrimino(matrix, next, move)
+			if err != nil {
+				return err
+			}
+			if !moved {
+				continue
+			}
+
+			key := keyOf(next)
+			if visited[key] {
+				continue
+			}
+			visited[key] = true
+
+			moves := make([]Move, len(n.moves), len(n.moves)+1)
+			copy(moves, n.moves)
+			queue = append(queue, node{tet: next, moves: append(moves, move)})
+		}
+
+		visit(n.tet.DeepCopy(), n.moves)
+	}
+	return nil
+}
+
+// applyToTetrimino performs a Move on the Tetrimino, returning true if it changed position.
+func applyToTetrimino(matrix tetris.Matrix, tet *tetris.Tetrimino, move Move) (bool, error) {
+	switch move {
+	case MoveLeft:
+		return tet.MoveLeft(matrix), nil
+	case MoveRight:
+		return tet.MoveRight(matrix), nil
+	case MoveDown:
+		return tet.MoveDown(matrix), nil
+	case MoveRotateClockwise, MoveRotateCounterClockwise:
+		rotationPoint, err := tet.RotateWithPoint(matrix, move == MoveRotateClockwise)
+		if err != nil {
+			return false, err
+		}
+		return rotationPoint > 0, nil
+	case MoveHardDrop, MoveHold:
+		return false, fmt.Errorf("move %q cannot be used when searching", move)
+	default:
+		return false, fmt.Errorf("unknown move %d", move)
+	}
+}
+
+// withHardDrop replaces any trailing MoveDown with a single MoveHardDrop.
+func withHardDrop(moves []Move) []Move {
+	end := len(moves)
+	for end > 0 && moves[end-1] == MoveDown {
+		end--
+	}
+
+	result := make([]Move, end, end+1)
+	copy(result, moves[:end])
+	return append(result, MoveHardDrop)
+}
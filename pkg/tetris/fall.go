@@ -5,25 +5,92 @@ import (
 	"time"
 )
 
+// DefaultSoftDropFactor is the number of times faster a Tetrimino falls whilst soft dropping.
+const DefaultSoftDropFactor = 15
+
 type Fall struct {
 	DefaultInterval  time.Duration
 	SoftDropInterval time.Duration
 	IsSoftDrop       bool
+	// IsInstant is whether the gravity is 20G, where the Tetrimino in play immediately drops to the lowest
+	// position it can reach (including when it spawns).
+	IsInstant bool
+
+	softDropFactor int
+	master         bool
 }
 
-func NewFall(level int) *Fall {
-	f := Fall{}
+func NewFall(level int, opts ...func(*Fall)) *Fall {
+	f := Fall{
+		softDropFactor: DefaultSoftDropFactor,
+	}
+
+	for _, opt := range opts {
+		opt(&f)
+	}
+
 	f.CalculateFallSpeeds(level)
 	return &f
 }
 
+// WithSoftDropFactor sets how many times faster a Tetrimino falls whilst soft dropping.
+// A factor less than 1 is ignored.
+func WithSoftDropFactor(factor int) func(*Fall) {
+	return func(f *Fall) {
+		if factor >= 1 {
+			f.softDropFactor = factor
+		}
+	}
+}
+
+// WithMasterGravity makes the fall speed follow the gravity of Master (see MasterGravity) rather than
+// the guideline curve. The level passed to CalculateFallSpeeds is then the Master level.
+func WithMasterGravity() func(*Fall) {
+	return func(f *Fall) {
+		f.master = true
+	}
+}
+
 func (f *Fall) CalculateFallSpeeds(level int) {
+	if f.master {
+		f.calculateMasterFallSpeeds(level)
+		return
+	}
+
 	decrementedLevel := float64(level - 1)
 	speed := math.Pow(0.8-(decrementedLevel*0.007), decrementedLevel)
 	speed *= float64(time.Second)
 
+	factor := f.softDropFactor
+	if factor < 1 {
+		factor = DefaultSoftDropFactor
+	}
+
 	f.DefaultInterval = time.Duration(speed)
-	f.SoftDropInterval = time.Duration(speed / 15)
+	f.SoftDropInterval = time.Duration(speed / float64(factor))
+}
+
+// calculateMasterFallSpeeds sets the intervals for the gravity of Master at the given level.
+// At 20G the Tetrimino does not fall at an interval, so the default interval is a single frame.
+func (f *Fall) calculateMasterFallSpeeds(level int) {
+	gravity := MasterGravity(level)
+	f.IsInstant = gravity >= InstantGravity
+
+	factor := f.softDropFactor
+	if factor < 1 {
+		factor = DefaultSoftDropFactor
+	}
+
+	f.DefaultInterval = Frame
+	if !f.IsInstant {
+		f.DefaultInterval = Frame * 256 / time.Duration(gravity)
+	}
+	f.SoftDropInterval = f.DefaultInterval / time.Duration(factor)
+}
+
+// SoftDropFactor returns how many times faster a Tetrimino falls whilst soft dropping.
+func (f *Fall) SoftDropFactor() int {
+	return f.softDropFactor
 }
 
 func (f *Fall) ToggleSoftDrop() {
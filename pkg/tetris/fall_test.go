@@ -101,3 +101,48 @@ func TestFall_SpeedProgression(t *testing.T) {
 		prevInterval = f.DefaultInterval
 	}
 }
+
+func TestNewFall_WithSoftDropFactor(t *testing.T) {
+	tt := map[string]struct {
+		factor     int
+		wantFactor int
+	}{
+		"custom":  {factor: 20, wantFactor: 20},
+		"minimum": {factor: 1, wantFactor: 1},
+		"zero":    {factor: 0, wantFactor: DefaultSoftDropFactor},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			f := NewFall(1, WithSoftDropFactor(tc.factor))
+
+			expected := time.Duration(float64(calculateExpectedSpeed(1)) / float64(tc.wantFactor))
+			assert.Equal(t, expected, f.SoftDropInterval)
+		})
+	}
+}
+
+func TestNewFall_WithMasterGravity(t *testing.T) {
+	tt := map[string]struct {
+		level        int
+		wantInterval time.Duration
+		wantInstant  bool
+	}{
+		"level 0":   {level: 0, wantInterval: 64 * Frame},
+		"level 200": {level: 200, wantInterval: 64 * Frame},
+		"level 251": {level: 251, wantInterval: Frame},
+		"level 300": {level: 300, wantInterval: Frame / 2},
+		"level 500": {level: 500, wantInterval: Frame, wantInstant: true},
+		"level 999": {level: 999, wantInterval: Frame, wantInstant: true},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			f := NewFall(tc.level, WithMasterGravity())
+
+			assert.Equal(t, tc.wantInterval, f.DefaultInterval)
+			assert.Equal(t, tc.wantInterval/DefaultSoftDropFactor, f.SoftDropInterval)
+			assert.Equal(t, tc.wantInstant, f.IsInstant)
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package tetris
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+)
+
+// GarbageCell is the value of the Minos in garbage lines.
+const GarbageCell byte = 'X'
+
+// actionToGarbageLinesMap is the number of garbage lines sent to an opponent for each Action.
+var actionToGarbageLinesMap = map[action]int{
+	actionUnknown:         0,
+	actionNone:            0,
+	actionSingle:          0,
+	actionDouble:          1,
+	actionTriple:          2,
+	actionTetris:          4,
+	actionMiniTSpin:       0,
+	actionMiniTSpinSingle: 0,
+	actionTSpin:           0,
+	actionTSpinSingle:     2,
+	actionTSpinDouble:     4,
+	actionTSpinTriple:     6,
+
+	actionSinglePerfectClear:           10,
+	actionDoublePerfectClear:           10,
+	actionTriplePerfectClear:           10,
+	actionTetrisPerfectClear:           10,
+	actionBackToBackTetrisPerfectClear: 10,
+}
+
+// comboGarbageLines is the number of extra garbage lines sent for each combo count.
+// Combos greater than the length of the table use the last value.
+var comboGarbageLines = []int{0, 0, 1, 1, 2, 2, 3, 3, 4, 4, 4, 5}
+
+// GetGarbageLines returns the number of garbage lines sent to an opponent by the Action.
+func (a action) GetGarbageLines() int {
+	return actionToGarbageLinesMap[a]
+}
+
+// GarbageLines returns the number of garbage lines sent to an opponent by a lock down with the given Action.
+// A Back-to-Back Action sends one extra line. The combo is the number of consecutive lock downs which
+// cleared lines before this one, where 0 means this is the first.
+func GarbageLines(a Action, backToBack bool, combo int) int {
+	if a.GetRowsCleared() == 0 {
+		return 0
+	}
+
+	lines := a.GetGarbageLines()
+	if backToBack {
+		lines++
+	}
+	if combo > 0 {
+		lines += comboGarbageLines[min(combo, len(comboGarbageLines)-1)]
+	}
+	return lines
+}
+
+// AddGarbage pushes the contents of the Matrix up and inserts the given number of garbage lines at the bottom.
+// Each garbage line is filled except for the hole column.
+// If any Minos are pushed out of the top of the Matrix true is returned (ie. Top Out).
+func (m *Matrix) AddGarbage(lines, hole int) (bool, error) {
+	if m.isOutOfBoundsHorizontally(hole) {
+		return false, fmt.Errorf("garbage hole column %d is out of bounds", hole)
+	}
+	lines = min(lines, len(*m))
+
+	toppedOut := false
+	for row := range lines {
+		if slices.ContainsFunc((*m)[row], func(cell byte) bool { return !isCellEmpty(cell) }) {
+			toppedOut = true
+		}
+	}
+
+	copy(*m, (*m)[lines:])
+	for row := len(*m) - lines; row < len(*m); row++ {
+		garbage := make([]byte, m.GetWidth())
+		for col := range garbage {
+			if col != hole {
+				garbage[col] = GarbageCell
+			}
+		}
+		(*m)[row] = garbage
+	}
+	return toppedOut, nil
+}
+
+// AddRandomGarbage pushes the contents of the Matrix up and inserts the given number of garbage lines at the bottom,
+// each with a hole in a random column (see RandomGarbageHole). If any Minos are pushed out of the top of the Matrix
+// true is returned (ie. Top Out).
+func (m *Matrix) AddRandomGarbage(lines int, rng *rand.Rand) (bool, error) {
+	toppedOut := false
+	for range lines {
+		out, err := m.AddGarbage(1, RandomGarbageHole(rng, m.GetWidth(), m.GetGarbageHole(len(*m)-1)))
+		if err != nil {
+			return false, err
+		}
+		toppedOut = toppedOut || out
+	}
+	return toppedOut, nil
+}
+
+// RandomGarbageHole returns a random hole column for a garbage line in a Matrix of the given width.
+// The hole is never in the prev column, which is the hole of the garbage line below it (or -1 if there is none),
+// so that each garbage line has to be cleared separately. A Matrix with a single column has no other column for the
+// hole to be in, so it is always 0.
+func RandomGarbageHole(rng *rand.Rand, width, prev int) int {
+	if width <= 1 {
+		return 0
+	}
+	if prev < 0 || prev >= width {
+		return rng.IntN(width)
+	}
+	hole := rng.IntN(width - 1)
+	if hole >= prev {
+		hole++
+	}
+	return hole
+}
+
+// GetGarbageHole returns the column of the first empty cell in the given row if it is a garbage line, or -1 otherwise.
+// A garbage line is a row containing at least one Mino from garbage.
+func (m *Matrix) GetGarbageHole(row int) int {
+	if !slices.Contains((*m)[row], GarbageCell) {
+		return -1
+	}
+	return slices.IndexFunc((*m)[row], isCellEmpty)
+}
+
+// CountGarbageLines returns the number of rows containing at least one Mino from garbage.
+func (m *Matrix) CountGarbageLines() int {
+	var lines int
+	for _, row := range *m {
+		if slices.Contains(row, GarbageCell) {
+			lines++
+		}
+	}
+	return lines
+}
@@ -0,0 +1,194 @@
+package tetris
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGarbageLines(t *testing.T) {
+	tt := map[string]struct {
+		action     Action
+		backToBack bool
+		combo      int
+		want       int
+	}{
+		"none":                        {action: Actions.None, want: 0},
+		"none with combo":             {action: Actions.None, combo: 5, want: 0},
+		"single":                      {action: Actions.Single, want: 0},
+		"double":                      {action: Actions.Double, want: 1},
+		"triple":                      {action: Actions.Triple, want: 2},
+		"tetris":                      {action: Actions.Tetris, want: 4},
+		"tetris back-to-back":         {action: Actions.Tetris, backToBack: true, want: 5},
+		"mini t-spin":                 {action: Actions.MiniTSpin, want: 0},
+		"mini t-spin single":          {action: Actions.MiniTSpinSingle, want: 0},
+		"t-spin single":               {action: Actions.TSpinSingle, want: 2},
+		"t-spin double":               {action: Actions.TSpinDouble, want: 4},
+		"t-spin triple":               {action: Actions.TSpinTriple, want: 6},
+		"t-spin double back-to-back":  {action: Actions.TSpinDouble, backToBack: true, want: 5},
+		"single combo 1":              {action: Actions.Single, combo: 1, want: 0},
+		"single combo 2":              {action: Actions.Single, combo: 2, want: 1},
+		"double combo 5":              {action: Actions.Double, combo: 5, want: 3},
+		"single combo beyond table":   {action: Actions.Single, combo: 20, want: 5},
+		"tetris back-to-back combo 4": {action: Actions.Tetris, backToBack: true, combo: 4, want: 7},
+		"single perfect clear":        {action: Actions.SinglePerfectClear, want: 10},
+		"tetris perfect clear":        {action: Actions.TetrisPerfectClear, want: 10},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, GarbageLines(tc.action, tc.backToBack, tc.combo))
+		})
+	}
+}
+
+func TestMatrix_AddGarbage(t *testing.T) {
+	tt := map[string]struct {
+		matrix        Matrix
+		lines         int
+		hole          int
+		want          Matrix
+		wantToppedOut bool
+		wantErr       bool
+	}{
+		"empty matrix": {
+			matrix: Matrix{
+				{0, 0, 0},
+				{0, 0, 0},
+				{0, 0, 0},
+			},
+			lines: 2,
+			hole:  1,
+			want: Matrix{
+				{0, 0, 0},
+				{'X', 0, 'X'},
+				{'X', 0, 'X'},
+			},
+		},
+		"pushes minos up": {
+			matrix: Matrix{
+				{0, 0, 0},
+				{0, 0, 0},
+				{'T', 'T', 0},
+			},
+			lines: 1,
+			hole:  2,
+			want: Matrix{
+				{0, 0, 0},
+				{'T', 'T', 0},
+				{'X', 'X', 0},
+			},
+		},
+		"top out": {
+			matrix: Matrix{
+				{0, 0, 0},
+				{'I', 0, 0},
+				{'I', 0, 0},
+			},
+			lines: 2,
+			hole:  0,
+			want: Matrix{
+				{'I', 0, 0},
+				{0, 'X', 'X'},
+				{0, 'X', 'X'},
+			},
+			wantToppedOut: true,
+		},
+		"ghost is not topped out": {
+			matrix: Matrix{
+				{'G', 0, 0},
+				{0, 0, 0},
+				{0, 0, 0},
+			},
+			lines: 1,
+			hole:  0,
+			want: Matrix{
+				{0, 0, 0},
+				{0, 0, 0},
+				{0, 'X', 'X'},
+			},
+		},
+		"hole out of bounds": {
+			matrix:  Matrix{{0, 0, 0}},
+			lines:   1,
+			hole:    3,
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			toppedOut, err := tc.matrix.AddGarbage(tc.lines, tc.hole)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantToppedOut, toppedOut)
+			assert.Equal(t, tc.want, tc.matrix)
+		})
+	}
+}
+
+func TestMatrix_AddRandomGarbage(t *testing.T) {
+	matrix, err := NewMatrix(8, 4)
+	require.NoError(t, err)
+	matrix[3] = []byte{'T', 'T', 'T', 0}
+	//nolint:gosec // This random source is not for any security-related tasks.
+	rng := rand.New(rand.NewPCG(1, 2))
+
+	toppedOut, err := matrix.AddRandomGarbage(3, rng)
+	require.NoError(t, err)
+	assert.False(t, toppedOut)
+	assert.Equal(t, []byte{'T', 'T', 'T', 0}, matrix[0])
+	assert.Equal(t, 3, matrix.CountGarbageLines())
+
+	// Each garbage line has one hole, which is never in the same column as the line below it.
+	for row := 5; row < matrix.GetHeight(); row++ {
+		hole := matrix.GetGarbageHole(row)
+		require.NotEqual(t, -1, hole)
+		assert.Equal(t, 1, bytes.Count(matrix[row], []byte{0}))
+		if row < matrix.GetHeight()-1 {
+			assert.NotEqual(t, matrix.GetGarbageHole(row+1), hole)
+		}
+	}
+
+	toppedOut, err = matrix.AddRandomGarbage(1, rng)
+	require.NoError(t, err)
+	assert.True(t, toppedOut)
+}
+
+func TestRandomGarbageHole(t *testing.T) {
+	tt := map[string]struct {
+		width int
+		prev  int
+	}{
+		"no previous hole":        {width: 10, prev: -1},
+		"previous hole":           {width: 10, prev: 4},
+		"previous hole at edge":   {width: 4, prev: 3},
+		"previous hole too large": {width: 4, prev: 4},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			//nolint:gosec // This random source is not for any security-related tasks.
+			rng := rand.New(rand.NewPCG(1, 2))
+			for range 100 {
+				hole := RandomGarbageHole(rng, tc.width, tc.prev)
+				assert.GreaterOrEqual(t, hole, 0)
+				assert.Less(t, hole, tc.width)
+				assert.NotEqual(t, tc.prev, hole)
+			}
+		})
+	}
+
+	t.Run("single column", func(t *testing.T) {
+		//nolint:gosec // This random source is not for any security-related tasks.
+		rng := rand.New(rand.NewPCG(1, 2))
+		assert.Equal(t, 0, RandomGarbageHole(rng, 1, -1))
+		assert.Equal(t, 0, RandomGarbageHole(rng, 1, 0))
+	})
+}
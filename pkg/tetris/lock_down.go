@@ -0,0 +1,218 @@
+package tetris
+
+import (
+	"fmt"
+	"time"
+)
+
+// LockDownMode determines how movement affects the Lock Down timer once a Tetrimino lands on a surface.
+type LockDownMode int
+
+const (
+	// LockDownExtended resets the timer on each movement, up to a maximum number of resets.
+	// Once the limit is reached the Tetrimino locks as soon as it is on a surface.
+	// The count is reset when the Tetrimino falls below the lowest row it has reached.
+	LockDownExtended LockDownMode = iota
+	// LockDownInfinite resets the timer on each movement with no limit.
+	LockDownInfinite
+	// LockDownClassic only resets the timer when the Tetrimino falls below the lowest row it has reached.
+	LockDownClassic
+)
+
+const (
+	// DefaultLockDownDelay is the time a Tetrimino can rest on a surface before it locks down.
+	DefaultLockDownDelay = 500 * time.Millisecond
+	// DefaultLockDownMaxResets is the number of movements that reset the timer in LockDownExtended.
+	DefaultLockDownMaxResets = 15
+)
+
+var lockDownModeToStrMap = map[LockDownMode]string{
+	LockDownExtended: "Extended",
+	LockDownInfinite: "Infinite",
+	LockDownClassic:  "Classic",
+}
+
+func (m LockDownMode) String() string {
+	return lockDownModeToStrMap[m]
+}
+
+// ParseLockDownMode parses the string representation of a LockDownMode (eg. "Extended").
+func ParseLockDownMode(s string) (LockDownMode, error) {
+	for mode, str := range lockDownModeToStrMap {
+		if str == s {
+			return mode, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid lock down mode %q", s)
+}
+
+// LockDown tracks the Lock Down timer for the Tetrimino in play.
+// The timer is started when the Tetrimino lands on a surface and the Tetrimino should be
+// locked into the Matrix once the timer expires.
+type LockDown struct {
+	mode      LockDownMode
+	delay     time.Duration
+	maxResets int
+
+	isActive  bool
+	remaining time.Duration
+	resets    int
+	lowestRow int
+}
+
+// NewLockDown creates a new LockDown using the default delay and max resets.
+func NewLockDown(mode LockDownMode) *LockDown {
+	return &LockDown{
+		mode:      mode,
+		delay:     DefaultLockDownDelay,
+		maxResets: DefaultLockDownMaxResets,
+		remaining: DefaultLockDownDelay,
+	}
+}
+
+// SetDelay sets the time a Tetrimino can rest on a surface before it locks down.
+// This takes effect the next time the timer is reset.
+func (ld *LockDown) SetDelay(delay time.Duration) {
+	ld.delay = delay
+}
+
+// Mode returns the LockDownMode.
+func (ld *LockDown) Mode() LockDownMode {
+	return ld.mode
+}
+
+// Reset prepares the timer for a new Tetrimino whose lowest Mino is on the given row.
+func (ld *LockDown) Reset(row int) {
+	ld.isActive = false
+	ld.remaining = ld.delay
+	ld.resets = 0
+	ld.lowestRow = row
+}
+
+// Start starts (or resumes) the timer. This should be called when the Tetrimino lands on a surface.
+// In LockDownExtended the timer expires immediately if the max resets have been used.
+func (ld *LockDown) Start() {
+	ld.isActive = true
+	if ld.isResetLimitReached() {
+		ld.remaining = 0
+	}
+}
+
+// Stop pauses the timer. This should be called when the Tetrimino is no longer on a surface.
+func (ld *LockDown) Stop() {
+	ld.isActive = false
+}
+
+// Descend records that the lowest Mino of the Tetrimino is now on the given row.
+// If this is lower than any row previously reached the timer and reset count are reset.
+func (ld *LockDown) Descend(row int) {
+	if row <= ld.lowestRow {
+		return
+	}
+	ld.lowestRow = row
+	ld.resets = 0
+	ld.remaining = ld.delay
+}
+
+// Move records a successful movement (ie. left, right or rotation) of the Tetrimino.
+// Depending on the LockDownMode this may reset the timer. In LockDownExtended the timer expires
+// immediately once the max resets have been used.
+func (ld *LockDown) Move() {
+	if !ld.isActive {
+		return
+	}
+
+	switch ld.mode {
+	case LockDownExtended:
+		if ld.isResetLimitReached() {
+			ld.remaining = 0
+			return
+		}
+		ld.resets++
+		ld.remaining = ld.delay
+	case LockDownInfinite:
+		ld.remaining = ld.delay
+	case LockDownClassic:
+	}
+}
+
+// isResetLimitReached returns true if no movements can reset the timer until the Tetrimino descends.
+func (ld *LockDown) isResetLimitReached() bool {
+	return ld.mode == LockDownExtended && ld.resets >= ld.maxResets
+}
+
+// Advance reduces the remaining time on the timer by the elapsed duration if it is active.
+// It returns true if the timer has expired.
+func (ld *LockDown) Advance(elapsed time.Duration) bool {
+	if !ld.isActive {
+		return false
+	}
+	ld.remaining -= elapsed
+	return ld.IsExpired()
+}
+
+// IsActive returns true if the timer is running.
+func (ld *LockDown) IsActive() bool {
+	return ld.isActive
+}
+
+// IsExpired returns true if the timer is running and has no time remaining.
+func (ld *LockDown) IsExpired() bool {
+	return ld.isActive && ld.remaining <= 0
+}
+
+// Remaining returns the time remaining before the timer expires.
+func (ld *LockDown) Remaining() time.Duration {
+	return max(ld.remaining, 0)
+}
+
+// LockDownState is the serializable state of a LockDown timer.
+type LockDownState struct {
+	Mode      string        `json:"mode"`
+	IsActive  bool          `json:"is_active"`
+	Remaining time.Duration `json:"remaining"`
+	Resets    int           `json:"resets"`
+	LowestRow int           `json:"lowest_row"`
+	// Delay is the time a Tetrimino can rest on a surface, or 0 if it is DefaultLockDownDelay.
+	Delay time.Duration `json:"delay,omitempty"`
+}
+
+// State returns the state of the LockDown timer.
+func (ld *LockDown) State() LockDownState {
+	var delay time.Duration
+	if ld.delay != DefaultLockDownDelay {
+		delay = ld.delay
+	}
+	return LockDownState{
+		Mode:      ld.mode.String(),
+		IsActive:  ld.isActive,
+		Remaining: ld.remaining,
+		Resets:    ld.resets,
+		LowestRow: ld.lowestRow,
+		Delay:     delay,
+	}
+}
+
+// RestoreLockDown recreates a LockDown timer from a state returned by State.
+func RestoreLockDown(state LockDownState) (*LockDown, error) {
+	mode, err := ParseLockDownMode(state.Mode)
+	if err != nil {
+		return nil, err
+	}
+	delay := DefaultLockDownDelay
+	if state.Delay != 0 {
+		delay = state.Delay
+	}
+	if delay < 0 || state.Resets < 0 || state.Remaining > delay {
+		return nil, fmt.Errorf("invalid delay '%s', resets '%d' or remaining time '%s'",
+			delay, state.Resets, state.Remaining)
+	}
+
+	ld := NewLockDown(mode)
+	ld.delay = delay
+	ld.isActive = state.IsActive
+	ld.remaining = state.Remaining
+	ld.resets = state.Resets
+	ld.lowestRow = state.LowestRow
+	return ld, nil
+}
@@ -0,0 +1,151 @@
+package tetris
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLockDownMode(t *testing.T) {
+	tt := map[string]struct {
+		want    LockDownMode
+		wantErr bool
+	}{
+		"Extended": {want: LockDownExtended},
+		"Infinite": {want: LockDownInfinite},
+		"Classic":  {want: LockDownClassic},
+		"":         {wantErr: true},
+		"extended": {wantErr: true},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseLockDownMode(name)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, name, got.String())
+		})
+	}
+}
+
+func TestLockDown_Advance(t *testing.T) {
+	ld := NewLockDown(LockDownExtended)
+	ld.Reset(10)
+
+	assert.False(t, ld.Advance(time.Second), "inactive timer should not expire")
+	assert.Equal(t, DefaultLockDownDelay, ld.Remaining())
+
+	ld.Start()
+	assert.False(t, ld.Advance(DefaultLockDownDelay/2))
+	assert.True(t, ld.Advance(DefaultLockDownDelay/2))
+	assert.True(t, ld.IsExpired())
+	assert.Equal(t, time.Duration(0), ld.Remaining())
+
+	ld.Stop()
+	assert.False(t, ld.IsExpired(), "stopped timer should not be expired")
+}
+
+func TestLockDown_Move(t *testing.T) {
+	tt := map[string]struct {
+		mode          LockDownMode
+		moves         int
+		wantRemaining time.Duration
+	}{
+		"extended; within max resets": {
+			mode:          LockDownExtended,
+			moves:         DefaultLockDownMaxResets,
+			wantRemaining: DefaultLockDownDelay,
+		},
+		"extended; exceeds max resets": {
+			mode:          LockDownExtended,
+			moves:         DefaultLockDownMaxResets + 1,
+			wantRemaining: 0,
+		},
+		"infinite": {
+			mode:          LockDownInfinite,
+			moves:         DefaultLockDownMaxResets * 2,
+			wantRemaining: DefaultLockDownDelay,
+		},
+		"classic": {
+			mode:          LockDownClassic,
+			moves:         1,
+			wantRemaining: DefaultLockDownDelay - time.Millisecond,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			ld := NewLockDown(tc.mode)
+			ld.Reset(10)
+			ld.Start()
+
+			for range tc.moves {
+				ld.Advance(time.Millisecond)
+				ld.Move()
+			}
+
+			assert.Equal(t, tc.wantRemaining, ld.Remaining())
+		})
+	}
+}
+
+func TestLockDown_ResetLimit(t *testing.T) {
+	ld := NewLockDown(LockDownExtended)
+	ld.Reset(10)
+	ld.Start()
+	for range DefaultLockDownMaxResets {
+		ld.Move()
+	}
+	assert.False(t, ld.IsExpired(), "moves within the limit should reset the timer")
+
+	ld.Stop()
+	ld.Start()
+	assert.True(t, ld.IsExpired(), "landing after the limit should lock immediately")
+
+	ld.Descend(11)
+	ld.Start()
+	assert.False(t, ld.IsExpired(), "descending should restore the resets")
+	assert.Equal(t, DefaultLockDownDelay, ld.Remaining())
+}
+
+func TestLockDown_Descend(t *testing.T) {
+	ld := NewLockDown(LockDownClassic)
+	ld.Reset(10)
+	ld.Start()
+	ld.Advance(DefaultLockDownDelay / 2)
+
+	ld.Descend(10)
+	assert.Equal(t, DefaultLockDownDelay/2, ld.Remaining(), "same row should not reset the timer")
+
+	ld.Descend(11)
+	assert.Equal(t, DefaultLockDownDelay, ld.Remaining(), "lower row should reset the timer")
+}
+
+func TestRestoreLockDown(t *testing.T) {
+	ld := NewLockDown(LockDownInfinite)
+	ld.Reset(20)
+	ld.Start()
+	ld.Advance(200 * time.Millisecond)
+	ld.Move()
+
+	restored, err := RestoreLockDown(ld.State())
+	require.NoError(t, err)
+	assert.Equal(t, ld, restored)
+
+	state := ld.State()
+	state.Mode = "unknown"
+	_, err = RestoreLockDown(state)
+	assert.Error(t, err)
+
+	ld.SetDelay(time.Second)
+	ld.Reset(20)
+	restored, err = RestoreLockDown(ld.State())
+	require.NoError(t, err)
+	assert.Equal(t, ld, restored, "a custom delay should be restored")
+}
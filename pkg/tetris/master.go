@@ -0,0 +1,275 @@
+package tetris
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// MasterMaxLevel is the level at which a game of Master is complete.
+	MasterMaxLevel = 999
+	// masterSectionLevels is the number of levels in each section of Master, the last level of which is a section stop.
+	masterSectionLevels = 100
+
+	// Frame is the duration of a single frame at 60 FPS, which Master timings are traditionally measured in.
+	Frame = time.Second / 60
+	// InstantGravity is the gravity (in 1/256ths of a row per frame) at which a Tetrimino drops to the lowest
+	// position it can reach immediately. This is known as 20G.
+	InstantGravity = 20 * 256
+)
+
+// MasterSection is the timing used from its Level until the Level of the next section.
+type MasterSection struct {
+	Level          int           `json:"level"`            // The first level of the section.
+	ARE            time.Duration `json:"are"`              // The delay before the next Tetrimino spawns.
+	LineClearDelay time.Duration `json:"line_clear_delay"` // The extra delay before spawning after lines are cleared.
+	LockDelay      time.Duration `json:"lock_delay"`       // The time a Tetrimino can rest on a surface before locking.
+}
+
+// DefaultMasterSections are the timings of each section of Master, with the delays shortening after level 500.
+var DefaultMasterSections = []MasterSection{
+	{Level: 0, ARE: 25 * Frame, LineClearDelay: 40 * Frame, LockDelay: 30 * Frame},
+	{Level: 100, ARE: 25 * Frame, LineClearDelay: 40 * Frame, LockDelay: 30 * Frame},
+	{Level: 200, ARE: 25 * Frame, LineClearDelay: 40 * Frame, LockDelay: 30 * Frame},
+	{Level: 300, ARE: 25 * Frame, LineClearDelay: 40 * Frame, LockDelay: 30 * Frame},
+	{Level: 400, ARE: 25 * Frame, LineClearDelay: 40 * Frame, LockDelay: 30 * Frame},
+	{Level: 500, ARE: 25 * Frame, LineClearDelay: 25 * Frame, LockDelay: 30 * Frame},
+	{Level: 600, ARE: 25 * Frame, LineClearDelay: 16 * Frame, LockDelay: 30 * Frame},
+	{Level: 700, ARE: 16 * Frame, LineClearDelay: 12 * Frame, LockDelay: 30 * Frame},
+	{Level: 800, ARE: 12 * Frame, LineClearDelay: 6 * Frame, LockDelay: 30 * Frame},
+	{Level: 900, ARE: 12 * Frame, LineClearDelay: 6 * Frame, LockDelay: 17 * Frame},
+}
+
+// masterGravityCurve is the gravity (in 1/256ths of a row per frame) from each level of Master.
+// It briefly drops back to a crawl at level 200 before reaching 20G at level 500.
+var masterGravityCurve = []struct {
+	level   int
+	gravity int
+}{
+	{0, 4}, {30, 6}, {35, 8}, {40, 10}, {50, 12}, {60, 16}, {70, 32}, {80, 48}, {90, 64}, {100, 80},
+	{120, 96}, {140, 112}, {160, 128}, {170, 144}, {200, 4}, {220, 32}, {230, 64}, {233, 96}, {236, 128},
+	{239, 160}, {243, 192}, {247, 224}, {251, 256}, {300, 512}, {330, 768}, {360, 1024}, {400, 1280},
+	{420, 1024}, {450, 768}, {500, InstantGravity},
+}
+
+// MasterGravity returns the gravity (in 1/256ths of a row per frame) at the given level of Master.
+func MasterGravity(level int) int {
+	gravity := masterGravityCurve[0].gravity
+	for _, step := range masterGravityCurve {
+		if step.level > level {
+			break
+		}
+		gravity = step.gravity
+	}
+	return gravity
+}
+
+// MasterGrade is the grade awarded in Master based on the score, from 9 (the lowest) to GM (Grand Master).
+type MasterGrade int
+
+const (
+	MasterGrade9 MasterGrade = iota
+	MasterGrade8
+	MasterGrade7
+	MasterGrade6
+	MasterGrade5
+	MasterGrade4
+	MasterGrade3
+	MasterGrade2
+	MasterGrade1
+	MasterGradeS1
+	MasterGradeS2
+	MasterGradeS3
+	MasterGradeS4
+	MasterGradeS5
+	MasterGradeS6
+	MasterGradeS7
+	MasterGradeS8
+	MasterGradeS9
+	MasterGradeGM
+)
+
+var masterGradeToStrMap = map[MasterGrade]string{
+	MasterGrade9:  "9",
+	MasterGrade8:  "8",
+	MasterGrade7:  "7",
+	MasterGrade6:  "6",
+	MasterGrade5:  "5",
+	MasterGrade4:  "4",
+	MasterGrade3:  "3",
+	MasterGrade2:  "2",
+	MasterGrade1:  "1",
+	MasterGradeS1: "S1",
+	MasterGradeS2: "S2",
+	MasterGradeS3: "S3",
+	MasterGradeS4: "S4",
+	MasterGradeS5: "S5",
+	MasterGradeS6: "S6",
+	MasterGradeS7: "S7",
+	MasterGradeS8: "S8",
+	MasterGradeS9: "S9",
+	MasterGradeGM: "GM",
+}
+
+func (g MasterGrade) String() string {
+	return masterGradeToStrMap[g]
+}
+
+// masterGradeScores is the score required for each grade up to S9, indexed by MasterGrade.
+var masterGradeScores = []int{
+	0, 400, 800, 1400, 2000, 3500, 5500, 8000, 12000,
+	16000, 22000, 30000, 40000, 52000, 66000, 82000, 100000, 120000,
+}
+
+// MasterGrandMasterScore is the score required for the GM grade, which is only awarded once Master is complete.
+const MasterGrandMasterScore = 126000
+
+// MasterGradeFor returns the grade for a game of Master with the given score, and whether it was completed.
+func MasterGradeFor(score int, completed bool) MasterGrade {
+	if completed && score >= MasterGrandMasterScore {
+		return MasterGradeGM
+	}
+	grade := MasterGrade9
+	for i, required := range masterGradeScores {
+		if score >= required {
+			grade = MasterGrade(i)
+		}
+	}
+	return grade
+}
+
+// Master tracks the level, score and grade of a game of Master, which replaces the guideline scoring system.
+// The level increases by one for each Tetrimino that spawns (except at the last level of a section) and by
+// the number of lines cleared. The game is complete once MasterMaxLevel is reached.
+type Master struct {
+	level    int
+	score    int
+	combo    int
+	sections []MasterSection
+}
+
+// NewMaster creates a new Master at level 0 using the given section timings.
+// The sections must start at level 0 and be in increasing order of level.
+func NewMaster(sections []MasterSection) (*Master, error) {
+	m := &Master{
+		combo:    1,
+		sections: sections,
+	}
+	return m, m.validate()
+}
+
+func (m *Master) validate() error {
+	if len(m.sections) == 0 || m.sections[0].Level != 0 {
+		return errors.New("the first section must start at level 0")
+	}
+	for i, s := range m.sections {
+		if i > 0 && s.Level <= m.sections[i-1].Level {
+			return fmt.Errorf("section %d does not start after the previous section", i)
+		}
+		if s.ARE < 0 || s.LineClearDelay < 0 || s.LockDelay <= 0 {
+			return fmt.Errorf("invalid timing for section %d: %+v", i, s)
+		}
+	}
+	if m.level < 0 || m.level > MasterMaxLevel {
+		return fmt.Errorf("invalid level '%d'", m.level)
+	}
+	if m.score < 0 || m.combo < 1 {
+		return fmt.Errorf("invalid score '%d' or combo '%d'", m.score, m.combo)
+	}
+	return nil
+}
+
+// Level returns the current level.
+func (m *Master) Level() int {
+	return m.level
+}
+
+// Score returns the current score.
+func (m *Master) Score() int {
+	return m.score
+}
+
+// Grade returns the grade for the current score.
+func (m *Master) Grade() MasterGrade {
+	return MasterGradeFor(m.score, m.IsComplete())
+}
+
+// IsComplete returns true once MasterMaxLevel has been reached.
+func (m *Master) IsComplete() bool {
+	return m.level >= MasterMaxLevel
+}
+
+// SectionStop returns the level which Tetriminos spawning cannot advance past, which is the last level of the
+// current section. Only clearing lines can advance the level beyond it.
+func (m *Master) SectionStop() int {
+	return min((m.level/masterSectionLevels+1)*masterSectionLevels-1, MasterMaxLevel)
+}
+
+// Section returns the timing of the current level.
+func (m *Master) Section() MasterSection {
+	section := m.sections[0]
+	for _, s := range m.sections {
+		if s.Level > m.level {
+			break
+		}
+		section = s
+	}
+	return section
+}
+
+// Spawn records a Tetrimino spawning, which advances the level unless it is at a section stop.
+// The last level before MasterMaxLevel is also a section stop, so that the game ends on a line clear.
+func (m *Master) Spawn() {
+	if m.level >= m.SectionStop() || m.level >= MasterMaxLevel-1 {
+		return
+	}
+	m.level++
+}
+
+// Lock records a Tetrimino locking down, awarding points and advancing the level for any lines cleared.
+// The points are ceil((level + lines) / 4) * lines * combo, multiplied by 4 for a perfect clear (known as a
+// Bravo), where combo increases with each consecutive lock down that clears lines.
+func (m *Master) Lock(lines int, perfectClear bool) {
+	if lines <= 0 {
+		m.combo = 1
+		return
+	}
+
+	m.combo += 2*lines - 2
+	bravo := 1
+	if perfectClear {
+		bravo = 4
+	}
+	m.score += (m.level + lines + 3) / 4 * lines * m.combo * bravo
+	m.level = min(m.level+lines, MasterMaxLevel)
+}
+
+// MasterState is the serializable state of a Master.
+type MasterState struct {
+	Level    int             `json:"level"`
+	Score    int             `json:"score"`
+	Combo    int             `json:"combo"`
+	Sections []MasterSection `json:"sections"`
+}
+
+// State returns the state of the Master.
+func (m *Master) State() MasterState {
+	return MasterState{
+		Level:    m.level,
+		Score:    m.score,
+		Combo:    m.combo,
+		Sections: m.sections,
+	}
+}
+
+// RestoreMaster recreates a Master from a state returned by State.
+func RestoreMaster(state MasterState) (*Master, error) {
+	m := &Master{
+		level:    state.Level,
+		score:    state.Score,
+		combo:    state.Combo,
+		sections: state.Sections,
+	}
+	return m, m.validate()
+}
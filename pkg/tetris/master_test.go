@@ -0,0 +1,153 @@
+package tetris
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMasterGravity(t *testing.T) {
+	tt := map[string]struct {
+		level int
+		want  int
+	}{
+		"level 0":   {level: 0, want: 4},
+		"level 34":  {level: 34, want: 6},
+		"level 199": {level: 199, want: 144},
+		"level 200": {level: 200, want: 4},
+		"level 499": {level: 499, want: 768},
+		"level 500": {level: 500, want: InstantGravity},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, MasterGravity(tc.level))
+		})
+	}
+}
+
+func TestMasterGradeFor(t *testing.T) {
+	tt := map[string]struct {
+		score     int
+		completed bool
+		want      MasterGrade
+	}{
+		"no score":             {score: 0, want: MasterGrade9},
+		"below grade 8":        {score: 399, want: MasterGrade9},
+		"grade 1":              {score: 12000, want: MasterGrade1},
+		"S5":                   {score: 60000, want: MasterGradeS5},
+		"S9 not completed":     {score: 200000, want: MasterGradeS9},
+		"GM":                   {score: 126000, completed: true, want: MasterGradeGM},
+		"completed below GM":   {score: 125999, completed: true, want: MasterGradeS9},
+		"completed low scores": {score: 500, completed: true, want: MasterGrade8},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, MasterGradeFor(tc.score, tc.completed))
+		})
+	}
+}
+
+func TestNewMaster(t *testing.T) {
+	tt := map[string]struct {
+		sections []MasterSection
+		wantErr  bool
+	}{
+		"default": {sections: DefaultMasterSections},
+		"no sections": {
+			sections: nil,
+			wantErr:  true,
+		},
+		"first section after level 0": {
+			sections: []MasterSection{{Level: 100, LockDelay: Frame}},
+			wantErr:  true,
+		},
+		"out of order": {
+			sections: []MasterSection{
+				{Level: 0, LockDelay: Frame}, {Level: 500, LockDelay: Frame}, {Level: 200, LockDelay: Frame},
+			},
+			wantErr: true,
+		},
+		"no lock delay": {
+			sections: []MasterSection{{Level: 0}},
+			wantErr:  true,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			m, err := NewMaster(tc.sections)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 0, m.Level())
+			assert.Equal(t, 0, m.Score())
+			assert.Equal(t, MasterGrade9, m.Grade())
+		})
+	}
+}
+
+func TestMaster_Spawn(t *testing.T) {
+	m, err := NewMaster(DefaultMasterSections)
+	require.NoError(t, err)
+
+	for range 150 {
+		m.Spawn()
+	}
+	assert.Equal(t, 99, m.Level(), "spawning should not pass the section stop")
+	assert.Equal(t, 99, m.SectionStop())
+
+	m.Lock(1, false)
+	assert.Equal(t, 100, m.Level(), "clearing lines should pass the section stop")
+	assert.Equal(t, 199, m.SectionStop())
+	assert.Equal(t, DefaultMasterSections[1], m.Section())
+
+	m.level = 990
+	for range 20 {
+		m.Spawn()
+	}
+	assert.Equal(t, MasterMaxLevel-1, m.Level(), "the last level should only be reached by clearing lines")
+	assert.False(t, m.IsComplete())
+
+	m.Lock(4, false)
+	assert.Equal(t, MasterMaxLevel, m.Level())
+	assert.True(t, m.IsComplete())
+}
+
+func TestMaster_Lock(t *testing.T) {
+	m, err := NewMaster(DefaultMasterSections)
+	require.NoError(t, err)
+
+	m.Lock(4, false)
+	assert.Equal(t, 28, m.Score(), "ceil((0+4)/4) * 4 lines * combo 7")
+	assert.Equal(t, 4, m.Level())
+
+	m.Lock(1, false)
+	assert.Equal(t, 28+14, m.Score(), "ceil((4+1)/4) * 1 line * combo 7")
+	assert.Equal(t, 5, m.Level())
+
+	m.Lock(0, false)
+	m.Lock(2, true)
+	assert.Equal(t, 28+14+48, m.Score(), "ceil((5+2)/4) * 2 lines * combo 3 * bravo 4")
+	assert.Equal(t, 7, m.Level())
+}
+
+func TestRestoreMaster(t *testing.T) {
+	m, err := NewMaster(DefaultMasterSections)
+	require.NoError(t, err)
+	m.Spawn()
+	m.Lock(2, false)
+
+	restored, err := RestoreMaster(m.State())
+	require.NoError(t, err)
+	assert.Equal(t, m, restored)
+
+	state := m.State()
+	state.Level = MasterMaxLevel + 1
+	_, err = RestoreMaster(state)
+	assert.Error(t, err)
+}
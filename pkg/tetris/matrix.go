@@ -1,7 +1,6 @@
 package tetris
 
 import (
-	"errors"
 	"fmt"
 	"slices"
 )
@@ -9,9 +8,21 @@ import (
 // Matrix represents the board of cells on which the game is played.
 type Matrix [][]byte
 
-// DefaultMatrix creates a new Matrix with a height of 40 and a width of 10.
+const (
+	// DefaultMatrixWidth is the number of columns in a standard Matrix.
+	DefaultMatrixWidth = 10
+	// DefaultMatrixHeight is the number of visible rows in a standard Matrix.
+	DefaultMatrixHeight = 20
+
+	MinMatrixWidth  = 4
+	MaxMatrixWidth  = 20
+	MinMatrixHeight = 4
+	MaxMatrixHeight = 40
+)
+
+// DefaultMatrix creates a new Matrix with a visible height of 20 and a width of 10.
 func DefaultMatrix() Matrix {
-	m, err := NewMatrix(40, 10)
+	m, err := NewMatrix(DefaultMatrixHeight*2, DefaultMatrixWidth)
 	if err != nil {
 		panic(fmt.Errorf("failed to create default matrix: %w", err))
 	}
@@ -19,10 +30,20 @@ func DefaultMatrix() Matrix {
 }
 
 // NewMatrix creates a new Matrix with the given height and width.
-// It returns an error if the height is less than 20 to allow for a buffer zone of 20 lines.
+// The height includes the buffer zone, which is the top half of the Matrix, so the visible height is half the height.
+// It returns an error if the height is odd or if the width or visible height are outside the supported range
+// (see MinMatrixWidth, MaxMatrixWidth, MinMatrixHeight and MaxMatrixHeight).
 func NewMatrix(height, width int) (Matrix, error) {
-	if height <= 20 {
-		return nil, errors.New("matrix height must be greater than 20 to allow for a buffer zone of 20 lines")
+	if width < MinMatrixWidth || width > MaxMatrixWidth {
+		return nil, fmt.Errorf("matrix width must be between %d and %d, got %d", MinMatrixWidth, MaxMatrixWidth, width)
+	}
+	if height%2 != 0 {
+		return nil, fmt.Errorf("matrix height must be even to allow for a buffer zone the size of the visible area, got %d",
+			height)
+	}
+	if visible := height / 2; visible < MinMatrixHeight || visible > MaxMatrixHeight {
+		return nil, fmt.Errorf("matrix visible height must be between %d and %d, got %d",
+			MinMatrixHeight, MaxMatrixHeight, visible)
 	}
 
 	matrix := make(Matrix, height)
@@ -37,14 +58,31 @@ func (m *Matrix) GetHeight() int {
 	return len(*m)
 }
 
+// GetWidth returns the width of the Matrix.
+func (m *Matrix) GetWidth() int {
+	if len(*m) == 0 {
+		return 0
+	}
+	return len((*m)[0])
+}
+
 // GetSkyline returns the skyline; the highest row that the player can see.
+// The buffer zone above the skyline is the same height as the visible portion of the Matrix.
 func (m *Matrix) GetSkyline() int {
-	return len(*m) - 20
+	return len(*m) / 2
 }
 
 // GetVisible returns the Matrix without the buffer zone at the top (ie. the visible portion of the Matrix).
 func (m *Matrix) GetVisible() Matrix {
-	return (*m)[20:]
+	return (*m)[m.GetSkyline():]
+}
+
+// GetStartingPosition returns the position at which the given Tetrimino spawns in the Matrix.
+// This is just above the skyline, horizontally centered (rounding to the left).
+func (m *Matrix) GetStartingPosition(tet *Tetrimino) Coordinate {
+	pos := startingPosition(tet, m.GetWidth())
+	pos.Y += m.GetSkyline()
+	return pos
 }
 
 func (m *Matrix) DeepCopy() *Matrix {
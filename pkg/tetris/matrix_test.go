@@ -18,16 +18,37 @@ func Test_NewMatrix(t *testing.T) {
 		wantErr error
 	}{
 		"success": {
-			width:  1,
-			height: 21,
+			width:  4,
+			height: 8,
 			want: Matrix{
-				{0}, {0}, {0}, {0}, {0}, {0}, {0}, {0}, {0}, {0}, {0}, {0}, {0}, {0}, {0}, {0}, {0}, {0}, {0}, {0}, {0},
+				{0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0},
+				{0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0},
 			},
 		},
-		"failure": {
-			width:   1,
-			height:  20,
-			wantErr: errors.New("matrix height must be greater than 20 to allow for a buffer zone of 20 lines"),
+		"failure; width too small": {
+			width:   3,
+			height:  40,
+			wantErr: errors.New("matrix width must be between 4 and 20, got 3"),
+		},
+		"failure; width too large": {
+			width:   21,
+			height:  40,
+			wantErr: errors.New("matrix width must be between 4 and 20, got 21"),
+		},
+		"failure; odd height": {
+			width:   10,
+			height:  41,
+			wantErr: errors.New("matrix height must be even to allow for a buffer zone the size of the visible area, got 41"),
+		},
+		"failure; visible height too small": {
+			width:   10,
+			height:  6,
+			wantErr: errors.New("matrix visible height must be between 4 and 40, got 3"),
+		},
+		"failure; visible height too large": {
+			width:   10,
+			height:  82,
+			wantErr: errors.New("matrix visible height must be between 4 and 40, got 41"),
 		},
 	}
 
@@ -44,6 +65,60 @@ func Test_NewMatrix(t *testing.T) {
 	}
 }
 
+func TestMatrix_GetVisible(t *testing.T) {
+	tt := map[string]struct {
+		width       int
+		height      int
+		wantSkyline int
+	}{
+		"default": {width: 10, height: 40, wantSkyline: 20},
+		"narrow":  {width: 4, height: 40, wantSkyline: 20},
+		"tall":    {width: 10, height: 60, wantSkyline: 30},
+		"short":   {width: 20, height: 8, wantSkyline: 4},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			m, err := NewMatrix(tc.height, tc.width)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.width, m.GetWidth())
+			assert.Equal(t, tc.wantSkyline, m.GetSkyline())
+			assert.Len(t, m.GetVisible(), tc.height-tc.wantSkyline)
+		})
+	}
+}
+
+func TestMatrix_GetStartingPosition(t *testing.T) {
+	tt := map[string]struct {
+		width int
+		want  map[byte]int
+	}{
+		"default": {width: 10, want: map[byte]int{'I': 3, 'O': 4, 'T': 3}},
+		"minimum": {width: 4, want: map[byte]int{'I': 0, 'O': 1, 'T': 0}},
+		"odd":     {width: 7, want: map[byte]int{'I': 1, 'O': 2, 'T': 2}},
+		"maximum": {width: 20, want: map[byte]int{'I': 8, 'O': 9, 'T': 8}},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			m, err := NewMatrix(40, tc.width)
+			require.NoError(t, err)
+
+			for value, wantX := range tc.want {
+				tet, err := GetTetrimino(value)
+				require.NoError(t, err)
+
+				pos := m.GetStartingPosition(tet)
+				assert.Equal(t, wantX, pos.X, "tetrimino %q", value)
+				assert.Equal(t, tet.Position.Y+m.GetSkyline(), pos.Y, "tetrimino %q", value)
+				tet.Position = pos
+				assert.True(t, tet.IsValid(m, false), "tetrimino %q", value)
+			}
+		})
+	}
+}
+
 func TestMatrix_isLineComplete(t *testing.T) {
 	matrix := &Matrix{
 		[]byte{1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
@@ -0,0 +1,163 @@
+package single
+
+import (
+	"fmt"
+	"time"
+)
+
+// Clock provides the time elapsed since the start of a game.
+type Clock interface {
+	Now() time.Duration
+}
+
+// StepClock is a Clock which only moves forward when stepped. This is the default Clock of an Engine.
+type StepClock struct {
+	now time.Duration
+}
+
+// Now returns the total duration the clock has been stepped.
+func (c *StepClock) Now() time.Duration {
+	return c.now
+}
+
+// Step moves the clock forward by the given duration.
+func (c *StepClock) Step(d time.Duration) {
+	c.now += d
+}
+
+// Engine runs a Game without any dependency on a UI or the wall clock.
+// Gravity and the Lock Down timer are driven by calling Advance (or Sync, using the injected Clock),
+// and each operation returns the Events which were emitted by the Game as a result.
+type Engine struct {
+	game  *Game
+	clock Clock
+
+	elapsed     time.Duration // The game time which has been processed
+	fallElapsed time.Duration // The game time since the last gravity tick
+	events      []Event       // The Events emitted since they were last returned
+}
+
+// WithClock sets the Clock used by Engine.Sync.
+func WithClock(clock Clock) func(*Engine) {
+	return func(e *Engine) {
+		e.clock = clock
+	}
+}
+
+// NewEngine creates a new Game and an Engine to drive it.
+// The Events emitted whilst creating the Game are returned by the first operation on the Engine.
+func NewEngine(in *Input, opts ...func(*Engine)) (*Engine, error) {
+	e := &Engine{
+		clock: new(StepClock),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	game, err := NewGame(in, WithEventHandler(e.handleEvent))
+	if err != nil {
+		return nil, err
+	}
+	e.game = game
+	return e, nil
+}
+
+// Game returns the Game being driven. This should only be used for reading its state;
+// operations should be performed using the Engine so that Events are captured.
+func (e *Engine) Game() *Game {
+	return e.game
+}
+
+// Elapsed returns the game time which has been processed.
+func (e *Engine) Elapsed() time.Duration {
+	return e.elapsed
+}
+
+// Sync advances the game to the current time of the Clock.
+func (e *Engine) Sync() ([]Event, error) {
+	return e.Advance(e.clock.Now() - e.elapsed)
+}
+
+// Advance moves the game forward by the given duration, performing any gravity ticks and
+// Lock Downs which occur in that time. Non-positive durations are ignored.
+func (e *Engine) Advance(d time.Duration) ([]Event, error) {
+	var events []Event
+	for d > 0 && !e.game.IsGameOver() {
+		untilTick := e.game.GetFallInterval() - e.fallElapsed
+		if d < untilTick {
+			e.fallElapsed += d
+			e.elapsed += d
+			break
+		}
+
+		untilTick = max(untilTick, 0)
+		d -= untilTick
+		e.elapsed += untilTick
+		e.fallElapsed += untilTick
+
+		e.game.UpdateLockDown(e.fallElapsed)
+		e.fallElapsed = 0
+		_, err := e.game.TickLower()
+		events = append(events, e.drain()...)
+		if err != nil {
+			return events, fmt.Errorf("failed to tick lower: %w", err)
+		}
+	}
+	return events, nil
+}
+
+// MoveLeft moves the Tetrimino in play left.
+func (e *Engine) MoveLeft() []Event {
+	e.game.MoveLeft()
+	return e.drain()
+}
+
+// MoveRight moves the Tetrimino in play right.
+func (e *Engine) MoveRight() []Event {
+	e.game.MoveRight()
+	return e.drain()
+}
+
+// Rotate rotates the Tetrimino in play.
+func (e *Engine) Rotate(clockwise bool) ([]Event, error) {
+	err := e.game.Rotate(clockwise)
+	return e.drain(), err
+}
+
+// ToggleSoftDrop toggles the Soft Drop state of the game.
+func (e *Engine) ToggleSoftDrop() []Event {
+	e.game.ToggleSoftDrop()
+	return e.drain()
+}
+
+// HardDrop drops the Tetrimino in play and locks it down.
+func (e *Engine) HardDrop() ([]Event, error) {
+	e.fallElapsed = 0
+	_, err := e.game.HardDrop()
+	return e.drain(), err
+}
+
+// Hold swaps the Tetrimino in play with the held Tetrimino.
+func (e *Engine) Hold() ([]Event, error) {
+	_, err := e.game.Hold()
+	return e.drain(), err
+}
+
+// EndGame ends the game.
+func (e *Engine) EndGame() []Event {
+	e.game.EndGame()
+	return e.drain()
+}
+
+// handleEvent records an Event emitted by the Game, stamping it with the current game time.
+func (e *Engine) handleEvent(event Event) {
+	event.Time = e.elapsed
+	e.events = append(e.events, event)
+}
+
+// drain returns the Events recorded since the last call and clears them.
+func (e *Engine) drain() []Event {
+	events := e.events
+	e.events = nil
+	return events
+}
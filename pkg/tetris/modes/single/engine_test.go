@@ -0,0 +1,106 @@
+package single
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris"
+)
+
+func newTestEngine(t *testing.T, opts ...func(*Engine)) *Engine {
+	t.Helper()
+
+	e, err := NewEngine(&Input{
+		Level:         1,
+		MaxLevel:      15,
+		IncreaseLevel: true,
+		Rand:          rand.New(rand.NewPCG(0, 0)),
+	}, opts...)
+	require.NoError(t, err)
+	return e
+}
+
+func eventKinds(events []Event) []EventKind {
+	kinds := make([]EventKind, len(events))
+	for i, e := range events {
+		kinds[i] = e.Kind
+	}
+	return kinds
+}
+
+func TestEngine_Advance(t *testing.T) {
+	e := newTestEngine(t)
+
+	// Not enough time for a gravity tick.
+	events, err := e.Advance(500 * time.Millisecond)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+	assert.Equal(t, 500*time.Millisecond, e.Elapsed())
+
+	// With no input the Tetriminos stack up until the game is over.
+	events, err = e.Advance(time.Hour)
+	require.NoError(t, err)
+	require.NotEmpty(t, events)
+	assert.True(t, e.Game().IsGameOver())
+	assert.Contains(t, eventKinds(events), EventPieceLocked)
+	assert.Equal(t, EventGameOver, events[len(events)-1].Kind)
+	assert.Less(t, e.Elapsed(), time.Hour)
+
+	// Once the game is over no time is processed.
+	elapsed := e.Elapsed()
+	events, err = e.Advance(time.Second)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+	assert.Equal(t, elapsed, e.Elapsed())
+}
+
+func TestEngine_Sync(t *testing.T) {
+	clock := new(StepClock)
+	e := newTestEngine(t, WithClock(clock))
+	startY := e.Game().tetInPlay.Position.Y
+
+	clock.Step(2500 * time.Millisecond)
+	_, err := e.Sync()
+	require.NoError(t, err)
+
+	assert.Equal(t, 2500*time.Millisecond, e.Elapsed())
+	assert.Equal(t, startY+2, e.Game().tetInPlay.Position.Y)
+}
+
+func TestEngine_HardDrop(t *testing.T) {
+	e := newTestEngine(t)
+	g := e.Game()
+
+	// Fill the bottom row, leaving a gap for a horizontal I Tetrimino.
+	// The Mino above the row prevents a Perfect Clear.
+	bottom := g.matrix.GetHeight() - 1
+	for col := range g.matrix[bottom] {
+		if col < 3 || col > 6 {
+			g.matrix[bottom][col] = 'X'
+		}
+	}
+	g.matrix[bottom-1][0] = 'X'
+	tet, err := tetris.GetTetrimino('I')
+	require.NoError(t, err)
+	g.tetInPlay = tet.DeepCopy()
+	g.tetInPlay.Position.Y += g.matrix.GetSkyline()
+
+	_, err = e.Advance(time.Second)
+	require.NoError(t, err)
+
+	events, err := e.HardDrop()
+	require.NoError(t, err)
+	require.Equal(t, []EventKind{EventPieceLocked, EventLinesCleared, EventPieceSpawned}, eventKinds(events))
+	assert.Equal(t, tetris.Actions.Single, events[1].Action)
+	assert.Equal(t, 1, events[1].Lines)
+	assert.Equal(t, time.Second, events[1].Time)
+
+	events = e.EndGame()
+	assert.Equal(t, []EventKind{EventGameOver}, eventKinds(events))
+	assert.Equal(t, GameOverEnded, events[0].Reason)
+	assert.Empty(t, e.EndGame())
+}
@@ -0,0 +1,123 @@
+package single
+
+import (
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris"
+)
+
+// EventKind identifies something notable that happened during a Game.
+type EventKind int
+
+const (
+	// EventPieceSpawned is emitted when a new Tetrimino is placed in play.
+	EventPieceSpawned EventKind = iota
+	// EventPieceLocked is emitted when the Tetrimino in play is locked into the Matrix.
+	EventPieceLocked
+	// EventLinesCleared is emitted when a lock down removes one or more lines from the Matrix.
+	EventLinesCleared
+	// EventBackToBack is emitted when a line clear continues a Back-to-Back sequence and is awarded the bonus.
+	EventBackToBack
+	// EventLevelUp is emitted when the level increases.
+	EventLevelUp
+	// EventHold is emitted when the Tetrimino in play is placed in the hold slot.
+	EventHold
+	// EventGameOver is emitted once when the game ends.
+	EventGameOver
+	// EventGarbageReceived is emitted when queued garbage lines are inserted into the Matrix.
+	EventGarbageReceived
+	// EventPerfectClear is emitted when a line clear leaves the Matrix empty.
+	EventPerfectClear
+)
+
+var eventKindToStrMap = map[EventKind]string{
+	EventPieceSpawned:    "PieceSpawned",
+	EventPieceLocked:     "PieceLocked",
+	EventLinesCleared:    "LinesCleared",
+	EventBackToBack:      "BackToBack",
+	EventLevelUp:         "LevelUp",
+	EventHold:            "Hold",
+	EventGameOver:        "GameOver",
+	EventGarbageReceived: "GarbageReceived",
+	EventPerfectClear:    "PerfectClear",
+}
+
+func (k EventKind) String() string {
+	return eventKindToStrMap[k]
+}
+
+// GameOverReason describes why a Game ended.
+type GameOverReason int
+
+const (
+	// GameOverNone means the game has not ended.
+	GameOverNone GameOverReason = iota
+	// GameOverBlockOut means a new Tetrimino could not be placed because it overlapped the Matrix.
+	GameOverBlockOut
+	// GameOverLockOut means a Tetrimino locked down completely above the Skyline.
+	GameOverLockOut
+	// GameOverTopOut means Minos were pushed above the top of the buffer zone (eg. by garbage lines).
+	GameOverTopOut
+	// GameOverLimitReached means the configured maximum level or lines was reached.
+	GameOverLimitReached
+	// GameOverEnded means the game was ended explicitly using Game.EndGame (eg. a time limit or the player quitting).
+	GameOverEnded
+)
+
+var gameOverReasonToStrMap = map[GameOverReason]string{
+	GameOverNone:         "None",
+	GameOverBlockOut:     "Block Out",
+	GameOverLockOut:      "Lock Out",
+	GameOverTopOut:       "Top Out",
+	GameOverLimitReached: "Limit Reached",
+	GameOverEnded:        "Ended",
+}
+
+func (r GameOverReason) String() string {
+	return gameOverReasonToStrMap[r]
+}
+
+// Event describes something notable that happened during a Game.
+type Event struct {
+	Kind EventKind
+	// Time is the game time at which the Event occurred. This is only set for Events returned by an Engine.
+	Time time.Duration
+	// Tetrimino is the Value of the Tetrimino involved.
+	// This is set for EventPieceSpawned, EventPieceLocked and EventHold.
+	Tetrimino byte
+	// Action is the scoring Action of the lock down, or the Perfect Clear bonus for EventPerfectClear
+	// (see tetris.PerfectClearBonus). This is set for EventPieceLocked, EventLinesCleared, EventBackToBack and
+	// EventPerfectClear.
+	Action tetris.Action
+	// Lines is the number of rows removed from the Matrix for EventLinesCleared,
+	// or the number of garbage lines inserted for EventGarbageReceived.
+	Lines int
+	// Level is the new level. This is set for EventLevelUp.
+	Level int
+	// Reason is why the game ended. This is set for EventGameOver.
+	Reason GameOverReason
+}
+
+// EventHandler is called synchronously with each Event emitted by a Game.
+// Handlers must not perform operations on the Game which emitted the Event.
+type EventHandler func(Event)
+
+// WithEventHandler registers an EventHandler when creating a Game.
+// This ensures the handler receives the EventPieceSpawned of the first Tetrimino.
+func WithEventHandler(h EventHandler) func(*Game) {
+	return func(g *Game) {
+		g.eventHandlers = append(g.eventHandlers, h)
+	}
+}
+
+// Subscribe registers an EventHandler which will be called with every subsequent Event.
+func (g *Game) Subscribe(h EventHandler) {
+	g.eventHandlers = append(g.eventHandlers, h)
+}
+
+// emit passes the Event to each registered EventHandler.
+func (g *Game) emit(e Event) {
+	for _, h := range g.eventHandlers {
+		h(e)
+	}
+}
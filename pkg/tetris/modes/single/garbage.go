@@ -0,0 +1,95 @@
+package single
+
+import (
+	"fmt"
+
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris"
+)
+
+// garbageAttack is a number of garbage lines received from an opponent which share the same hole column.
+type garbageAttack struct {
+	lines int
+	hole  int
+}
+
+// AddGarbage queues garbage lines to be inserted at the bottom of the Matrix, with a gap in the hole column.
+// Queued garbage is inserted when the next Tetrimino locks down without clearing any lines.
+func (g *Game) AddGarbage(lines, hole int) {
+	if lines <= 0 {
+		return
+	}
+	g.garbage = append(g.garbage, garbageAttack{lines: lines, hole: hole})
+}
+
+// AddRandomGarbage queues garbage lines to be inserted at the bottom of the Matrix in the same way as AddGarbage,
+// except that each line has a hole in a random column (see tetris.RandomGarbageHole).
+func (g *Game) AddRandomGarbage(lines int) {
+	prev := g.matrix.GetGarbageHole(g.matrix.GetHeight() - 1)
+	if len(g.garbage) > 0 {
+		prev = g.garbage[len(g.garbage)-1].hole
+	}
+	for range lines {
+		prev = tetris.RandomGarbageHole(g.rand, g.matrix.GetWidth(), prev)
+		g.AddGarbage(1, prev)
+	}
+}
+
+// CancelGarbage removes up to the given number of queued garbage lines, oldest first.
+// It returns the number of lines which were not cancelled (ie. the lines left to send to the opponent).
+func (g *Game) CancelGarbage(lines int) int {
+	for lines > 0 && len(g.garbage) > 0 {
+		cancelled := min(lines, g.garbage[0].lines)
+		lines -= cancelled
+		g.garbage[0].lines -= cancelled
+		if g.garbage[0].lines == 0 {
+			g.garbage = g.garbage[1:]
+		}
+	}
+	return lines
+}
+
+// GetPendingGarbage returns the number of garbage lines queued to be inserted into the Matrix.
+func (g *Game) GetPendingGarbage() int {
+	var lines int
+	for _, attack := range g.garbage {
+		lines += attack.lines
+	}
+	return lines
+}
+
+// insertGarbage inserts all queued garbage into the Matrix.
+// If any Minos are pushed out of the Matrix the game ends with GameOverTopOut and true is returned.
+func (g *Game) insertGarbage() (bool, error) {
+	if len(g.garbage) == 0 {
+		return false, nil
+	}
+
+	var lines int
+	var toppedOut bool
+	for _, attack := range g.garbage {
+		out, err := g.matrix.AddGarbage(attack.lines, attack.hole)
+		if err != nil {
+			return false, fmt.Errorf("adding garbage: %w", err)
+		}
+		toppedOut = toppedOut || out
+		lines += attack.lines
+	}
+	g.garbage = nil
+	g.emit(Event{Kind: EventGarbageReceived, Lines: lines})
+
+	if toppedOut {
+		g.setGameOver(GameOverTopOut)
+		return true, nil
+	}
+	return false, nil
+}
+
+// GetGarbageRemaining returns the number of garbage lines in the Matrix, plus those queued to be inserted.
+func (g *Game) GetGarbageRemaining() int {
+	return g.matrix.CountGarbageLines() + g.GetPendingGarbage()
+}
+
+// isGarbageCleared returns true if the game ends once every garbage line is cleared and none remain.
+func (g *Game) isGarbageCleared() bool {
+	return g.endOnGarbageCleared && g.GetGarbageRemaining() == 0
+}
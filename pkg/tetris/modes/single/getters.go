@@ -10,8 +10,18 @@ func (g *Game) IsGameOver() bool {
 	return g.gameOver
 }
 
+// GetGameOverReason returns why the game ended, or GameOverNone if it has not ended.
+func (g *Game) GetGameOverReason() GameOverReason {
+	return g.gameOverReason
+}
+
+// GetVisibleMatrix returns the visible part of the Matrix, including the Tetrimino in play and the ghost
+// Tetrimino unless the next Tetrimino is waiting to spawn.
 func (g *Game) GetVisibleMatrix() (tetris.Matrix, error) {
 	matrix := g.matrix.DeepCopy()
+	if g.isSpawnPending {
+		return matrix.GetVisible(), nil
+	}
 
 	if g.ghostTet != nil {
 		err := matrix.AddTetrimino(g.ghostTet)
@@ -35,11 +45,19 @@ func (g *Game) GetHoldTetrimino() *tetris.Tetrimino {
 	return g.holdQueue
 }
 
+// GetTotalScore returns the score, which is calculated by tetris.Master in Master.
 func (g *Game) GetTotalScore() int {
+	if g.master != nil {
+		return g.master.Score()
+	}
 	return g.scoring.Total()
 }
 
+// GetLevel returns the level, which is between 0 and tetris.MasterMaxLevel in Master.
 func (g *Game) GetLevel() int {
+	if g.master != nil {
+		return g.master.Level()
+	}
 	return g.scoring.Level()
 }
 
@@ -47,6 +65,37 @@ func (g *Game) GetLinesCleared() int {
 	return g.scoring.Lines()
 }
 
+// GetCombo returns the number of consecutive lock downs which cleared lines, minus one.
+// A value of -1 means there is no combo.
+func (g *Game) GetCombo() int {
+	return g.scoring.Combo()
+}
+
+// GetMaxCombo returns the highest combo reached during the game.
+func (g *Game) GetMaxCombo() int {
+	return g.scoring.MaxCombo()
+}
+
 func (g *Game) GetDefaultFallInterval() time.Duration {
 	return g.fall.DefaultInterval
 }
+
+// GetMatrix returns a copy of the Matrix, excluding the Tetrimino in play and the ghost Tetrimino.
+func (g *Game) GetMatrix() tetris.Matrix {
+	return *g.matrix.DeepCopy()
+}
+
+// GetTetriminoInPlay returns a copy of the Tetrimino in play.
+func (g *Game) GetTetriminoInPlay() *tetris.Tetrimino {
+	return g.tetInPlay.DeepCopy()
+}
+
+// IsSoftDropping returns true if Soft Drop is on.
+func (g *Game) IsSoftDropping() bool {
+	return g.fall.IsSoftDrop
+}
+
+// CanHold returns true if the Tetrimino in play can be held.
+func (g *Game) CanHold() bool {
+	return g.canHold
+}
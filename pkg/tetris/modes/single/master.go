@@ -0,0 +1,84 @@
+package single
+
+import (
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris"
+)
+
+// IsMaster returns true if the game is Master (see Input.Master).
+func (g *Game) IsMaster() bool {
+	return g.master != nil
+}
+
+// GetMasterGrade returns the grade which has been reached in Master, or the lowest grade if this is not Master.
+func (g *Game) GetMasterGrade() tetris.MasterGrade {
+	if g.master == nil {
+		return tetris.MasterGrade9
+	}
+	return g.master.Grade()
+}
+
+// GetSectionStop returns the level which spawning Tetriminos cannot advance past in Master (see
+// tetris.Master.SectionStop), or 0 if this is not Master.
+func (g *Game) GetSectionStop() int {
+	if g.master == nil {
+		return 0
+	}
+	return g.master.SectionStop()
+}
+
+// IsSpawnPending returns true if the next Tetrimino is waiting to spawn, in which case it is not yet in play.
+func (g *Game) IsSpawnPending() bool {
+	return g.isSpawnPending
+}
+
+// lockMaster records the Tetrimino in play locking down in Master, emitting EventLevelUp if the lines cleared
+// reached a new section.
+func (g *Game) lockMaster(action tetris.Action, perfectClear bool) {
+	prevSectionStop := g.master.SectionStop()
+	g.master.Lock(action.GetRowsCleared(), perfectClear)
+	if g.master.SectionStop() > prevSectionStop {
+		g.emit(Event{Kind: EventLevelUp, Level: g.master.Level()})
+	}
+}
+
+// spawnMaster records the next Tetrimino spawning in Master and uses the Lock Down delay of the new level.
+func (g *Game) spawnMaster() {
+	if g.master == nil {
+		return
+	}
+	g.master.Spawn()
+	g.lockDown.SetDelay(g.master.Section().LockDelay)
+}
+
+// isMasterComplete returns true if this is Master and the final level has been reached.
+func (g *Game) isMasterComplete() bool {
+	return g.master != nil && g.master.IsComplete()
+}
+
+// getSpawnDelay returns how long the next Tetrimino waits before spawning after the given action in Master.
+// This is the ARE of the current section, plus the line clear delay if any lines were cleared.
+func (g *Game) getSpawnDelay(action tetris.Action) time.Duration {
+	if g.master == nil {
+		return 0
+	}
+	section := g.master.Section()
+	if action.GetRowsCleared() > 0 {
+		return section.ARE + section.LineClearDelay
+	}
+	return section.ARE
+}
+
+// applyInstantGravity drops the Tetrimino in play to the lowest position it can reach if the gravity is 20G,
+// starting the Lock Down timer since it is then resting on a surface.
+func (g *Game) applyInstantGravity() {
+	if !g.fall.IsInstant {
+		return
+	}
+	for g.tetInPlay.MoveDown(g.matrix) {
+		g.rotationPoint = 0
+	}
+	g.lockDown.Descend(g.tetInPlayLowestRow())
+	g.lockDown.Start()
+}
@@ -14,14 +14,30 @@ import (
 type Game struct {
 	matrix           tetris.Matrix     // The Matrix of cells on which the game is played
 	nextQueue        *tetris.NextQueue // The queue of upcoming Tetriminos
+	source           *rand.PCG         // The random source of the NextQueue, if it can be saved
 	tetInPlay        *tetris.Tetrimino // The current Tetrimino in play
 	ghostTet         *tetris.Tetrimino // The ghost Tetrimino
 	holdQueue        *tetris.Tetrimino // The Tetrimino that is being held
+	rotationPoint    int               // The SRS rotation point of the last movement, 0 if it was not a rotation
 	canHold          bool              // Whether the player can hold the current Tetrimino
 	gameOver         bool              // Whether the game is over
 	softDropStartRow int               // Records where the user began soft drop
 	scoring          *tetris.Scoring   // The scoring system
 	fall             *tetris.Fall      // The system for calculating the fall speed
+	lockDown         *tetris.LockDown  // The system for timing when the Tetrimino in play locks down
+
+	gameOverReason GameOverReason  // Why the game ended
+	eventHandlers  []EventHandler  // The handlers which are called with each emitted Event
+	garbage        []garbageAttack // The garbage lines waiting to be inserted into the Matrix
+	rand           *rand.Rand      // The random source used to choose the hole column of garbage lines
+	stats          Stats           // The counts of what the player has achieved
+
+	endOnGarbageCleared bool // Whether the game ends once every garbage line has been cleared
+	lineGoal            int  // The number of rows to clear before the game ends, or 0 if there is no goal
+
+	master         *tetris.Master // The level, score and grade of Master, or nil if this is not Master
+	isSpawnPending bool           // Whether the Tetrimino in play is waiting to spawn (see spawnDelay)
+	spawnDelay     time.Duration  // The time remaining before the Tetrimino in play spawns (known as ARE)
 }
 
 type Input struct {
@@ -32,20 +48,100 @@ type Input struct {
 
 	MaxLines      int  // The maximum number of lines to clear before the game ends. 0 means no limit.
 	EndOnMaxLines bool // Whether the game should end when the maximum number of lines is cleared.
-
-	GhostEnabled bool       // Whether the ghost Tetrimino should be displayed.
-	Rand         *rand.Rand // The random source to use for Tetrimino generation.
+	// LineGoal is the number of rows to clear before the game ends. 0 means no goal.
+	// Unlike MaxLines, which counts variable goal lines, every row counts once regardless of how it was cleared.
+	LineGoal int
+
+	GarbageLines        int  // The number of garbage lines to start with. Must be less than the visible height.
+	EndOnGarbageCleared bool // Whether the game should end once every garbage line (including queued ones) is cleared.
+
+	// Master is whether to play Master, where tetris.Master replaces the level and scoring system.
+	// Gravity follows tetris.MasterGravity (reaching 20G), the next Tetrimino spawns after a delay, and
+	// LockDownClassic is always used. The level options are ignored.
+	Master bool
+	// MasterSections is the timing of each section of Master. nil uses tetris.DefaultMasterSections.
+	MasterSections []tetris.MasterSection
+
+	LockDownMode   tetris.LockDownMode // How movement affects the Lock Down timer.
+	SoftDropFactor int                 // How many times faster Soft Drop is. 0 uses tetris.DefaultSoftDropFactor.
+
+	Width  int // The number of columns in the Matrix. 0 uses tetris.DefaultMatrixWidth.
+	Height int // The number of visible rows in the Matrix. 0 uses tetris.DefaultMatrixHeight.
+
+	GhostEnabled bool              // Whether the ghost Tetrimino should be displayed.
+	Rand         *rand.Rand        // The random source to use for Tetrimino generation.
+	Source       *rand.PCG         // The random source to use instead of Rand, which allows the Game to be saved.
+	Randomizer   tetris.Randomizer // The order of Tetrimino generation. nil uses a 7-bag.
 }
 
-func NewGame(in *Input) (*Game, error) {
-	matrix, err := tetris.NewMatrix(40, 10)
+func NewGame(in *Input, opts ...func(*Game)) (*Game, error) {
+	width, height := in.Width, in.Height
+	if width == 0 {
+		width = tetris.DefaultMatrixWidth
+	}
+	if height == 0 {
+		height = tetris.DefaultMatrixHeight
+	}
+	matrix, err := tetris.NewMatrix(height*2, width)
 	if err != nil {
 		return nil, err
 	}
-	nq := tetris.NewNextQueue(matrix.GetSkyline(), tetris.WithRandSource(in.Rand))
+	rng := in.Rand
+	if in.Source != nil {
+		rng = rand.New(in.Source)
+	}
+	if rng == nil {
+		//nolint:gosec // This random source is not for any security-related tasks.
+		rng = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+	nqOpts := []func(*tetris.NextQueue){
+		tetris.WithRandSource(rng),
+		tetris.WithMatrixWidth(matrix.GetWidth()),
+	}
+	if in.Randomizer != nil {
+		nqOpts = append(nqOpts, tetris.WithRandomizer(in.Randomizer))
+	}
+	nq := tetris.NewNextQueue(matrix.GetSkyline(), nqOpts...)
+
+	if in.LineGoal < 0 {
+		return nil, fmt.Errorf("invalid line goal '%d'", in.LineGoal)
+	}
+	if in.GarbageLines < 0 || in.GarbageLines >= height {
+		return nil, fmt.Errorf("garbage lines must be between 0 and %d, got %d", height-1, in.GarbageLines)
+	}
+	if in.EndOnGarbageCleared && in.GarbageLines == 0 {
+		return nil, errors.New("the game cannot end on garbage cleared without any garbage lines")
+	}
+	if _, err = matrix.AddRandomGarbage(in.GarbageLines, rng); err != nil {
+		return nil, fmt.Errorf("adding garbage lines: %w", err)
+	}
+
+	level, increaseLevel := in.Level, in.IncreaseLevel
+	fall := tetris.NewFall(in.Level, tetris.WithSoftDropFactor(in.SoftDropFactor))
+	lockDown := tetris.NewLockDown(in.LockDownMode)
+	var master *tetris.Master
+	if in.Master {
+		sections := in.MasterSections
+		if sections == nil {
+			sections = tetris.DefaultMasterSections
+		}
+		master, err = tetris.NewMaster(sections)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create master: %w", err)
+		}
+
+		// The scoring system only counts lines and combos in Master, so it stays at the first level.
+		level, increaseLevel = 1, false
+		fall = tetris.NewFall(master.Level(),
+			tetris.WithSoftDropFactor(in.SoftDropFactor),
+			tetris.WithMasterGravity(),
+		)
+		lockDown = tetris.NewLockDown(tetris.LockDownClassic)
+		lockDown.SetDelay(master.Section().LockDelay)
+	}
 
 	scoring, err := tetris.NewScoring(
-		in.Level, in.MaxLevel, in.IncreaseLevel, in.EndOnMaxLevel, in.MaxLines, in.EndOnMaxLines,
+		level, in.MaxLevel, increaseLevel, in.EndOnMaxLevel, in.MaxLines, in.EndOnMaxLines,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create scoring system: %w", err)
@@ -54,12 +150,23 @@ func NewGame(in *Input) (*Game, error) {
 	g := &Game{
 		matrix:           matrix,
 		nextQueue:        nq,
+		source:           in.Source,
 		tetInPlay:        nq.Next(),
 		holdQueue:        tetris.GetEmptyTetrimino(),
 		gameOver:         false,
 		softDropStartRow: matrix.GetHeight(),
 		scoring:          scoring,
-		fall:             tetris.NewFall(in.Level),
+		fall:             fall,
+		lockDown:         lockDown,
+		rand:             rng,
+
+		endOnGarbageCleared: in.EndOnGarbageCleared,
+		lineGoal:            in.LineGoal,
+		master:              master,
+	}
+
+	for _, opt := range opts {
+		opt(g)
 	}
 
 	if in.GhostEnabled {
@@ -74,21 +181,46 @@ func NewGame(in *Input) (*Game, error) {
 	return g, nil
 }
 
-func (g *Game) MoveLeft() {
-	_ = g.tetInPlay.MoveLeft(g.matrix)
+// MoveLeft moves the Tetrimino in play one cell to the left, returning true if it moved.
+func (g *Game) MoveLeft() bool {
+	if g.isSpawnPending {
+		return false
+	}
+	moved := g.tetInPlay.MoveLeft(g.matrix)
+	if moved {
+		g.rotationPoint = 0
+		g.onTetInPlayMoved()
+	}
 	g.updateGhost()
+	return moved
 }
 
-func (g *Game) MoveRight() {
-	_ = g.tetInPlay.MoveRight(g.matrix)
+// MoveRight moves the Tetrimino in play one cell to the right, returning true if it moved.
+func (g *Game) MoveRight() bool {
+	if g.isSpawnPending {
+		return false
+	}
+	moved := g.tetInPlay.MoveRight(g.matrix)
+	if moved {
+		g.rotationPoint = 0
+		g.onTetInPlayMoved()
+	}
 	g.updateGhost()
+	return moved
 }
 
 func (g *Game) Rotate(clockwise bool) error {
-	err := g.tetInPlay.Rotate(g.matrix, clockwise)
+	if g.isSpawnPending {
+		return nil
+	}
+	rotationPoint, err := g.tetInPlay.RotateWithPoint(g.matrix, clockwise)
 	if err != nil {
 		return err
 	}
+	if rotationPoint > 0 {
+		g.rotationPoint = rotationPoint
+		g.onTetInPlayMoved()
+	}
 
 	g.updateGhost()
 	return nil
@@ -97,13 +229,15 @@ func (g *Game) Rotate(clockwise bool) error {
 // Hold will swap the current Tetrimino with the hold Tetrimino.
 // If the hold Tetrimino is empty, the current Tetrimino is placed in the hold slot and
 // the setupNewTetInPlay Tetrimino is drawn.
-// If not allowed to hold, no action is taken.
+// If not allowed to hold, or the Tetrimino in play is waiting to spawn, no action is taken.
 // If true is returned the game is over.
 func (g *Game) Hold() (bool, error) {
-	if !g.canHold {
+	if !g.canHold || g.isSpawnPending {
 		return false, nil
 	}
 
+	g.emit(Event{Kind: EventHold, Tetrimino: g.tetInPlay.Value})
+
 	// Swap the current tetrimino with the hold tetrimino
 	if g.holdQueue.Value == 0 {
 		g.holdQueue = g.tetInPlay
@@ -124,7 +258,7 @@ func (g *Game) Hold() (bool, error) {
 		return false, err
 	}
 	g.holdQueue = t.DeepCopy()
-	g.holdQueue.Position.Y += g.matrix.GetSkyline()
+	g.holdQueue.Position = g.matrix.GetStartingPosition(g.holdQueue)
 
 	g.canHold = false
 	return false, nil
@@ -132,61 +266,66 @@ func (g *Game) Hold() (bool, error) {
 
 // TickLower moves the current Tetrimino down one row.
 // This should be triggered at a regular interval calculated using Fall.
-// If the Tetrimino cannot move down, it is locked in place and true is returned.
-// Game Over is updated if needed.
+// If the Tetrimino cannot move down the Lock Down timer is started. Once the timer has expired
+// (see UpdateLockDown) the Tetrimino is locked in place on the next call.
+// Whilst the next Tetrimino is waiting to spawn it is instead spawned once the delay has passed.
+// If true is returned the game is over.
 func (g *Game) TickLower() (bool, error) {
-	lockedDown, err := g.lowerTetInPlay()
-	if err != nil {
-		return false, fmt.Errorf("failed to lower tetrimino: %w", err)
-	}
-	if !lockedDown {
-		return false, nil
-	}
-	if g.gameOver {
-		return true, nil
+	if g.isSpawnPending {
+		return g.spawnPendingTetInPlay(), nil
 	}
 
-	if g.fall.IsSoftDrop {
-		linesCleared := g.tetInPlay.Position.Y - g.softDropStartRow
-		if linesCleared > 0 {
-			g.scoring.AddSoftDrop(linesCleared)
+	if g.tetInPlay.MoveDown(g.matrix) {
+		g.rotationPoint = 0
+		g.lockDown.Descend(g.tetInPlayLowestRow())
+		if g.isTetInPlayOnSurface() {
+			g.lockDown.Start()
 		}
+		return false, nil
 	}
 
-	g.tetInPlay = g.nextQueue.Next()
-	gameOver := g.setupNewTetInPlay()
-	if gameOver {
-		g.gameOver = gameOver
+	g.lockDown.Start()
+	if !g.lockDown.IsExpired() {
+		return false, nil
 	}
 
+	g.addSoftDropPoints()
+	gameOver, err := g.lockDownTetInPlay()
+	if err != nil {
+		return false, fmt.Errorf("failed to lock down tetrimino: %w", err)
+	}
 	return gameOver, nil
 }
 
+// UpdateLockDown advances the Lock Down timer by the elapsed duration.
+// The timer only runs whilst the Tetrimino in play is resting on a surface.
+// This should be called before TickLower, which performs the Lock Down once the timer has expired.
+// Whilst the next Tetrimino is waiting to spawn this instead advances the spawn delay.
+func (g *Game) UpdateLockDown(elapsed time.Duration) {
+	if g.isSpawnPending {
+		g.spawnDelay -= elapsed
+		return
+	}
+	g.lockDown.Advance(elapsed)
+}
+
 func (g *Game) HardDrop() (bool, error) {
+	if g.isSpawnPending {
+		return false, nil
+	}
 	startRow := g.tetInPlay.Position.Y
 
-	for {
-		lockedDown, err := g.lowerTetInPlay()
-		if err != nil {
-			return false, fmt.Errorf("failed to lower tetrimino (hard drop): %w", err)
-		}
-		if lockedDown {
-			break
-		}
-	}
-	if g.gameOver {
-		return true, nil
+	for g.tetInPlay.MoveDown(g.matrix) {
+		g.rotationPoint = 0
 	}
 
 	linesCleared := g.tetInPlay.Position.Y - startRow
 	g.scoring.AddHardDrop(linesCleared)
 
-	g.tetInPlay = g.nextQueue.Next()
-	gameOver := g.setupNewTetInPlay()
-	if gameOver {
-		g.gameOver = gameOver
+	gameOver, err := g.lockDownTetInPlay()
+	if err != nil {
+		return false, fmt.Errorf("failed to lock down tetrimino (hard drop): %w", err)
 	}
-
 	return gameOver, nil
 }
 
@@ -206,75 +345,207 @@ func (g *Game) ToggleSoftDrop() {
 	g.softDropStartRow = g.matrix.GetSkyline()
 }
 
+// SetSoftDrop turns Soft Drop on or off, returning true if the state changed.
+// This allows Soft Drop to last whilst a key is held, rather than toggling it.
+func (g *Game) SetSoftDrop(enabled bool) bool {
+	if g.fall.IsSoftDrop == enabled {
+		return false
+	}
+	g.ToggleSoftDrop()
+	return true
+}
+
 // GetFallInterval returns the time interval for the Fall system.
+// Whilst the Lock Down timer is running this will not exceed the time remaining before Lock Down.
+// Whilst the next Tetrimino is waiting to spawn it is the time remaining before it spawns.
 func (g *Game) GetFallInterval() time.Duration {
+	if g.isSpawnPending {
+		return max(g.spawnDelay, 0)
+	}
+
+	interval := g.fall.DefaultInterval
 	if g.fall.IsSoftDrop {
-		return g.fall.SoftDropInterval
+		interval = g.fall.SoftDropInterval
+	}
+
+	if g.lockDown.IsActive() {
+		interval = min(interval, g.lockDown.Remaining())
 	}
-	return g.fall.DefaultInterval
+	return interval
 }
 
 // EndGame sets Game.gameOver to true.
 func (g *Game) EndGame() {
-	g.gameOver = true
+	g.setGameOver(GameOverEnded)
 }
 
-// lowerTetInPlay moves the current Tetrimino down one row if possible.
-// If it cannot be moved down this will instead remove completed lines, calculate scores and
-// fall speed and return true (representing a Lock Down).
-// If the max score is reached and the game is configured to end on max level,
-// the Game.gameOver value will be set to true.
-func (g *Game) lowerTetInPlay() (bool, error) {
-	if g.tetInPlay.MoveDown(g.matrix) {
-		return false, nil
+// setGameOver ends the game for the given reason, emitting EventGameOver if it had not already ended.
+func (g *Game) setGameOver(reason GameOverReason) {
+	if g.gameOver {
+		return
 	}
+	g.gameOver = true
+	g.gameOverReason = reason
+	g.emit(Event{Kind: EventGameOver, Reason: reason})
+}
 
+// lockDownTetInPlay locks the current Tetrimino into the Matrix, removes completed lines, and calculates
+// the score and fall speed. The next Tetrimino is then setup as the Tetrimino in play, unless it must first wait
+// to spawn (see spawnPendingTetInPlay).
+// If no lines were cleared any queued garbage is inserted (see AddGarbage).
+// If the game is configured to end on max level/lines or on every garbage line being cleared and it is reached,
+// Master is complete, garbage tops out the Matrix, or the next Tetrimino cannot be placed, Game.gameOver is set
+// and true is returned.
+func (g *Game) lockDownTetInPlay() (bool, error) {
 	err := g.matrix.AddTetrimino(g.tetInPlay)
 	if err != nil {
 		return false, err
 	}
 
-	action := g.matrix.RemoveCompletedLines(g.tetInPlay)
+	tSpin := g.matrix.DetectTSpin(g.tetInPlay, g.rotationPoint)
+	action := tetris.ApplyTSpin(g.matrix.RemoveCompletedLines(g.tetInPlay), tSpin)
+	perfectClear := action.GetRowsCleared() > 0 && g.matrix.IsEmpty()
 	if !action.IsValid() {
 		return false, fmt.Errorf("invalid action received %q", action.String())
 	}
 
-	gameOver, err := g.scoring.ProcessAction(action)
+	g.updateStats(action, tSpin)
+	g.emit(Event{Kind: EventPieceLocked, Tetrimino: g.tetInPlay.Value, Action: action})
+	if lines := action.GetRowsCleared(); lines > 0 {
+		g.emit(Event{Kind: EventLinesCleared, Action: action, Lines: lines})
+	}
+	if perfectClear {
+		g.emit(Event{Kind: EventPerfectClear, Action: tetris.PerfectClearBonus(action, g.scoring.IsBackToBack())})
+	}
+
+	startsBackToBack, err := action.StartsBackToBack()
+	if err != nil {
+		return false, err
+	}
+	isBackToBack := startsBackToBack && g.scoring.IsBackToBack()
+	prevLevel := g.scoring.Level()
+
+	processAction := g.scoring.ProcessAction
+	if perfectClear {
+		processAction = g.scoring.ProcessPerfectClear
+	}
+	gameOver, err := processAction(action)
 	if err != nil {
 		return false, fmt.Errorf("failed to process action: %w", err)
 	}
-	if gameOver {
-		g.gameOver = true
+	if isBackToBack {
+		g.emit(Event{Kind: EventBackToBack, Action: action})
+	}
+	if g.master != nil {
+		g.lockMaster(action, perfectClear)
+	} else if level := g.scoring.Level(); level > prevLevel {
+		g.emit(Event{Kind: EventLevelUp, Level: level})
+	}
+	if gameOver || g.isLineGoalReached() || g.isGarbageCleared() || g.isMasterComplete() {
+		g.setGameOver(GameOverLimitReached)
+		return true, nil
 	}
 
-	g.fall.CalculateFallSpeeds(g.scoring.Level())
+	if action.GetRowsCleared() == 0 {
+		gameOver, err = g.insertGarbage()
+		if err != nil {
+			return false, err
+		}
+		if gameOver {
+			return true, nil
+		}
+	}
+
+	g.tetInPlay = g.nextQueue.Next()
+	if spawnDelay := g.getSpawnDelay(action); spawnDelay > 0 {
+		g.isSpawnPending = true
+		g.spawnDelay = spawnDelay
+		return false, nil
+	}
+	return g.spawnNextTetInPlay(), nil
+}
+
+// spawnPendingTetInPlay spawns the Tetrimino in play once it has waited for the spawn delay.
+// If true is returned the game is over.
+func (g *Game) spawnPendingTetInPlay() bool {
+	if g.spawnDelay > 0 {
+		return false
+	}
+	g.isSpawnPending = false
+	g.spawnDelay = 0
+	return g.spawnNextTetInPlay()
+}
+
+// spawnNextTetInPlay updates the level and fall speed for the next Tetrimino, then sets it up as the
+// Tetrimino in play (see setupNewTetInPlay). If true is returned the game is over.
+func (g *Game) spawnNextTetInPlay() bool {
+	g.spawnMaster()
+	g.fall.CalculateFallSpeeds(g.GetLevel())
+	return g.setupNewTetInPlay()
+}
+
+// addSoftDropPoints adds the points for the rows travelled whilst soft dropping, if applicable.
+func (g *Game) addSoftDropPoints() {
+	if !g.fall.IsSoftDrop {
+		return
+	}
+	linesCleared := g.tetInPlay.Position.Y - g.softDropStartRow
+	if linesCleared > 0 {
+		g.scoring.AddSoftDrop(linesCleared)
+	}
+}
+
+// onTetInPlayMoved updates the Lock Down timer after a successful movement or rotation.
+// With 20G gravity the Tetrimino then immediately drops to the lowest position it can reach.
+func (g *Game) onTetInPlayMoved() {
+	g.lockDown.Descend(g.tetInPlayLowestRow())
+	if g.isTetInPlayOnSurface() {
+		g.lockDown.Move()
+	} else {
+		g.lockDown.Stop()
+	}
+	g.applyInstantGravity()
+}
+
+// isTetInPlayOnSurface returns true if the Tetrimino in play cannot move down.
+func (g *Game) isTetInPlayOnSurface() bool {
+	return !g.tetInPlay.DeepCopy().MoveDown(g.matrix)
+}
 
-	return true, nil
+// tetInPlayLowestRow returns the row below the bottom of the Tetrimino in play.
+func (g *Game) tetInPlayLowestRow() int {
+	return g.tetInPlay.Position.Y + len(g.tetInPlay.Cells)
 }
 
 // setupNewTetInPlay will do the following setup for the new Tetrimino in play:
-//   - If possible, move down one row into the visible Matrix.
+//   - If possible, move down one row into the visible Matrix (or to the lowest position it can reach with 20G).
 //   - Check for Lock Out & Block Out game over conditions.
 //   - Reset Game.softDropStartRow if currently Soft Dropping.
 //   - Set Game.canHold to true.
+//   - Reset Game.rotationPoint since the new Tetrimino has not been rotated.
+//   - Reset the Lock Down timer.
 //
 // It does not modify Game.tetInPlay. If true is returned the game is over.
 func (g *Game) setupNewTetInPlay() bool {
 	// Block Out
 	if !g.tetInPlay.IsValid(g.matrix, false) {
-		g.gameOver = true
+		g.setGameOver(GameOverBlockOut)
 		return true
 	}
 
 	if !g.tetInPlay.MoveDown(g.matrix) {
 		// Lock Out
 		if g.tetInPlay.IsAboveSkyline(g.matrix.GetSkyline()) {
-			g.gameOver = true
+			g.setGameOver(GameOverLockOut)
 			return true
 		}
 	}
 
 	g.canHold = true
+	g.rotationPoint = 0
+	g.lockDown.Reset(g.tetInPlayLowestRow())
+	g.applyInstantGravity()
+	g.emit(Event{Kind: EventPieceSpawned, Tetrimino: g.tetInPlay.Value})
 
 	if g.fall.IsSoftDrop {
 		g.softDropStartRow = g.tetInPlay.Position.Y
@@ -7,6 +7,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris"
 )
 
 func TestToggleSoftDrop(t *testing.T) {
@@ -70,3 +72,591 @@ func TestToggleSoftDrop(t *testing.T) {
 		})
 	}
 }
+
+func TestSetSoftDrop(t *testing.T) {
+	tests := map[string]struct {
+		initialSoftDrop bool
+		enabled         bool
+		wantChanged     bool
+	}{
+		"Enable when off": {
+			initialSoftDrop: false,
+			enabled:         true,
+			wantChanged:     true,
+		},
+		"Enable when on": {
+			initialSoftDrop: true,
+			enabled:         true,
+			wantChanged:     false,
+		},
+		"Disable when on": {
+			initialSoftDrop: true,
+			enabled:         false,
+			wantChanged:     true,
+		},
+		"Disable when off": {
+			initialSoftDrop: false,
+			enabled:         false,
+			wantChanged:     false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			game, err := NewGame(&Input{
+				Level: 1,
+				Rand:  rand.New(rand.NewPCG(0, 0)),
+			})
+			require.NoError(t, err)
+			game.fall.IsSoftDrop = tt.initialSoftDrop
+
+			assert.Equal(t, tt.wantChanged, game.SetSoftDrop(tt.enabled))
+			assert.Equal(t, tt.enabled, game.IsSoftDropping())
+		})
+	}
+}
+
+func TestTickLower_TSpinDouble(t *testing.T) {
+	game, err := NewGame(&Input{
+		Level:        1,
+		GhostEnabled: false,
+		Rand:         rand.New(rand.NewPCG(0, 0)),
+	})
+	require.NoError(t, err)
+
+	// Build a T-Spin Double slot at the bottom of the matrix with an overhang on the left.
+	bottom := game.matrix.GetHeight() - 1
+	for col := range game.matrix[bottom] {
+		if col != 4 {
+			game.matrix[bottom][col] = 'X'
+		}
+		if col < 3 || col > 5 {
+			game.matrix[bottom-1][col] = 'X'
+		}
+	}
+	game.matrix[bottom-2][3] = 'X'
+
+	// Place a south-facing T Tetrimino in the slot as though it was rotated in.
+	tet, err := tetris.GetTetrimino('T')
+	require.NoError(t, err)
+	tet.Cells = [][]bool{
+		{true, true, true},
+		{false, true, false},
+	}
+	tet.CompassDirection = 2
+	tet.Position = tetris.Coordinate{X: 3, Y: bottom - 1}
+	game.tetInPlay = tet
+	game.rotationPoint = 1
+
+	_, err = game.TickLower()
+	require.NoError(t, err)
+	game.UpdateLockDown(tetris.DefaultLockDownDelay)
+	_, err = game.TickLower()
+	require.NoError(t, err)
+
+	assert.Equal(t, tetris.Actions.TSpinDouble.GetPoints(), game.GetTotalScore())
+	assert.Equal(t, Stats{Pieces: 1, Rows: 2, TSpins: 1}, game.GetStats())
+	// Only the overhang should remain, shifted down by the two cleared lines.
+	assert.Equal(t, []byte{0, 0, 0, 'X', 0, 0, 0, 0, 0, 0}, []byte(game.matrix[bottom]))
+}
+
+func TestTickLower_LockDown(t *testing.T) {
+	tt := map[string]struct {
+		mode       tetris.LockDownMode
+		moves      int
+		wantLocked bool
+	}{
+		"extended; moves reset timer": {
+			mode:       tetris.LockDownExtended,
+			moves:      3,
+			wantLocked: false,
+		},
+		"extended; max resets exceeded": {
+			mode:       tetris.LockDownExtended,
+			moves:      tetris.DefaultLockDownMaxResets + 1,
+			wantLocked: true,
+		},
+		"infinite; many moves": {
+			mode:       tetris.LockDownInfinite,
+			moves:      tetris.DefaultLockDownMaxResets + 1,
+			wantLocked: false,
+		},
+		"classic; moves do not reset timer": {
+			mode:       tetris.LockDownClassic,
+			moves:      3,
+			wantLocked: true,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			game, err := NewGame(&Input{
+				Level:        1,
+				LockDownMode: tc.mode,
+				Rand:         rand.New(rand.NewPCG(0, 0)),
+			})
+			require.NoError(t, err)
+
+			// Lower the Tetrimino onto the floor.
+			for !game.isTetInPlayOnSurface() {
+				_, err = game.TickLower()
+				require.NoError(t, err)
+			}
+			_, err = game.TickLower()
+			require.NoError(t, err)
+			tet := game.tetInPlay
+
+			// Slide back and forth, advancing the timer most of the way between each move.
+			for i := range tc.moves {
+				game.UpdateLockDown(tetris.DefaultLockDownDelay / 4)
+				if i%2 == 0 {
+					game.MoveLeft()
+				} else {
+					game.MoveRight()
+				}
+			}
+			game.UpdateLockDown(tetris.DefaultLockDownDelay * 3 / 4)
+
+			_, err = game.TickLower()
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantLocked, game.tetInPlay != tet)
+		})
+	}
+}
+
+func TestGame_Events(t *testing.T) {
+	var events []Event
+	game, err := NewGame(&Input{
+		Level:         1,
+		MaxLines:      1,
+		EndOnMaxLines: true,
+		Rand:          rand.New(rand.NewPCG(0, 0)),
+	}, WithEventHandler(func(e Event) {
+		events = append(events, e)
+	}))
+	require.NoError(t, err)
+
+	require.Len(t, events, 1)
+	assert.Equal(t, EventPieceSpawned, events[0].Kind)
+	assert.Equal(t, game.tetInPlay.Value, events[0].Tetrimino)
+
+	held := game.tetInPlay.Value
+	_, err = game.Hold()
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, Event{Kind: EventHold, Tetrimino: held}, events[1])
+	assert.Equal(t, EventPieceSpawned, events[2].Kind)
+
+	// Fill the bottom row, leaving a gap for a horizontal I Tetrimino.
+	// The Mino above the row prevents a Perfect Clear.
+	events = nil
+	bottom := game.matrix.GetHeight() - 1
+	for col := range game.matrix[bottom] {
+		if col < 3 || col > 6 {
+			game.matrix[bottom][col] = 'X'
+		}
+	}
+	game.matrix[bottom-1][0] = 'X'
+	tet, err := tetris.GetTetrimino('I')
+	require.NoError(t, err)
+	game.tetInPlay = tet.DeepCopy()
+	game.tetInPlay.Position.Y += game.matrix.GetSkyline()
+
+	_, err = game.HardDrop()
+	require.NoError(t, err)
+
+	kinds := make([]EventKind, len(events))
+	for i, e := range events {
+		kinds[i] = e.Kind
+	}
+	assert.Equal(t, []EventKind{EventPieceLocked, EventLinesCleared, EventGameOver}, kinds)
+	assert.Equal(t, GameOverLimitReached, events[2].Reason)
+	assert.Equal(t, GameOverLimitReached, game.GetGameOverReason())
+}
+
+func TestGame_PerfectClear(t *testing.T) {
+	var events []Event
+	game, err := NewGame(&Input{
+		Level: 1,
+		Rand:  rand.New(rand.NewPCG(0, 0)),
+	}, WithEventHandler(func(e Event) {
+		events = append(events, e)
+	}))
+	require.NoError(t, err)
+
+	// Fill the bottom row, leaving a gap for a horizontal I Tetrimino which clears the Matrix.
+	bottom := game.matrix.GetHeight() - 1
+	for col := range game.matrix[bottom] {
+		if col < 3 || col > 6 {
+			game.matrix[bottom][col] = 'X'
+		}
+	}
+	tet, err := tetris.GetTetrimino('I')
+	require.NoError(t, err)
+	game.tetInPlay = tet.DeepCopy()
+	game.tetInPlay.Position.Y += game.matrix.GetSkyline()
+	hardDropPoints := 2 * (bottom - game.tetInPlay.Position.Y)
+
+	events = nil
+	_, err = game.HardDrop()
+	require.NoError(t, err)
+
+	require.Len(t, events, 4)
+	assert.Equal(t, EventPerfectClear, events[2].Kind)
+	assert.Equal(t, tetris.Actions.SinglePerfectClear, events[2].Action)
+	assert.True(t, game.matrix.IsEmpty())
+	assert.Equal(t, 100+800+hardDropPoints, game.GetTotalScore())
+}
+
+func TestTickLower_TSpinPerfectClear(t *testing.T) {
+	var events []Event
+	game, err := NewGame(&Input{
+		Level: 1,
+		Rand:  rand.New(rand.NewPCG(0, 0)),
+	}, WithEventHandler(func(e Event) {
+		events = append(events, e)
+	}))
+	require.NoError(t, err)
+
+	// Fill the bottom two rows except for a T-Spin Double slot, which clears the Matrix.
+	bottom := game.matrix.GetHeight() - 1
+	for col := range game.matrix[bottom] {
+		if col < 3 || col > 5 {
+			game.matrix[bottom][col] = 'X'
+		}
+		if col != 4 {
+			game.matrix[bottom-1][col] = 'X'
+		}
+	}
+
+	// Place a north-facing T Tetrimino in the slot as though it was rotated in.
+	tet, err := tetris.GetTetrimino('T')
+	require.NoError(t, err)
+	game.tetInPlay = tet.DeepCopy()
+	game.tetInPlay.Position = tetris.Coordinate{X: 3, Y: bottom - 1}
+	game.rotationPoint = 1
+
+	events = nil
+	_, err = game.TickLower()
+	require.NoError(t, err)
+	game.UpdateLockDown(tetris.DefaultLockDownDelay)
+	_, err = game.TickLower()
+	require.NoError(t, err)
+
+	require.GreaterOrEqual(t, len(events), 3)
+	assert.Equal(t, EventPieceLocked, events[0].Kind)
+	assert.Equal(t, tetris.Actions.TSpinDouble, events[0].Action)
+	assert.Equal(t, EventPerfectClear, events[2].Kind)
+	assert.Equal(t, tetris.Actions.DoublePerfectClear, events[2].Action)
+	assert.True(t, game.matrix.IsEmpty())
+
+	// The Perfect Clear bonus is awarded on top of the T-Spin Double, which keeps its goal lines.
+	assert.Equal(t, tetris.Actions.TSpinDouble.GetPoints()+tetris.Actions.DoublePerfectClear.GetPoints(),
+		game.GetTotalScore())
+	assert.Equal(t, 12, game.GetLinesCleared())
+	assert.Equal(t, Stats{Pieces: 1, Rows: 2, TSpins: 1}, game.GetStats())
+}
+
+func TestGame_Stats(t *testing.T) {
+	game, err := NewGame(&Input{
+		Level: 1,
+		Rand:  rand.New(rand.NewPCG(0, 0)),
+	})
+	require.NoError(t, err)
+
+	// Fill the bottom four rows, leaving a well in the first column for a vertical I Tetrimino.
+	bottom := game.matrix.GetHeight() - 1
+	for row := bottom - 3; row <= bottom; row++ {
+		for col := 1; col < game.matrix.GetWidth(); col++ {
+			game.matrix[row][col] = 'X'
+		}
+	}
+	tet, err := tetris.GetTetrimino('I')
+	require.NoError(t, err)
+	tet.Cells = [][]bool{{true}, {true}, {true}, {true}}
+	tet.CompassDirection = 1
+	tet.Position = tetris.Coordinate{X: 0, Y: game.matrix.GetSkyline()}
+	game.tetInPlay = tet
+
+	_, err = game.HardDrop()
+	require.NoError(t, err)
+	_, err = game.HardDrop()
+	require.NoError(t, err)
+
+	assert.Equal(t, Stats{Pieces: 2, Rows: 4, Tetrises: 1}, game.GetStats())
+}
+
+func TestGame_LineGoal(t *testing.T) {
+	tt := map[string]struct {
+		lineGoal     int
+		wantGameOver bool
+		wantErr      bool
+	}{
+		"no goal":       {},
+		"reached":       {lineGoal: 4, wantGameOver: true},
+		"not reached":   {lineGoal: 5},
+		"negative goal": {lineGoal: -1, wantErr: true},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			game, err := NewGame(&Input{
+				Level:    1,
+				LineGoal: tc.lineGoal,
+				Rand:     rand.New(rand.NewPCG(0, 0)),
+			})
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			// A Tetris clears four rows, but is worth eight goal lines.
+			bottom := game.matrix.GetHeight() - 1
+			for row := bottom - 3; row <= bottom; row++ {
+				for col := 1; col < game.matrix.GetWidth(); col++ {
+					game.matrix[row][col] = 'X'
+				}
+			}
+			tet, err := tetris.GetTetrimino('I')
+			require.NoError(t, err)
+			tet.Cells = [][]bool{{true}, {true}, {true}, {true}}
+			tet.CompassDirection = 1
+			tet.Position = tetris.Coordinate{X: 0, Y: game.matrix.GetSkyline()}
+			game.tetInPlay = tet
+
+			gameOver, err := game.HardDrop()
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantGameOver, gameOver)
+			assert.Equal(t, 4, game.GetStats().Rows)
+			assert.Equal(t, 8, game.GetLinesCleared())
+			if tc.wantGameOver {
+				assert.Equal(t, GameOverLimitReached, game.GetGameOverReason())
+			}
+		})
+	}
+}
+
+func TestNewGame_MatrixSize(t *testing.T) {
+	tt := map[string]struct {
+		width      int
+		height     int
+		wantWidth  int
+		wantHeight int
+		wantErr    bool
+	}{
+		"default":       {wantWidth: 10, wantHeight: 20},
+		"narrow":        {width: 4, wantWidth: 4, wantHeight: 20},
+		"wide and tall": {width: 20, height: 30, wantWidth: 20, wantHeight: 30},
+		"too narrow":    {width: 3, wantErr: true},
+		"too tall":      {height: 41, wantErr: true},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			g, err := NewGame(&Input{
+				Level:  1,
+				Width:  tc.width,
+				Height: tc.height,
+				Rand:   rand.New(rand.NewPCG(1, 2)),
+			})
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			visible, err := g.GetVisibleMatrix()
+			require.NoError(t, err)
+			assert.Len(t, visible, tc.wantHeight)
+			assert.Len(t, visible[0], tc.wantWidth)
+
+			// Hard drop until the Matrix fills up to ensure every spawn position is within the Matrix.
+			for range 100 {
+				gameOver, err := g.HardDrop()
+				require.NoError(t, err)
+				if gameOver {
+					break
+				}
+				_, err = g.Hold()
+				require.NoError(t, err)
+			}
+			assert.True(t, g.IsGameOver())
+		})
+	}
+}
+
+func TestGame_Garbage(t *testing.T) {
+	var events []Event
+	game, err := NewGame(&Input{
+		Level: 1,
+		Rand:  rand.New(rand.NewPCG(0, 0)),
+	}, WithEventHandler(func(e Event) {
+		events = append(events, e)
+	}))
+	require.NoError(t, err)
+
+	game.AddGarbage(2, 3)
+	game.AddGarbage(3, 5)
+	assert.Equal(t, 5, game.GetPendingGarbage())
+	assert.Equal(t, 0, game.CancelGarbage(3))
+	assert.Equal(t, 2, game.GetPendingGarbage())
+
+	events = nil
+	gameOver, err := game.HardDrop()
+	require.NoError(t, err)
+	require.False(t, gameOver)
+	assert.Equal(t, 0, game.GetPendingGarbage())
+	assert.Contains(t, events, Event{Kind: EventGarbageReceived, Lines: 2})
+
+	// The remaining garbage is from the second attack, so the hole is in column 5.
+	for row := game.matrix.GetHeight() - 2; row < game.matrix.GetHeight(); row++ {
+		for col, cell := range game.matrix[row] {
+			if col == 5 {
+				assert.Equal(t, byte(0), cell)
+			} else {
+				assert.Equal(t, tetris.GarbageCell, cell)
+			}
+		}
+	}
+
+	// Enough garbage to push the locked Minos out of the Matrix.
+	game.AddGarbage(game.matrix.GetHeight(), 0)
+	gameOver, err = game.HardDrop()
+	require.NoError(t, err)
+	assert.True(t, gameOver)
+	assert.Equal(t, GameOverTopOut, game.GetGameOverReason())
+}
+
+func TestGame_GarbageCleared(t *testing.T) {
+	newGame := func(t *testing.T, lines int) (*Game, error) {
+		t.Helper()
+		return NewGame(&Input{
+			Level:               1,
+			GarbageLines:        lines,
+			EndOnGarbageCleared: true,
+			Rand:                rand.New(rand.NewPCG(1, 2)),
+		})
+	}
+
+	_, err := newGame(t, 0)
+	require.Error(t, err)
+	_, err = newGame(t, tetris.DefaultMatrixHeight)
+	require.Error(t, err)
+
+	game, err := newGame(t, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, game.GetGarbageRemaining())
+	game.AddRandomGarbage(2)
+	assert.Equal(t, 3, game.GetGarbageRemaining())
+	assert.Equal(t, 1, game.CancelGarbage(3))
+
+	// Drop a vertical I Tetrimino into the hole of the only garbage line.
+	bottom := game.matrix.GetHeight() - 1
+	hole := game.matrix.GetGarbageHole(bottom)
+	require.NotEqual(t, -1, hole)
+	tet, err := tetris.GetTetrimino('I')
+	require.NoError(t, err)
+	tet.Cells = [][]bool{{true}, {true}, {true}, {true}}
+	tet.CompassDirection = 1
+	tet.Position = tetris.Coordinate{X: hole, Y: game.matrix.GetSkyline()}
+	game.tetInPlay = tet
+
+	gameOver, err := game.HardDrop()
+	require.NoError(t, err)
+	assert.True(t, gameOver)
+	assert.Equal(t, GameOverLimitReached, game.GetGameOverReason())
+	assert.Equal(t, 0, game.GetGarbageRemaining())
+	assert.Equal(t, 1, game.GetLinesCleared())
+}
+
+func TestGame_Master(t *testing.T) {
+	game, err := NewGame(&Input{
+		Master:       true,
+		LockDownMode: tetris.LockDownInfinite,
+		Rand:         rand.New(rand.NewPCG(1, 2)),
+	})
+	require.NoError(t, err)
+	assert.True(t, game.IsMaster())
+	assert.Equal(t, 0, game.GetLevel())
+	assert.Equal(t, tetris.LockDownClassic, game.lockDown.Mode())
+	assert.Equal(t, 64*tetris.Frame, game.GetFallInterval())
+
+	// The next Tetrimino waits for the ARE before it spawns.
+	gameOver, err := game.HardDrop()
+	require.NoError(t, err)
+	require.False(t, gameOver)
+	assert.True(t, game.IsSpawnPending())
+	assert.Equal(t, tetris.DefaultMasterSections[0].ARE, game.GetFallInterval())
+	assert.False(t, game.MoveLeft(), "the Tetrimino should not move before it spawns")
+
+	game.UpdateLockDown(game.GetFallInterval() - time.Millisecond)
+	_, err = game.TickLower()
+	require.NoError(t, err)
+	assert.True(t, game.IsSpawnPending())
+	assert.Equal(t, 0, game.GetLevel())
+
+	game.UpdateLockDown(time.Millisecond)
+	_, err = game.TickLower()
+	require.NoError(t, err)
+	assert.False(t, game.IsSpawnPending())
+	assert.Equal(t, 1, game.GetLevel(), "the level should increase when the Tetrimino spawns")
+}
+
+func TestGame_Master20G(t *testing.T) {
+	game, err := NewGame(&Input{
+		Master: true,
+		Rand:   rand.New(rand.NewPCG(1, 2)),
+	})
+	require.NoError(t, err)
+	game.master, err = tetris.RestoreMaster(tetris.MasterState{
+		Level: 500, Combo: 1, Sections: tetris.DefaultMasterSections,
+	})
+	require.NoError(t, err)
+
+	gameOver, err := game.HardDrop()
+	require.NoError(t, err)
+	require.False(t, gameOver)
+	game.UpdateLockDown(game.GetFallInterval())
+	_, err = game.TickLower()
+	require.NoError(t, err)
+
+	// The Tetrimino should spawn at the lowest position it can reach, with the Lock Down timer running.
+	require.False(t, game.IsSpawnPending())
+	assert.True(t, game.fall.IsInstant)
+	assert.True(t, game.isTetInPlayOnSurface())
+	assert.True(t, game.lockDown.IsActive())
+
+	require.True(t, game.MoveLeft())
+	assert.True(t, game.isTetInPlayOnSurface(), "the Tetrimino should drop again after moving")
+}
+
+func TestGame_MasterComplete(t *testing.T) {
+	game, err := NewGame(&Input{
+		Master: true,
+		Rand:   rand.New(rand.NewPCG(1, 2)),
+	})
+	require.NoError(t, err)
+	game.master, err = tetris.RestoreMaster(tetris.MasterState{
+		Level: tetris.MasterMaxLevel - 1, Score: 130000, Combo: 1, Sections: tetris.DefaultMasterSections,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, tetris.MasterGradeS9, game.GetMasterGrade())
+
+	// Drop a vertical I Tetrimino into the hole of a garbage line to clear it.
+	_, err = game.matrix.AddGarbage(1, 0)
+	require.NoError(t, err)
+	tet, err := tetris.GetTetrimino('I')
+	require.NoError(t, err)
+	tet.Cells = [][]bool{{true}, {true}, {true}, {true}}
+	tet.CompassDirection = 1
+	tet.Position = tetris.Coordinate{X: 0, Y: game.matrix.GetSkyline()}
+	game.tetInPlay = tet
+
+	gameOver, err := game.HardDrop()
+	require.NoError(t, err)
+	assert.True(t, gameOver)
+	assert.Equal(t, GameOverLimitReached, game.GetGameOverReason())
+	assert.Equal(t, tetris.MasterMaxLevel, game.GetLevel())
+	assert.Equal(t, tetris.MasterGradeGM, game.GetMasterGrade())
+}
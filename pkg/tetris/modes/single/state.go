@@ -0,0 +1,379 @@
+package single
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris"
+)
+
+// StateVersion is the current version of the State format.
+// It is incremented whenever a field is added, since a Game restored from an older State would be missing it.
+const StateVersion = 3
+
+const (
+	// emptyCell represents an empty cell of the Matrix in a State.
+	emptyCell = '.'
+	// minoCell represents a Mino in the Cells of a TetriminoState.
+	minoCell = '#'
+)
+
+// State is the serializable state of a Game, allowing it to be saved and resumed later.
+type State struct {
+	Version int `json:"version"`
+
+	// Matrix is the rows of the Matrix, with each cell being the Value of its Mino or '.' when empty.
+	Matrix          []string       `json:"matrix"`
+	TetriminoInPlay TetriminoState `json:"tetrimino_in_play"`
+	// Hold is the Value of the held Tetrimino, or empty if nothing is held.
+	Hold      string                `json:"hold,omitempty"`
+	NextQueue tetris.NextQueueState `json:"next_queue"`
+	// RandSource is the binary encoding of the rand.PCG used by the NextQueue.
+	RandSource []byte `json:"rand_source"`
+
+	RotationPoint    int            `json:"rotation_point"`
+	CanHold          bool           `json:"can_hold"`
+	GameOver         bool           `json:"game_over"`
+	GameOverReason   GameOverReason `json:"game_over_reason"`
+	SoftDropStartRow int            `json:"soft_drop_start_row"`
+	SoftDropping     bool           `json:"soft_dropping"`
+	SoftDropFactor   int            `json:"soft_drop_factor"`
+	GhostEnabled     bool           `json:"ghost_enabled"`
+
+	Scoring  tetris.ScoringState  `json:"scoring"`
+	LockDown tetris.LockDownState `json:"lock_down"`
+	Garbage  []GarbageState       `json:"garbage,omitempty"`
+	Stats    Stats                `json:"stats"`
+	// EndOnGarbageCleared is whether the game ends once every garbage line has been cleared.
+	EndOnGarbageCleared bool `json:"end_on_garbage_cleared,omitempty"`
+	// LineGoal is the number of rows to clear before the game ends, or 0 if there is no goal.
+	LineGoal int `json:"line_goal,omitempty"`
+	// Master is the state of Master, or nil if the game is not Master.
+	Master *tetris.MasterState `json:"master,omitempty"`
+	// SpawnPending is whether the Tetrimino in play is waiting SpawnDelay before it spawns.
+	SpawnPending bool          `json:"spawn_pending,omitempty"`
+	SpawnDelay   time.Duration `json:"spawn_delay,omitempty"`
+}
+
+// TetriminoState is the serializable state of a Tetrimino.
+type TetriminoState struct {
+	Value byte `json:"value"`
+	// Cells is the rows of the (possibly rotated) shape, with '#' for a Mino and '.' for empty space.
+	Cells            []string          `json:"cells"`
+	Position         tetris.Coordinate `json:"position"`
+	CompassDirection int               `json:"compass_direction"`
+}
+
+// GarbageState is a number of queued garbage lines which share the same hole column.
+type GarbageState struct {
+	Lines int `json:"lines"`
+	Hole  int `json:"hole"`
+}
+
+// State returns the state of the Game.
+// It returns an error if the Game was not created with Input.Source, since the random source cannot be saved otherwise.
+func (g *Game) State() (*State, error) {
+	if g.source == nil {
+		return nil, errors.New("the game has no saveable random source")
+	}
+	source, err := g.source.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("encoding random source: %w", err)
+	}
+	nq, err := g.nextQueue.State()
+	if err != nil {
+		return nil, fmt.Errorf("saving next queue: %w", err)
+	}
+
+	matrix := make([]string, len(g.matrix))
+	for row := range g.matrix {
+		var sb strings.Builder
+		for _, cell := range g.matrix[row] {
+			if cell == 0 {
+				cell = emptyCell
+			}
+			sb.WriteByte(cell)
+		}
+		matrix[row] = sb.String()
+	}
+
+	var hold string
+	if g.holdQueue.Value != 0 {
+		hold = string(g.holdQueue.Value)
+	}
+
+	var garbage []GarbageState
+	for _, attack := range g.garbage {
+		garbage = append(garbage, GarbageState{Lines: attack.lines, Hole: attack.hole})
+	}
+
+	var master *tetris.MasterState
+	if g.master != nil {
+		state := g.master.State()
+		master = &state
+	}
+
+	return &State{
+		Version:          StateVersion,
+		Matrix:           matrix,
+		TetriminoInPlay:  newTetriminoState(g.tetInPlay),
+		Hold:             hold,
+		NextQueue:        nq,
+		RandSource:       source,
+		RotationPoint:    g.rotationPoint,
+		CanHold:          g.canHold,
+		GameOver:         g.gameOver,
+		GameOverReason:   g.gameOverReason,
+		SoftDropStartRow: g.softDropStartRow,
+		SoftDropping:     g.fall.IsSoftDrop,
+		SoftDropFactor:   g.fall.SoftDropFactor(),
+		GhostEnabled:     g.ghostTet != nil,
+		Scoring:          g.scoring.State(),
+		LockDown:         g.lockDown.State(),
+		Garbage:          garbage,
+		Stats:            g.stats,
+
+		EndOnGarbageCleared: g.endOnGarbageCleared,
+		LineGoal:            g.lineGoal,
+		Master:              master,
+		SpawnPending:        g.isSpawnPending,
+		SpawnDelay:          g.spawnDelay,
+	}, nil
+}
+
+// RestoreGame recreates a Game from a state returned by Game.State.
+// No Events are emitted whilst restoring the Game.
+func RestoreGame(state *State, opts ...func(*Game)) (*Game, error) {
+	if state.Version != StateVersion {
+		return nil, fmt.Errorf("unsupported game state version %d (expected %d)", state.Version, StateVersion)
+	}
+
+	matrix, err := restoreMatrix(state.Matrix)
+	if err != nil {
+		return nil, fmt.Errorf("restoring matrix: %w", err)
+	}
+
+	source := new(rand.PCG)
+	if err = source.UnmarshalBinary(state.RandSource); err != nil {
+		return nil, fmt.Errorf("decoding random source: %w", err)
+	}
+	rng := rand.New(source)
+	nq, err := tetris.RestoreNextQueue(matrix.GetSkyline(), state.NextQueue,
+		tetris.WithRandSource(rng),
+		tetris.WithMatrixWidth(matrix.GetWidth()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("restoring next queue: %w", err)
+	}
+
+	tetInPlay, err := state.TetriminoInPlay.restore()
+	if err != nil {
+		return nil, fmt.Errorf("restoring tetrimino in play: %w", err)
+	}
+	if !isWithinMatrix(tetInPlay, matrix) {
+		return nil, fmt.Errorf("tetrimino in play at %+v is outside the matrix", tetInPlay.Position)
+	}
+	// A Tetrimino waiting to spawn is checked for Block Out once it spawns.
+	if !state.SpawnPending && !tetInPlay.IsValid(matrix, true) {
+		return nil, errors.New("tetrimino in play overlaps the matrix")
+	}
+	if state.RotationPoint < 0 || state.RotationPoint > len(tetInPlay.RotationCompass[tetInPlay.CompassDirection]) {
+		return nil, fmt.Errorf("invalid rotation point %d", state.RotationPoint)
+	}
+	if state.SoftDropStartRow < 0 || state.SoftDropStartRow > matrix.GetHeight() {
+		return nil, fmt.Errorf("invalid soft drop start row %d", state.SoftDropStartRow)
+	}
+
+	holdQueue := tetris.GetEmptyTetrimino()
+	if len(state.Hold) > 1 {
+		return nil, fmt.Errorf("invalid hold value %q", state.Hold)
+	}
+	if state.Hold != "" {
+		holdQueue, err = tetris.GetTetrimino(state.Hold[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid hold value %q: %w", state.Hold, err)
+		}
+		holdQueue.Position = matrix.GetStartingPosition(holdQueue)
+	}
+
+	scoring, err := tetris.RestoreScoring(state.Scoring)
+	if err != nil {
+		return nil, fmt.Errorf("restoring scoring system: %w", err)
+	}
+	lockDown, err := tetris.RestoreLockDown(state.LockDown)
+	if err != nil {
+		return nil, fmt.Errorf("restoring lock down timer: %w", err)
+	}
+	fall := tetris.NewFall(scoring.Level(), tetris.WithSoftDropFactor(state.SoftDropFactor))
+	var master *tetris.Master
+	if state.Master != nil {
+		master, err = tetris.RestoreMaster(*state.Master)
+		if err != nil {
+			return nil, fmt.Errorf("restoring master: %w", err)
+		}
+		fall = tetris.NewFall(master.Level(),
+			tetris.WithSoftDropFactor(state.SoftDropFactor),
+			tetris.WithMasterGravity(),
+		)
+	}
+	fall.IsSoftDrop = state.SoftDropping
+
+	garbage := make([]garbageAttack, 0, len(state.Garbage))
+	for _, attack := range state.Garbage {
+		if attack.Lines <= 0 || attack.Hole < 0 || attack.Hole >= matrix.GetWidth() {
+			return nil, fmt.Errorf("invalid garbage of %d lines with hole %d", attack.Lines, attack.Hole)
+		}
+		garbage = append(garbage, garbageAttack{lines: attack.Lines, hole: attack.Hole})
+	}
+
+	stats := state.Stats
+	if stats.Pieces < 0 || stats.Rows < 0 || stats.Tetrises < 0 || stats.TSpins < 0 || stats.MiniTSpins < 0 {
+		return nil, fmt.Errorf("invalid stats %+v", stats)
+	}
+	if state.LineGoal < 0 {
+		return nil, fmt.Errorf("invalid line goal '%d'", state.LineGoal)
+	}
+
+	g := &Game{
+		matrix:           matrix,
+		nextQueue:        nq,
+		source:           source,
+		tetInPlay:        tetInPlay,
+		holdQueue:        holdQueue,
+		rotationPoint:    state.RotationPoint,
+		canHold:          state.CanHold,
+		gameOver:         state.GameOver,
+		gameOverReason:   state.GameOverReason,
+		softDropStartRow: state.SoftDropStartRow,
+		scoring:          scoring,
+		fall:             fall,
+		lockDown:         lockDown,
+		garbage:          garbage,
+		stats:            state.Stats,
+		rand:             rng,
+
+		endOnGarbageCleared: state.EndOnGarbageCleared,
+		lineGoal:            state.LineGoal,
+		master:              master,
+		isSpawnPending:      state.SpawnPending,
+		spawnDelay:          state.SpawnDelay,
+	}
+
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	if state.GhostEnabled {
+		g.ghostTet = g.tetInPlay
+		g.updateGhost()
+	}
+	return g, nil
+}
+
+func newTetriminoState(tet *tetris.Tetrimino) TetriminoState {
+	cells := make([]string, len(tet.Cells))
+	for row := range tet.Cells {
+		var sb strings.Builder
+		for _, isMino := range tet.Cells[row] {
+			if isMino {
+				sb.WriteByte(minoCell)
+			} else {
+				sb.WriteByte(emptyCell)
+			}
+		}
+		cells[row] = sb.String()
+	}
+
+	return TetriminoState{
+		Value:            tet.Value,
+		Cells:            cells,
+		Position:         tet.Position,
+		CompassDirection: tet.CompassDirection,
+	}
+}
+
+func (s TetriminoState) restore() (*tetris.Tetrimino, error) {
+	tet, err := tetris.GetTetrimino(s.Value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %q: %w", s.Value, err)
+	}
+	if s.CompassDirection < 0 || s.CompassDirection >= len(tet.RotationCompass) {
+		return nil, fmt.Errorf("invalid compass direction %d", s.CompassDirection)
+	}
+
+	var minos int
+	cells := make([][]bool, len(s.Cells))
+	for row := range s.Cells {
+		if len(s.Cells[row]) != len(s.Cells[0]) {
+			return nil, errors.New("cells are not rectangular")
+		}
+		cells[row] = make([]bool, len(s.Cells[row]))
+		for col := range len(s.Cells[row]) {
+			switch s.Cells[row][col] {
+			case minoCell:
+				cells[row][col] = true
+				minos++
+			case emptyCell:
+			default:
+				return nil, fmt.Errorf("invalid cell %q", s.Cells[row][col])
+			}
+		}
+	}
+	if minos != 4 {
+		return nil, fmt.Errorf("expected 4 minos, got %d", minos)
+	}
+
+	tet.Cells = cells
+	tet.Position = s.Position
+	tet.CompassDirection = s.CompassDirection
+	return tet, nil
+}
+
+// isWithinMatrix returns true if every Mino of the Tetrimino is within the bounds of the Matrix.
+func isWithinMatrix(tet *tetris.Tetrimino, matrix tetris.Matrix) bool {
+	for row := range tet.Cells {
+		for col := range tet.Cells[row] {
+			if !tet.Cells[row][col] {
+				continue
+			}
+			y, x := tet.Position.Y+row, tet.Position.X+col
+			if y < 0 || y >= matrix.GetHeight() || x < 0 || x >= matrix.GetWidth() {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func restoreMatrix(rows []string) (tetris.Matrix, error) {
+	if len(rows) == 0 {
+		return nil, errors.New("matrix has no rows")
+	}
+	matrix, err := tetris.NewMatrix(len(rows), len(rows[0]))
+	if err != nil {
+		return nil, err
+	}
+
+	for row := range rows {
+		if len(rows[row]) != matrix.GetWidth() {
+			return nil, fmt.Errorf("row %d has %d cells, expected %d", row, len(rows[row]), matrix.GetWidth())
+		}
+		for col := range len(rows[row]) {
+			cell := rows[row][col]
+			switch {
+			case cell == emptyCell:
+				continue
+			case cell == tetris.GarbageCell:
+			default:
+				if _, err = tetris.GetTetrimino(cell); err != nil {
+					return nil, fmt.Errorf("invalid cell %q at row %d, column %d", cell, row, col)
+				}
+			}
+			matrix[row][col] = cell
+		}
+	}
+	return matrix, nil
+}
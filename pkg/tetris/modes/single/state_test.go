@@ -0,0 +1,212 @@
+package single
+
+import (
+	"encoding/json"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris"
+)
+
+func TestRestoreGame(t *testing.T) {
+	newGame := func(t *testing.T) *Game {
+		randomizer, err := tetris.NewRandomizer(tetris.RandomizerTGM1)
+		require.NoError(t, err)
+		g, err := NewGame(&Input{
+			Level:         1,
+			IncreaseLevel: true,
+			LockDownMode:  tetris.LockDownInfinite,
+			GhostEnabled:  true,
+			Source:        rand.NewPCG(1, 2),
+			Randomizer:    randomizer,
+		})
+		require.NoError(t, err)
+		return g
+	}
+
+	// play performs a sequence of operations which covers most of the state of the game.
+	play := func(t *testing.T, g *Game) {
+		for i := range 4 {
+			for range i {
+				g.MoveLeft()
+			}
+			require.NoError(t, g.Rotate(i%2 == 0))
+			gameOver, err := g.HardDrop()
+			require.NoError(t, err)
+			require.False(t, gameOver)
+		}
+		_, err := g.Hold()
+		require.NoError(t, err)
+		g.ToggleSoftDrop()
+		_, err = g.TickLower()
+		require.NoError(t, err)
+		g.AddGarbage(2, 3)
+	}
+
+	g := newGame(t)
+	play(t, g)
+
+	state, err := g.State()
+	require.NoError(t, err)
+	b, err := json.Marshal(state)
+	require.NoError(t, err)
+	decoded := new(State)
+	require.NoError(t, json.Unmarshal(b, decoded))
+
+	restored, err := RestoreGame(decoded)
+	require.NoError(t, err)
+	restoredState, err := restored.State()
+	require.NoError(t, err)
+	assert.Equal(t, state, restoredState)
+
+	wantMatrix, err := g.GetVisibleMatrix()
+	require.NoError(t, err)
+	gotMatrix, err := restored.GetVisibleMatrix()
+	require.NoError(t, err)
+	assert.Equal(t, wantMatrix, gotMatrix)
+	assert.Equal(t, g.GetFallInterval(), restored.GetFallInterval())
+
+	// Both games should continue identically.
+	play(t, g)
+	play(t, restored)
+	g.UpdateLockDown(time.Second)
+	restored.UpdateLockDown(time.Second)
+	want, err := g.State()
+	require.NoError(t, err)
+	got, err := restored.State()
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestGame_State_NoSource(t *testing.T) {
+	g, err := NewGame(&Input{Level: 1, Rand: rand.New(rand.NewPCG(0, 0))})
+	require.NoError(t, err)
+
+	_, err = g.State()
+	assert.Error(t, err)
+}
+
+func TestRestoreGame_Invalid(t *testing.T) {
+	tt := map[string]struct {
+		modify func(s *State)
+	}{
+		"unsupported version": {
+			modify: func(s *State) { s.Version = StateVersion + 1 },
+		},
+		"previous version": {
+			modify: func(s *State) { s.Version = StateVersion - 1 },
+		},
+		"invalid matrix cell": {
+			modify: func(s *State) { s.Matrix[len(s.Matrix)-1] = "....?....." },
+		},
+		"ragged matrix": {
+			modify: func(s *State) { s.Matrix[0] = "..." },
+		},
+		"invalid random source": {
+			modify: func(s *State) { s.RandSource = []byte("invalid") },
+		},
+		"invalid tetrimino in play": {
+			modify: func(s *State) { s.TetriminoInPlay.Cells = []string{"##", "#."} },
+		},
+		"tetrimino in play out of bounds": {
+			modify: func(s *State) { s.TetriminoInPlay.Position.X = -2 },
+		},
+		"tetrimino waiting to spawn out of bounds": {
+			modify: func(s *State) {
+				s.SpawnPending = true
+				s.TetriminoInPlay.Position.Y = len(s.Matrix)
+			},
+		},
+		"invalid rotation point": {
+			modify: func(s *State) { s.RotationPoint = 6 },
+		},
+		"negative rotation point": {
+			modify: func(s *State) { s.RotationPoint = -1 },
+		},
+		"soft drop start row out of bounds": {
+			modify: func(s *State) { s.SoftDropStartRow = len(s.Matrix) + 1 },
+		},
+		"invalid hold": {
+			modify: func(s *State) { s.Hold = "Q" },
+		},
+		"invalid garbage": {
+			modify: func(s *State) { s.Garbage = []GarbageState{{Lines: 1, Hole: 10}} },
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			g, err := NewGame(&Input{Level: 1, Source: rand.NewPCG(0, 0)})
+			require.NoError(t, err)
+			state, err := g.State()
+			require.NoError(t, err)
+
+			tc.modify(state)
+			_, err = RestoreGame(state)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRestoreGame_RandomGarbage(t *testing.T) {
+	g, err := NewGame(&Input{
+		Level:               1,
+		GarbageLines:        5,
+		EndOnGarbageCleared: true,
+		Source:              rand.NewPCG(1, 2),
+	})
+	require.NoError(t, err)
+
+	state, err := g.State()
+	require.NoError(t, err)
+	assert.True(t, state.EndOnGarbageCleared)
+	restored, err := RestoreGame(state)
+	require.NoError(t, err)
+	assert.Equal(t, 5, restored.GetGarbageRemaining())
+
+	// The holes of random garbage are chosen using the restored random source.
+	g.AddRandomGarbage(3)
+	restored.AddRandomGarbage(3)
+	want, err := g.State()
+	require.NoError(t, err)
+	got, err := restored.State()
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestRestoreGame_Master(t *testing.T) {
+	g, err := NewGame(&Input{
+		Master: true,
+		Source: rand.NewPCG(1, 2),
+	})
+	require.NoError(t, err)
+	_, err = g.HardDrop()
+	require.NoError(t, err)
+	g.UpdateLockDown(time.Millisecond)
+	require.True(t, g.IsSpawnPending())
+
+	state, err := g.State()
+	require.NoError(t, err)
+	require.NotNil(t, state.Master)
+	restored, err := RestoreGame(state)
+	require.NoError(t, err)
+	assert.True(t, restored.IsMaster())
+	assert.True(t, restored.IsSpawnPending())
+	assert.Equal(t, g.GetFallInterval(), restored.GetFallInterval())
+
+	// Both games should spawn the next Tetrimino at the same time.
+	for _, game := range []*Game{g, restored} {
+		game.UpdateLockDown(game.GetFallInterval())
+		_, err = game.TickLower()
+		require.NoError(t, err)
+	}
+	want, err := g.State()
+	require.NoError(t, err)
+	got, err := restored.State()
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
@@ -0,0 +1,39 @@
+package single
+
+import "github.com/Broderick-Westrope/tetrigo/pkg/tetris"
+
+// Stats are counts of what the player achieved during a Game, in addition to the score, lines and level.
+type Stats struct {
+	Pieces     int `json:"pieces"`       // The number of Tetriminos locked down.
+	Rows       int `json:"rows"`         // The number of rows removed from the Matrix by line clears.
+	Tetrises   int `json:"tetrises"`     // The number of lock downs which cleared four lines.
+	TSpins     int `json:"t_spins"`      // The number of full T-Spins, with or without a line clear.
+	MiniTSpins int `json:"mini_t_spins"` // The number of Mini T-Spins, with or without a line clear.
+}
+
+// GetStats returns the Stats of the game so far.
+func (g *Game) GetStats() Stats {
+	return g.stats
+}
+
+// updateStats counts a Tetrimino locking down with the given Action and T-Spin.
+func (g *Game) updateStats(action tetris.Action, tSpin tetris.TSpin) {
+	g.stats.Pieces++
+	g.stats.Rows += action.GetRowsCleared()
+	if action.GetRowsCleared() == 4 {
+		g.stats.Tetrises++
+	}
+
+	switch tSpin {
+	case tetris.TSpinFull:
+		g.stats.TSpins++
+	case tetris.TSpinMini:
+		g.stats.MiniTSpins++
+	case tetris.TSpinNone:
+	}
+}
+
+// isLineGoalReached returns true if the game has a line goal and enough rows have been cleared to reach it.
+func (g *Game) isLineGoalReached() bool {
+	return g.lineGoal > 0 && g.stats.Rows >= g.lineGoal
+}
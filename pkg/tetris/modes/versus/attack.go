@@ -0,0 +1,57 @@
+package versus
+
+import (
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris"
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris/modes/single"
+)
+
+// Attack calculates the garbage lines sent by a single.Game, tracking Back-to-Backs.
+// HandleEvent must be registered as an EventHandler of the game, and Resolve called after each operation
+// on the game. This allows the opponent to be local (see Game) or remote.
+type Attack struct {
+	linesSent  int           // The total number of garbage lines sent to the opponent
+	hasLocked  bool          // Whether a Tetrimino has locked down since the last call to Resolve
+	lockAction tetris.Action // The Action of the last lock down
+	backToBack bool          // Whether the last lock down was awarded the Back-to-Back bonus
+}
+
+func NewAttack() *Attack {
+	return &Attack{}
+}
+
+// GetLinesSent returns the total number of garbage lines sent to the opponent.
+func (a *Attack) GetLinesSent() int {
+	return a.linesSent
+}
+
+// HandleEvent records the details of each lock down so that garbage can be calculated once the
+// operation on the game has finished.
+func (a *Attack) HandleEvent(e single.Event) {
+	switch e.Kind {
+	case single.EventPieceLocked:
+		a.hasLocked = true
+		a.lockAction = e.Action
+		a.backToBack = false
+	case single.EventBackToBack:
+		a.backToBack = true
+	case single.EventPerfectClear:
+		// A Perfect Clear sends the garbage of its bonus instead of that of the line clear.
+		a.lockAction = e.Action
+	case single.EventPieceSpawned, single.EventLinesCleared, single.EventLevelUp, single.EventHold,
+		single.EventGameOver, single.EventGarbageReceived:
+	}
+}
+
+// Resolve returns the number of garbage lines to send to the opponent for the last lock down of the game.
+// These first cancel any garbage waiting to be received by the game, so only the remainder is returned.
+func (a *Attack) Resolve(game *single.Game) int {
+	if !a.hasLocked {
+		return 0
+	}
+	a.hasLocked = false
+
+	lines := tetris.GarbageLines(a.lockAction, a.backToBack, game.GetCombo())
+	lines = game.CancelGarbage(lines)
+	a.linesSent += max(lines, 0)
+	return lines
+}
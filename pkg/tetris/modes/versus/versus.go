@@ -0,0 +1,179 @@
+package versus
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris/modes/single"
+)
+
+// Game represents a game of Tetris between two players, each with their own single.Game.
+// Clearing lines sends garbage to the opponent, which first cancels any garbage waiting to be received.
+// The last player standing wins.
+type Game struct {
+	players [2]*Player
+	rand    *rand.Rand
+	winner  int
+}
+
+type Input struct {
+	Players [2]*single.Input // The input used to create the game of each player.
+	Rand    *rand.Rand       // The random source used to choose the hole column of garbage lines.
+}
+
+// Player is one side of a versus Game. All operations on the player's single.Game must be performed using
+// the Player so that garbage is exchanged.
+type Player struct {
+	game     *single.Game
+	attack   *Attack
+	versus   *Game
+	opponent *Player
+	width    int
+}
+
+func NewGame(in *Input) (*Game, error) {
+	if in.Rand == nil {
+		return nil, errors.New("random source is required")
+	}
+
+	g := &Game{
+		rand:   in.Rand,
+		winner: -1,
+	}
+	for i, playerIn := range in.Players {
+		if playerIn == nil {
+			return nil, fmt.Errorf("input for player %d is required", i+1)
+		}
+
+		p := &Player{versus: g, attack: NewAttack()}
+		game, err := single.NewGame(playerIn, single.WithEventHandler(p.attack.HandleEvent))
+		if err != nil {
+			return nil, fmt.Errorf("creating game for player %d: %w", i+1, err)
+		}
+		p.game = game
+		matrix := game.GetMatrix()
+		p.width = matrix.GetWidth()
+		g.players[i] = p
+	}
+	g.players[0].opponent = g.players[1]
+	g.players[1].opponent = g.players[0]
+
+	return g, nil
+}
+
+// Player returns the player with the given index (0 or 1).
+func (g *Game) Player(i int) *Player {
+	return g.players[i]
+}
+
+// IsGameOver returns true once either player's game is over.
+func (g *Game) IsGameOver() bool {
+	return g.winner >= 0
+}
+
+// GetWinner returns the index of the winning player, or -1 if the game is not over.
+func (g *Game) GetWinner() int {
+	return g.winner
+}
+
+// Game returns the single.Game of the player. This should only be used to query the state of the game.
+func (p *Player) Game() *single.Game {
+	return p.game
+}
+
+// GetCombo returns the number of consecutive lock downs which cleared lines, minus one.
+// A value of -1 means there is no combo.
+func (p *Player) GetCombo() int {
+	return p.game.GetCombo()
+}
+
+// GetLinesSent returns the total number of garbage lines sent to the opponent.
+func (p *Player) GetLinesSent() int {
+	return p.attack.GetLinesSent()
+}
+
+// GetPendingGarbage returns the number of garbage lines waiting to be inserted into the player's Matrix.
+func (p *Player) GetPendingGarbage() int {
+	return p.game.GetPendingGarbage()
+}
+
+func (p *Player) MoveLeft() {
+	p.game.MoveLeft()
+}
+
+func (p *Player) MoveRight() {
+	p.game.MoveRight()
+}
+
+func (p *Player) Rotate(clockwise bool) error {
+	return p.game.Rotate(clockwise)
+}
+
+func (p *Player) ToggleSoftDrop() {
+	p.game.ToggleSoftDrop()
+}
+
+func (p *Player) UpdateLockDown(elapsed time.Duration) {
+	p.game.UpdateLockDown(elapsed)
+}
+
+// Hold swaps the Tetrimino in play with the hold Tetrimino (see single.Game.Hold).
+// If true is returned the versus game is over.
+func (p *Player) Hold() (bool, error) {
+	if _, err := p.game.Hold(); err != nil {
+		return false, err
+	}
+	return p.update(), nil
+}
+
+// TickLower moves the Tetrimino in play down one row (see single.Game.TickLower).
+// If true is returned the versus game is over.
+func (p *Player) TickLower() (bool, error) {
+	if _, err := p.game.TickLower(); err != nil {
+		return false, err
+	}
+	return p.update(), nil
+}
+
+// HardDrop drops and locks the Tetrimino in play (see single.Game.HardDrop).
+// If true is returned the versus game is over.
+func (p *Player) HardDrop() (bool, error) {
+	if _, err := p.game.HardDrop(); err != nil {
+		return false, err
+	}
+	return p.update(), nil
+}
+
+// Forfeit ends the player's game, making the opponent the winner.
+func (p *Player) Forfeit() {
+	p.game.EndGame()
+	p.update()
+}
+
+// update sends garbage for the last lock down and checks whether the versus game is over.
+func (p *Player) update() bool {
+	p.sendGarbage()
+
+	if !p.versus.IsGameOver() && p.game.IsGameOver() {
+		p.versus.winner = p.opponent.index()
+	}
+	return p.versus.IsGameOver()
+}
+
+// sendGarbage sends the garbage lines from the last lock down to the opponent with a random hole column.
+func (p *Player) sendGarbage() {
+	lines := p.attack.Resolve(p.game)
+	if lines <= 0 {
+		return
+	}
+	p.opponent.game.AddGarbage(lines, p.versus.rand.IntN(p.opponent.width))
+}
+
+func (p *Player) index() int {
+	if p.versus.players[0] == p {
+		return 0
+	}
+	return 1
+}
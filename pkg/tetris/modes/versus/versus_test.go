@@ -0,0 +1,149 @@
+package versus
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris"
+	"github.com/Broderick-Westrope/tetrigo/pkg/tetris/modes/single"
+)
+
+// newTestGame creates a Game where both players have a Matrix 4 wide and only receive O Tetriminos.
+// Placing two O Tetriminos side by side on an empty Matrix clears a Double Perfect Clear, which sends 10 lines.
+func newTestGame(t *testing.T) *Game {
+	t.Helper()
+
+	var inputs [2]*single.Input
+	for i := range inputs {
+		r, err := tetris.NewSequenceRandomizer([]byte{'O'})
+		require.NoError(t, err)
+		inputs[i] = &single.Input{
+			Level:      1,
+			Width:      4,
+			Rand:       rand.New(rand.NewPCG(0, 0)),
+			Randomizer: r,
+		}
+	}
+
+	g, err := NewGame(&Input{Players: inputs, Rand: rand.New(rand.NewPCG(1, 2))})
+	require.NoError(t, err)
+	return g
+}
+
+func dropLeft(t *testing.T, p *Player) {
+	t.Helper()
+	p.MoveLeft()
+	_, err := p.HardDrop()
+	require.NoError(t, err)
+}
+
+func dropRight(t *testing.T, p *Player) {
+	t.Helper()
+	p.MoveRight()
+	_, err := p.HardDrop()
+	require.NoError(t, err)
+}
+
+func TestNewGame(t *testing.T) {
+	_, err := NewGame(&Input{Rand: rand.New(rand.NewPCG(1, 2))})
+	require.Error(t, err)
+
+	g := newTestGame(t)
+	assert.False(t, g.IsGameOver())
+	assert.Equal(t, -1, g.GetWinner())
+	assert.Equal(t, -1, g.Player(0).GetCombo())
+}
+
+func TestPlayer_SendGarbage(t *testing.T) {
+	g := newTestGame(t)
+	p1, p2 := g.Player(0), g.Player(1)
+
+	dropLeft(t, p1)
+	assert.Equal(t, 0, p2.GetPendingGarbage())
+
+	dropRight(t, p1)
+	assert.Equal(t, 0, p1.GetCombo())
+	assert.Equal(t, 10, p1.GetLinesSent())
+	assert.Equal(t, 10, p2.GetPendingGarbage())
+
+	// Locking without clearing lines inserts the garbage.
+	dropLeft(t, p2)
+	assert.Equal(t, 0, p2.GetPendingGarbage())
+	matrix := p2.Game().GetMatrix()
+	bottom := matrix[matrix.GetHeight()-1]
+	assert.Equal(t, 3, countGarbage(bottom))
+}
+
+func TestPlayer_CancelGarbage(t *testing.T) {
+	g := newTestGame(t)
+	p1, p2 := g.Player(0), g.Player(1)
+
+	dropLeft(t, p1)
+	dropLeft(t, p2)
+	dropRight(t, p1)
+	require.Equal(t, 10, p2.GetPendingGarbage())
+
+	// Player 2 clears a Double Perfect Clear, cancelling the garbage instead of sending it.
+	dropRight(t, p2)
+	assert.Equal(t, 0, p2.GetPendingGarbage())
+	assert.Equal(t, 0, p1.GetPendingGarbage())
+	assert.Equal(t, 0, p2.GetLinesSent())
+}
+
+func TestPlayer_Combo(t *testing.T) {
+	g := newTestGame(t)
+	p1 := g.Player(0)
+
+	dropLeft(t, p1)
+	dropRight(t, p1)
+	assert.Equal(t, 0, p1.GetCombo())
+
+	dropLeft(t, p1)
+	assert.Equal(t, -1, p1.GetCombo())
+}
+
+func TestPlayer_Forfeit(t *testing.T) {
+	g := newTestGame(t)
+
+	g.Player(1).Forfeit()
+	assert.True(t, g.IsGameOver())
+	assert.Equal(t, 0, g.GetWinner())
+
+	// The winner does not change once the game is over.
+	g.Player(0).Forfeit()
+	assert.Equal(t, 0, g.GetWinner())
+}
+
+func TestPlayer_LosesToGarbage(t *testing.T) {
+	g := newTestGame(t)
+	p1, p2 := g.Player(0), g.Player(1)
+
+	var gameOver bool
+	for range 100 {
+		dropLeft(t, p1)
+		dropRight(t, p1)
+		var err error
+		gameOver, err = p2.HardDrop()
+		require.NoError(t, err)
+		if gameOver {
+			break
+		}
+	}
+	require.True(t, gameOver)
+	assert.Equal(t, 0, g.GetWinner())
+	assert.True(t, p2.Game().IsGameOver())
+	assert.False(t, p1.Game().IsGameOver())
+}
+
+func countGarbage(row []byte) int {
+	var count int
+	for _, cell := range row {
+		if cell == tetris.GarbageCell {
+			count++
+		}
+	}
+	return count
+}
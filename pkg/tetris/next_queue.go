@@ -1,15 +1,19 @@
 package tetris
 
 import (
+	"errors"
+	"fmt"
 	"math/rand/v2"
 )
 
 // NextQueue is a collection of up to 14 Tetriminos that are drawn from randomly.
 // The queue is refilled when it has less than 7 Tetriminos.
 type NextQueue struct {
-	elements []Tetrimino
-	skyline  int
-	rand     *rand.Rand
+	elements   []Tetrimino
+	skyline    int
+	width      int
+	rand       *rand.Rand
+	randomizer Randomizer
 }
 
 // NewNextQueue creates a new NextQueue of Tetriminos.
@@ -17,8 +21,10 @@ func NewNextQueue(skyline int, opts ...func(*NextQueue)) *NextQueue {
 	nq := &NextQueue{
 		elements: make([]Tetrimino, 0, 14),
 		skyline:  skyline,
+		width:    DefaultMatrixWidth,
 		//nolint:gosec // This random source is not for any security-related tasks.
-		rand: rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
+		rand:       rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
+		randomizer: NewBagRandomizer(1),
 	}
 
 	for _, opt := range opts {
@@ -35,6 +41,20 @@ func WithRandSource(rand *rand.Rand) func(*NextQueue) {
 	}
 }
 
+// WithRandomizer sets the Randomizer which decides the order of the Tetriminos. The default is a 7-bag.
+func WithRandomizer(r Randomizer) func(*NextQueue) {
+	return func(nq *NextQueue) {
+		nq.randomizer = r
+	}
+}
+
+// WithMatrixWidth sets the width of the Matrix, which is used to center the Tetriminos. The default is 10.
+func WithMatrixWidth(width int) func(*NextQueue) {
+	return func(nq *NextQueue) {
+		nq.width = width
+	}
+}
+
 // GetElements returns the Tetriminos in the queue.
 func (nq *NextQueue) GetElements() []Tetrimino {
 	return nq.elements
@@ -54,20 +74,82 @@ func (nq *NextQueue) Next() *Tetrimino {
 	return &tet
 }
 
-// fill adds Tetriminos to the queue until it has 7 or more.
-// This is done by getting all valid Tetriminos, shuffling them, and adding them to the queue.
+// fill adds 7 Tetriminos to the queue if it has 7 or less.
+// The Tetriminos are chosen by the Randomizer.
 func (nq *NextQueue) fill() {
 	if len(nq.elements) > 7 {
 		return
 	}
 
-	tetriminos := GetValidTetriminos()
-	perm := nq.rand.Perm(len(tetriminos))
-	for _, i := range perm {
+	tetriminos := getMapOfValidTetriminos()
+	for range 7 {
 		if len(nq.elements) == 14 {
-			// This should be impossible whilst there are only 7 Tetriminos and we check that there is space for 7 in the queue
+			// This should be impossible since we check that there is space for 7 in the queue
 			return
 		}
-		nq.elements = append(nq.elements, tetriminos[i])
+		tet := tetriminos[nq.randomizer.Next(nq.rand)]
+		tet.Position.X = startingPosition(&tet, nq.width).X
+		nq.elements = append(nq.elements, *tet.DeepCopy())
+	}
+}
+
+// NextQueueState is the serializable state of a NextQueue.
+type NextQueueState struct {
+	// Values are the Values of the Tetriminos in the queue, in order.
+	Values     string          `json:"values"`
+	Randomizer RandomizerState `json:"randomizer"`
+}
+
+// State returns the state of the NextQueue. The random source is not included.
+func (nq *NextQueue) State() (NextQueueState, error) {
+	randomizer, err := SaveRandomizer(nq.randomizer)
+	if err != nil {
+		return NextQueueState{}, fmt.Errorf("saving randomizer: %w", err)
+	}
+
+	values := make([]byte, len(nq.elements))
+	for i := range nq.elements {
+		values[i] = nq.elements[i].Value
+	}
+	return NextQueueState{
+		Values:     string(values),
+		Randomizer: randomizer,
+	}, nil
+}
+
+// RestoreNextQueue recreates a NextQueue from a state returned by State.
+// The random source must be restored using WithRandSource.
+// The Randomizer of the state takes precedence over WithRandomizer.
+func RestoreNextQueue(skyline int, state NextQueueState, opts ...func(*NextQueue)) (*NextQueue, error) {
+	if len(state.Values) == 0 || len(state.Values) > 14 {
+		return nil, fmt.Errorf("invalid number of tetriminos %d", len(state.Values))
+	}
+
+	randomizer, err := RestoreRandomizer(state.Randomizer)
+	if err != nil {
+		return nil, fmt.Errorf("restoring randomizer: %w", err)
+	}
+
+	nq := &NextQueue{
+		elements: make([]Tetrimino, 0, 14),
+		skyline:  skyline,
+		width:    DefaultMatrixWidth,
+	}
+	for _, opt := range opts {
+		opt(nq)
+	}
+	nq.randomizer = randomizer
+	if nq.rand == nil {
+		return nil, errors.New("a random source is required")
+	}
+
+	for i := range len(state.Values) {
+		tet, err := GetTetrimino(state.Values[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid tetrimino %q: %w", state.Values[i], err)
+		}
+		tet.Position.X = startingPosition(tet, nq.width).X
+		nq.elements = append(nq.elements, *tet)
 	}
+	return nq, nil
 }
@@ -1,6 +1,7 @@
 package tetris
 
 import (
+	"math/rand/v2"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -148,3 +149,56 @@ func TestNextQueue_Fill(t *testing.T) {
 		})
 	}
 }
+
+func TestRestoreNextQueue(t *testing.T) {
+	source := rand.NewPCG(1, 2)
+	nq := NewNextQueue(20, WithRandSource(rand.New(source)), WithMatrixWidth(12))
+	for range 10 {
+		nq.Next()
+	}
+
+	state, err := nq.State()
+	require.NoError(t, err)
+	sourceState, err := source.MarshalBinary()
+	require.NoError(t, err)
+	restoredSource := &rand.PCG{}
+	require.NoError(t, restoredSource.UnmarshalBinary(sourceState))
+
+	restored, err := RestoreNextQueue(20, state, WithRandSource(rand.New(restoredSource)), WithMatrixWidth(12))
+	require.NoError(t, err)
+	assert.Equal(t, nq.GetElements(), restored.GetElements())
+	for i := range 30 {
+		assert.Equal(t, nq.Next(), restored.Next(), "tetrimino %d", i)
+	}
+}
+
+func TestRestoreNextQueue_Invalid(t *testing.T) {
+	validRandomizer := RandomizerState{Name: RandomizerBag7}
+	tt := map[string]struct {
+		state NextQueueState
+		opts  []func(*NextQueue)
+	}{
+		"empty": {
+			state: NextQueueState{Randomizer: validRandomizer},
+			opts:  []func(*NextQueue){WithRandSource(rand.New(rand.NewPCG(0, 0)))},
+		},
+		"invalid value": {
+			state: NextQueueState{Values: "IOX", Randomizer: validRandomizer},
+			opts:  []func(*NextQueue){WithRandSource(rand.New(rand.NewPCG(0, 0)))},
+		},
+		"invalid randomizer": {
+			state: NextQueueState{Values: "IOT", Randomizer: RandomizerState{Name: "unknown"}},
+			opts:  []func(*NextQueue){WithRandSource(rand.New(rand.NewPCG(0, 0)))},
+		},
+		"no random source": {
+			state: NextQueueState{Values: "IOT", Randomizer: validRandomizer},
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			_, err := RestoreNextQueue(20, tc.state, tc.opts...)
+			assert.Error(t, err)
+		})
+	}
+}
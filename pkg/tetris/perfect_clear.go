@@ -0,0 +1,35 @@
+package tetris
+
+// IsEmpty returns true if there are no Minos in the Matrix.
+// When this is true after removing completed lines the lock down is a Perfect Clear (see PerfectClearBonus).
+func (m *Matrix) IsEmpty() bool {
+	for _, row := range *m {
+		for _, cell := range row {
+			if !isCellEmpty(cell) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// PerfectClearBonus returns the Perfect Clear awarded in addition to a line clear Action (as returned by ApplyTSpin)
+// which left the Matrix empty. backToBack is whether the Back-to-Back bonus is active (see Scoring.IsBackToBack),
+// which awards a Tetris Perfect Clear as a Back-to-Back Tetris Perfect Clear.
+// Actions.None is returned for an Action which did not clear any lines.
+func PerfectClearBonus(a Action, backToBack bool) Action {
+	switch a.GetRowsCleared() {
+	case 1:
+		return Actions.SinglePerfectClear
+	case 2:
+		return Actions.DoublePerfectClear
+	case 3:
+		return Actions.TriplePerfectClear
+	case 4:
+		if backToBack {
+			return Actions.BackToBackTetrisPerfectClear
+		}
+		return Actions.TetrisPerfectClear
+	}
+	return Actions.None
+}
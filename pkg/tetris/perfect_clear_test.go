@@ -0,0 +1,61 @@
+package tetris
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatrix_IsEmpty(t *testing.T) {
+	tt := map[string]struct {
+		matrix Matrix
+		want   bool
+	}{
+		"empty": {
+			matrix: Matrix{{0, 0}, {0, 0}},
+			want:   true,
+		},
+		"ghost only": {
+			matrix: Matrix{{0, 0}, {'G', 'G'}},
+			want:   true,
+		},
+		"mino": {
+			matrix: Matrix{{0, 0}, {0, 'T'}},
+			want:   false,
+		},
+		"garbage": {
+			matrix: Matrix{{0, 0}, {GarbageCell, 0}},
+			want:   false,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.matrix.IsEmpty())
+		})
+	}
+}
+
+func TestPerfectClearBonus(t *testing.T) {
+	tt := map[string]struct {
+		a          Action
+		backToBack bool
+		want       Action
+	}{
+		"none":                 {a: Actions.None, want: Actions.None},
+		"t-spin without lines": {a: Actions.TSpin, want: Actions.None},
+		"single":               {a: Actions.Single, want: Actions.SinglePerfectClear},
+		"double":               {a: Actions.Double, want: Actions.DoublePerfectClear},
+		"t-spin double":        {a: Actions.TSpinDouble, want: Actions.DoublePerfectClear},
+		"triple":               {a: Actions.Triple, want: Actions.TriplePerfectClear},
+		"tetris":               {a: Actions.Tetris, want: Actions.TetrisPerfectClear},
+		"tetris back-to-back":  {a: Actions.Tetris, backToBack: true, want: Actions.BackToBackTetrisPerfectClear},
+		"single back-to-back":  {a: Actions.Single, backToBack: true, want: Actions.SinglePerfectClear},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, PerfectClearBonus(tc.a, tc.backToBack))
+		})
+	}
+}
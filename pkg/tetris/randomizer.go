@@ -0,0 +1,317 @@
+package tetris
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// A Randomizer decides the order in which Tetriminos are added to the NextQueue.
+type Randomizer interface {
+	// Next returns the Value of the next Tetrimino, using r as the source of randomness.
+	Next(r *rand.Rand) byte
+	// String returns the name of the Randomizer, as accepted by NewRandomizer.
+	String() string
+}
+
+const (
+	RandomizerBag7    = "7-bag"
+	RandomizerBag14   = "14-bag"
+	RandomizerRandom  = "random"
+	RandomizerClassic = "classic"
+	RandomizerTGM1    = "tgm1"
+	RandomizerTGM2    = "tgm2"
+
+	randomizerBagSuffix      = "-bag"
+	randomizerSequencePrefix = "sequence:"
+)
+
+// NewRandomizer creates the Randomizer with the given name. Valid names are:
+//   - "7-bag": each of the 7 Tetriminos once per shuffled bag.
+//   - "14-bag": each of the 7 Tetriminos twice per shuffled bag. Any multiple of 7 is accepted.
+//   - "random": each Tetrimino is chosen independently at random.
+//   - "classic": an NES-style randomizer which rerolls once if the previous Tetrimino is chosen.
+//   - "tgm1": a history of 4 with up to 4 rolls.
+//   - "tgm2": a history of 4 with up to 6 rolls.
+//   - "sequence:<values>": a fixed, repeating sequence (eg. "sequence:IOTSZJL").
+func NewRandomizer(name string) (Randomizer, error) {
+	switch name {
+	case RandomizerRandom:
+		return &PureRandomizer{}, nil
+	case RandomizerClassic:
+		return &ClassicRandomizer{}, nil
+	case RandomizerTGM1:
+		return NewHistoryRandomizer(4), nil
+	case RandomizerTGM2:
+		return NewHistoryRandomizer(6), nil
+	}
+
+	if values, ok := strings.CutPrefix(name, randomizerSequencePrefix); ok {
+		return NewSequenceRandomizer([]byte(values))
+	}
+	if size, ok := strings.CutSuffix(name, randomizerBagSuffix); ok {
+		n, err := strconv.Atoi(size)
+		if err == nil && n > 0 && n%len(randomizerValues) == 0 {
+			return NewBagRandomizer(n / len(randomizerValues)), nil
+		}
+	}
+	return nil, fmt.Errorf("invalid randomizer %q", name)
+}
+
+// randomizerValues are the Values of all valid Tetriminos, sorted to ensure determinism.
+// This must not be modified.
+var randomizerValues = validTetriminoValues()
+
+func validTetriminoValues() []byte {
+	tetriminos := GetValidTetriminos()
+	values := make([]byte, len(tetriminos))
+	for i := range tetriminos {
+		values[i] = tetriminos[i].Value
+	}
+	return values
+}
+
+// BagRandomizer deals Tetriminos from a shuffled bag containing each Tetrimino a fixed number of times.
+// Once the bag is empty it is refilled and shuffled.
+type BagRandomizer struct {
+	copies int
+	bag    []byte
+}
+
+// NewBagRandomizer creates a BagRandomizer where the bag contains the given number of copies of each Tetrimino.
+func NewBagRandomizer(copies int) *BagRandomizer {
+	return &BagRandomizer{copies: max(copies, 1)}
+}
+
+func (b *BagRandomizer) Next(r *rand.Rand) byte {
+	if len(b.bag) == 0 {
+		values := randomizerValues
+		pool := make([]byte, 0, len(values)*b.copies)
+		for range b.copies {
+			pool = append(pool, values...)
+		}
+		for _, i := range r.Perm(len(pool)) {
+			b.bag = append(b.bag, pool[i])
+		}
+	}
+
+	v := b.bag[0]
+	b.bag = b.bag[1:]
+	return v
+}
+
+func (b *BagRandomizer) String() string {
+	return strconv.Itoa(b.copies*len(randomizerValues)) + randomizerBagSuffix
+}
+
+// PureRandomizer chooses each Tetrimino independently with equal probability.
+type PureRandomizer struct{}
+
+func (*PureRandomizer) Next(r *rand.Rand) byte {
+	values := randomizerValues
+	return values[r.IntN(len(values))]
+}
+
+func (*PureRandomizer) String() string {
+	return RandomizerRandom
+}
+
+// ClassicRandomizer is the NES-style randomizer. It rolls an 8 sided die where the eighth side
+// (or the previous Tetrimino) causes a single reroll of a 7 sided die.
+type ClassicRandomizer struct {
+	previous byte
+}
+
+func (c *ClassicRandomizer) Next(r *rand.Rand) byte {
+	values := randomizerValues
+	i := r.IntN(len(values) + 1)
+	if i == len(values) || values[i] == c.previous {
+		i = r.IntN(len(values))
+	}
+
+	c.previous = values[i]
+	return c.previous
+}
+
+func (*ClassicRandomizer) String() string {
+	return RandomizerClassic
+}
+
+// HistoryRandomizer is the randomizer used by The Grand Master series. It remembers the last 4 Tetriminos
+// and rolls again, up to a maximum number of rolls, if the chosen Tetrimino is in the history.
+// The first Tetrimino is never an S, Z or O.
+type HistoryRandomizer struct {
+	rolls   int
+	history []byte
+	isFirst bool
+}
+
+// NewHistoryRandomizer creates a HistoryRandomizer with the given maximum number of rolls.
+// A value of 4 behaves like TGM, starting with a history of ZZZZ, and a value of 6 behaves like TGM2,
+// starting with a history of ZSSZ.
+func NewHistoryRandomizer(rolls int) *HistoryRandomizer {
+	history := []byte{'Z', 'Z', 'Z', 'Z'}
+	if rolls > 4 {
+		history = []byte{'Z', 'S', 'S', 'Z'}
+	}
+	return &HistoryRandomizer{
+		rolls:   max(rolls, 1),
+		history: history,
+		isFirst: true,
+	}
+}
+
+func (h *HistoryRandomizer) Next(r *rand.Rand) byte {
+	values := randomizerValues
+
+	var v byte
+	if h.isFirst {
+		h.isFirst = false
+		first := []byte{'I', 'J', 'L', 'T'}
+		v = first[r.IntN(len(first))]
+	} else {
+		for range h.rolls {
+			v = values[r.IntN(len(values))]
+			if !slices.Contains(h.history, v) {
+				break
+			}
+		}
+	}
+
+	h.history = append(h.history[1:], v)
+	return v
+}
+
+func (h *HistoryRandomizer) String() string {
+	if h.rolls > 4 {
+		return RandomizerTGM2
+	}
+	return RandomizerTGM1
+}
+
+// SequenceRandomizer repeats a fixed sequence of Tetriminos. This is useful for puzzles and tests.
+type SequenceRandomizer struct {
+	values []byte
+	next   int
+}
+
+// NewSequenceRandomizer creates a SequenceRandomizer which repeats the given Tetrimino Values.
+func NewSequenceRandomizer(values []byte) (*SequenceRandomizer, error) {
+	if len(values) == 0 {
+		return nil, errors.New("sequence randomizer requires at least one value")
+	}
+	for _, v := range values {
+		if _, err := GetTetrimino(v); err != nil {
+			return nil, fmt.Errorf("invalid tetrimino %q in sequence: %w", v, err)
+		}
+	}
+	return &SequenceRandomizer{values: values}, nil
+}
+
+func (s *SequenceRandomizer) Next(*rand.Rand) byte {
+	v := s.values[s.next]
+	s.next = (s.next + 1) % len(s.values)
+	return v
+}
+
+func (s *SequenceRandomizer) String() string {
+	return randomizerSequencePrefix + string(s.values)
+}
+
+// RandomizerState is the serializable state of one of the Randomizers in this package.
+type RandomizerState struct {
+	// Name is the name of the Randomizer, as accepted by NewRandomizer.
+	Name string `json:"name"`
+	// Bag is the Values left in the bag of a BagRandomizer.
+	Bag string `json:"bag,omitempty"`
+	// Previous is the Value last chosen by a ClassicRandomizer.
+	Previous string `json:"previous,omitempty"`
+	// History is the Values remembered by a HistoryRandomizer, and IsFirst is whether it has chosen a Value yet.
+	History string `json:"history,omitempty"`
+	IsFirst bool   `json:"is_first,omitempty"`
+	// Next is the index of the next Value in the sequence of a SequenceRandomizer.
+	Next int `json:"next,omitempty"`
+}
+
+// SaveRandomizer returns the state of the given Randomizer. Only the Randomizers in this package are supported.
+func SaveRandomizer(r Randomizer) (RandomizerState, error) {
+	state := RandomizerState{Name: r.String()}
+
+	switch r := r.(type) {
+	case *BagRandomizer:
+		state.Bag = string(r.bag)
+	case *PureRandomizer:
+	case *ClassicRandomizer:
+		if r.previous != 0 {
+			state.Previous = string(r.previous)
+		}
+	case *HistoryRandomizer:
+		state.History = string(r.history)
+		state.IsFirst = r.isFirst
+	case *SequenceRandomizer:
+		state.Next = r.next
+	default:
+		return RandomizerState{}, fmt.Errorf("unsupported randomizer %T", r)
+	}
+	return state, nil
+}
+
+// RestoreRandomizer creates a Randomizer from a state returned by SaveRandomizer.
+func RestoreRandomizer(state RandomizerState) (Randomizer, error) {
+	randomizer, err := NewRandomizer(state.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	switch r := randomizer.(type) {
+	case *BagRandomizer:
+		if len(state.Bag) > r.copies*len(randomizerValues) {
+			return nil, fmt.Errorf("bag has %d values, which is more than %s holds", len(state.Bag), state.Name)
+		}
+		if err = validateValues(state.Bag); err != nil {
+			return nil, fmt.Errorf("invalid bag: %w", err)
+		}
+		r.bag = []byte(state.Bag)
+	case *PureRandomizer:
+	case *ClassicRandomizer:
+		if len(state.Previous) > 1 {
+			return nil, fmt.Errorf("invalid previous value %q", state.Previous)
+		}
+		if err = validateValues(state.Previous); err != nil {
+			return nil, fmt.Errorf("invalid previous value: %w", err)
+		}
+		if state.Previous != "" {
+			r.previous = state.Previous[0]
+		}
+	case *HistoryRandomizer:
+		if len(state.History) != len(r.history) {
+			return nil, fmt.Errorf("history has %d values, expected %d", len(state.History), len(r.history))
+		}
+		if err = validateValues(state.History); err != nil {
+			return nil, fmt.Errorf("invalid history: %w", err)
+		}
+		r.history = []byte(state.History)
+		r.isFirst = state.IsFirst
+	case *SequenceRandomizer:
+		if state.Next < 0 || state.Next >= len(r.values) {
+			return nil, fmt.Errorf("sequence index %d is out of range", state.Next)
+		}
+		r.next = state.Next
+	default:
+		return nil, fmt.Errorf("unsupported randomizer %T", r)
+	}
+	return randomizer, nil
+}
+
+// validateValues returns an error if any of the given values is not a valid Tetrimino Value.
+func validateValues(values string) error {
+	for i := range len(values) {
+		if _, err := GetTetrimino(values[i]); err != nil {
+			return fmt.Errorf("invalid tetrimino %q: %w", values[i], err)
+		}
+	}
+	return nil
+}
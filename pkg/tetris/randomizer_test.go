@@ -0,0 +1,252 @@
+package tetris
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const randomizerSamples = 70_000
+
+func drawValues(t *testing.T, name string, n int) []byte {
+	t.Helper()
+
+	r, err := NewRandomizer(name)
+	require.NoError(t, err)
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	values := make([]byte, n)
+	for i := range values {
+		values[i] = r.Next(rng)
+	}
+	return values
+}
+
+// assertUniform checks that each Tetrimino occurs with a frequency within the tolerance of 1/7.
+func assertUniform(t *testing.T, values []byte, tolerance float64) {
+	t.Helper()
+
+	counts := make(map[byte]int)
+	for _, v := range values {
+		counts[v]++
+	}
+	require.Len(t, counts, 7)
+	for v, count := range counts {
+		assert.InDeltaf(t, 1.0/7, float64(count)/float64(len(values)), tolerance, "frequency of %q", v)
+	}
+}
+
+// repeatRate returns the proportion of Tetriminos which are the same as the previous Tetrimino.
+func repeatRate(values []byte) float64 {
+	repeats := 0
+	for i := 1; i < len(values); i++ {
+		if values[i] == values[i-1] {
+			repeats++
+		}
+	}
+	return float64(repeats) / float64(len(values)-1)
+}
+
+func TestNewRandomizer(t *testing.T) {
+	tt := map[string]struct {
+		name    string
+		wantErr bool
+	}{
+		"7-bag":              {name: "7-bag"},
+		"14-bag":             {name: "14-bag"},
+		"35-bag":             {name: "35-bag"},
+		"random":             {name: "random"},
+		"classic":            {name: "classic"},
+		"tgm1":               {name: "tgm1"},
+		"tgm2":               {name: "tgm2"},
+		"sequence":           {name: "sequence:IOTSZJL"},
+		"empty":              {name: "", wantErr: true},
+		"unknown":            {name: "fair", wantErr: true},
+		"bag not multiple":   {name: "10-bag", wantErr: true},
+		"sequence empty":     {name: "sequence:", wantErr: true},
+		"sequence has X":     {name: "sequence:IOX", wantErr: true},
+		"sequence lowercase": {name: "sequence:iot", wantErr: true},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			r, err := NewRandomizer(tc.name)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.name, r.String())
+		})
+	}
+}
+
+func TestBagRandomizer(t *testing.T) {
+	tt := map[string]struct {
+		name   string
+		size   int
+		maxGap int
+	}{
+		"7-bag":  {name: RandomizerBag7, size: 7, maxGap: 12},
+		"14-bag": {name: RandomizerBag14, size: 14, maxGap: 26},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			values := drawValues(t, tc.name, tc.size*1000)
+
+			// Each bag contains every Tetrimino the same number of times.
+			for start := 0; start < len(values); start += tc.size {
+				counts := make(map[byte]int)
+				for _, v := range values[start : start+tc.size] {
+					counts[v]++
+				}
+				require.Len(t, counts, 7)
+				for _, count := range counts {
+					require.Equal(t, tc.size/7, count)
+				}
+			}
+
+			// The gap between occurrences of the same Tetrimino is bounded.
+			last := make(map[byte]int)
+			for i, v := range values {
+				if prev, ok := last[v]; ok {
+					require.LessOrEqual(t, i-prev-1, tc.maxGap)
+				}
+				last[v] = i
+			}
+		})
+	}
+}
+
+func TestBagRandomizer_MatchesPreviousShuffle(t *testing.T) {
+	// The 7-bag must produce the same order as shuffling GetValidTetriminos so that seeds are stable.
+	values := drawValues(t, RandomizerBag7, 14)
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	tetriminos := GetValidTetriminos()
+	var want []byte
+	for range 2 {
+		for _, i := range rng.Perm(len(tetriminos)) {
+			want = append(want, tetriminos[i].Value)
+		}
+	}
+	assert.Equal(t, want, values)
+}
+
+func TestPureRandomizer(t *testing.T) {
+	values := drawValues(t, RandomizerRandom, randomizerSamples)
+
+	assertUniform(t, values, 0.005)
+	assert.InDelta(t, 1.0/7, repeatRate(values), 0.01)
+}
+
+func TestClassicRandomizer(t *testing.T) {
+	values := drawValues(t, RandomizerClassic, randomizerSamples)
+
+	assertUniform(t, values, 0.01)
+	// A repeat requires the first roll to be the previous Tetrimino or the reroll side (2/8),
+	// then the reroll to be the previous Tetrimino (1/7).
+	assert.InDelta(t, 2.0/56, repeatRate(values), 0.005)
+}
+
+func TestHistoryRandomizer(t *testing.T) {
+	tt := map[string]struct {
+		name          string
+		maxRepeatRate float64
+	}{
+		"tgm1": {name: RandomizerTGM1, maxRepeatRate: 0.03},
+		"tgm2": {name: RandomizerTGM2, maxRepeatRate: 0.01},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			for seed := range uint64(100) {
+				r, err := NewRandomizer(tc.name)
+				require.NoError(t, err)
+				first := r.Next(rand.New(rand.NewPCG(seed, seed)))
+				require.NotContains(t, []byte{'S', 'Z', 'O'}, first)
+			}
+
+			values := drawValues(t, tc.name, randomizerSamples)
+			assertUniform(t, values, 0.01)
+			assert.Less(t, repeatRate(values), tc.maxRepeatRate)
+		})
+	}
+}
+
+func TestSequenceRandomizer(t *testing.T) {
+	values := drawValues(t, "sequence:TSZ", 7)
+	assert.Equal(t, []byte("TSZTSZT"), values)
+}
+
+func TestNextQueue_WithRandomizer(t *testing.T) {
+	r, err := NewSequenceRandomizer([]byte("IO"))
+	require.NoError(t, err)
+
+	nq := NewNextQueue(20, WithRandomizer(r))
+	for i := range 20 {
+		want := byte('I')
+		if i%2 == 1 {
+			want = 'O'
+		}
+		assert.Equal(t, want, nq.Next().Value)
+	}
+}
+
+func TestRestoreRandomizer(t *testing.T) {
+	tt := map[string]struct {
+		name string
+	}{
+		"7-bag":    {name: RandomizerBag7},
+		"14-bag":   {name: RandomizerBag14},
+		"random":   {name: RandomizerRandom},
+		"classic":  {name: RandomizerClassic},
+		"tgm1":     {name: RandomizerTGM1},
+		"tgm2":     {name: RandomizerTGM2},
+		"sequence": {name: "sequence:TSZ"},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			r, err := NewRandomizer(tc.name)
+			require.NoError(t, err)
+			rng := rand.New(rand.NewPCG(1, 2))
+			for range 5 {
+				r.Next(rng)
+			}
+
+			state, err := SaveRandomizer(r)
+			require.NoError(t, err)
+			restored, err := RestoreRandomizer(state)
+			require.NoError(t, err)
+
+			want, got := rand.New(rand.NewPCG(3, 4)), rand.New(rand.NewPCG(3, 4))
+			for i := range 20 {
+				assert.Equal(t, r.Next(want), restored.Next(got), "value %d", i)
+			}
+		})
+	}
+}
+
+func TestRestoreRandomizer_Invalid(t *testing.T) {
+	tt := map[string]struct {
+		state RandomizerState
+	}{
+		"unknown name":          {state: RandomizerState{Name: "unknown"}},
+		"invalid bag value":     {state: RandomizerState{Name: RandomizerBag7, Bag: "IX"}},
+		"bag too large":         {state: RandomizerState{Name: RandomizerBag7, Bag: "IOTSZJLI"}},
+		"invalid previous":      {state: RandomizerState{Name: RandomizerClassic, Previous: "IO"}},
+		"short history":         {state: RandomizerState{Name: RandomizerTGM1, History: "ZZZ"}},
+		"sequence out of range": {state: RandomizerState{Name: "sequence:TSZ", Next: 3}},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			_, err := RestoreRandomizer(tc.state)
+			assert.Error(t, err)
+		})
+	}
+}
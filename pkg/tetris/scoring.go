@@ -19,6 +19,8 @@ type Scoring struct {
 
 	total      int
 	backToBack bool
+	chain      int // The number of consecutive lock downs which cleared lines
+	maxCombo   int
 }
 
 // NewScoring creates a new scoring system.
@@ -70,6 +72,22 @@ func (s *Scoring) Lines() int {
 	return s.lines
 }
 
+// IsBackToBack returns true if the next difficult line clear will be awarded the Back-to-Back bonus.
+func (s *Scoring) IsBackToBack() bool {
+	return s.backToBack
+}
+
+// Combo returns the number of consecutive lock downs which cleared lines, minus one.
+// A value of -1 means there is no combo.
+func (s *Scoring) Combo() int {
+	return s.chain - 1
+}
+
+// MaxCombo returns the highest combo reached.
+func (s *Scoring) MaxCombo() int {
+	return s.maxCombo
+}
+
 // AddSoftDrop adds points for a soft drop.
 func (s *Scoring) AddSoftDrop(lines int) {
 	s.total += lines
@@ -83,20 +101,44 @@ func (s *Scoring) AddHardDrop(lines int) {
 // ProcessAction processes an action and updates the score, lines cleared, level, etc.
 // The returned boolean indicates if the game should end.
 func (s *Scoring) ProcessAction(a Action) (bool, error) {
+	return s.processAction(a, false)
+}
+
+// ProcessPerfectClear processes a line clear Action which left the Matrix empty in the same way as ProcessAction,
+// and also awards the Perfect Clear bonus (see PerfectClearBonus). A Perfect Clear never breaks a Back-to-Back
+// sequence. The returned boolean indicates if the game should end.
+func (s *Scoring) ProcessPerfectClear(a Action) (bool, error) {
+	return s.processAction(a, true)
+}
+
+func (s *Scoring) processAction(a Action, perfectClear bool) (bool, error) {
+	if a.GetRowsCleared() > 0 {
+		s.chain++
+		s.maxCombo = max(s.maxCombo, s.Combo())
+	} else {
+		s.chain = 0
+	}
+
 	if a == Actions.None {
 		return false, nil
 	}
 
+	bonus := Actions.None
+	if perfectClear {
+		bonus = PerfectClearBonus(a, s.backToBack)
+	}
 	points := float64(a.GetPoints())
 
 	var err error
 	var result bool
-	backToBack := 0.0
+	backToBack := 1.0
 	if result, err = a.EndsBackToBack(); result {
-		s.backToBack = false
+		if !perfectClear {
+			s.backToBack = false
+		}
 	} else if result, err = a.StartsBackToBack(); result {
 		if s.backToBack {
-			backToBack = points * 0.5
+			backToBack = 1.5
 		}
 		s.backToBack = true
 	}
@@ -104,8 +146,10 @@ func (s *Scoring) ProcessAction(a Action) (bool, error) {
 		return false, err
 	}
 
-	s.total += int(points+backToBack) * s.level
-	s.lines += int((points + backToBack) / 100)
+	// The goal lines are those of the line clear, excluding the Perfect Clear bonus.
+	points *= backToBack
+	s.total += (int(points)+bonus.GetPoints())*s.level + s.comboBonus()
+	s.lines += int(points / 100)
 
 	// if max lines enabled, and max lines reached
 	if s.maxLines > 0 && s.lines >= s.maxLines {
@@ -135,3 +179,66 @@ func (s *Scoring) ProcessAction(a Action) (bool, error) {
 
 	return false, nil
 }
+
+// comboBonus returns the points awarded for the current combo.
+func (s *Scoring) comboBonus() int {
+	return 50 * max(s.Combo(), 0) * s.level
+}
+
+// ScoringState is the serializable state of a Scoring system.
+type ScoringState struct {
+	Level         int  `json:"level"`
+	MaxLevel      int  `json:"max_level"`
+	IncreaseLevel bool `json:"increase_level"`
+	EndOnMaxLevel bool `json:"end_on_max_level"`
+
+	Lines         int  `json:"lines"`
+	MaxLines      int  `json:"max_lines"`
+	EndOnMaxLines bool `json:"end_on_max_lines"`
+
+	Total      int  `json:"total"`
+	BackToBack bool `json:"back_to_back"`
+	Chain      int  `json:"chain"`
+	MaxCombo   int  `json:"max_combo"`
+}
+
+// State returns the state of the Scoring system.
+func (s *Scoring) State() ScoringState {
+	return ScoringState{
+		Level:         s.level,
+		MaxLevel:      s.maxLevel,
+		IncreaseLevel: s.increaseLevel,
+		EndOnMaxLevel: s.endOnMaxLevel,
+		Lines:         s.lines,
+		MaxLines:      s.maxLines,
+		EndOnMaxLines: s.endOnMaxLines,
+		Total:         s.total,
+		BackToBack:    s.backToBack,
+		Chain:         s.chain,
+		MaxCombo:      s.maxCombo,
+	}
+}
+
+// RestoreScoring recreates a Scoring system from a state returned by State.
+func RestoreScoring(state ScoringState) (*Scoring, error) {
+	s := &Scoring{
+		level:         state.Level,
+		maxLevel:      state.MaxLevel,
+		increaseLevel: state.IncreaseLevel,
+		endOnMaxLevel: state.EndOnMaxLevel,
+		lines:         state.Lines,
+		maxLines:      state.MaxLines,
+		endOnMaxLines: state.EndOnMaxLines,
+		total:         state.Total,
+		backToBack:    state.BackToBack,
+		chain:         state.Chain,
+		maxCombo:      state.MaxCombo,
+	}
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	if s.lines < 0 || s.chain < 0 || s.maxCombo < 0 {
+		return nil, fmt.Errorf("invalid lines '%d', chain '%d' or max combo '%d'", s.lines, s.chain, s.maxCombo)
+	}
+	return s, nil
+}
@@ -410,3 +410,169 @@ func TestScoring_ProcessAction(t *testing.T) {
 		})
 	}
 }
+
+func TestScoring_Combo(t *testing.T) {
+	tt := map[string]struct {
+		actions          []Action
+		level            int
+		expectedTotal    int
+		expectedCombo    int
+		expectedMaxCombo int
+	}{
+		"no actions": {
+			actions:          nil,
+			level:            1,
+			expectedTotal:    0,
+			expectedCombo:    -1,
+			expectedMaxCombo: 0,
+		},
+		"single clear": {
+			actions:          []Action{Actions.Single},
+			level:            1,
+			expectedTotal:    100,
+			expectedCombo:    0,
+			expectedMaxCombo: 0,
+		},
+		"three consecutive clears": {
+			actions:          []Action{Actions.Single, Actions.Single, Actions.Double},
+			level:            1,
+			expectedTotal:    100 + (100 + 50) + (300 + 100),
+			expectedCombo:    2,
+			expectedMaxCombo: 2,
+		},
+		"bonus is multiplied by level": {
+			actions:          []Action{Actions.Single, Actions.Single},
+			level:            3,
+			expectedTotal:    100*3 + (100*3 + 50*3),
+			expectedCombo:    1,
+			expectedMaxCombo: 1,
+		},
+		"reset by lock without clear": {
+			actions:          []Action{Actions.Single, Actions.Single, Actions.None, Actions.Single},
+			level:            1,
+			expectedTotal:    100 + (100 + 50) + 100,
+			expectedCombo:    0,
+			expectedMaxCombo: 1,
+		},
+		"reset by T-spin without clear": {
+			actions:          []Action{Actions.Single, Actions.Single, Actions.TSpin},
+			level:            1,
+			expectedTotal:    100 + (100 + 50) + 400,
+			expectedCombo:    -1,
+			expectedMaxCombo: 1,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			s, err := NewScoring(tc.level, 0, false, false, 0, false)
+			require.NoError(t, err)
+
+			for _, a := range tc.actions {
+				_, err = s.ProcessAction(a)
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, tc.expectedTotal, s.Total())
+			assert.Equal(t, tc.expectedCombo, s.Combo())
+			assert.Equal(t, tc.expectedMaxCombo, s.MaxCombo())
+		})
+	}
+}
+
+func TestScoring_ProcessPerfectClear(t *testing.T) {
+	tt := map[string]struct {
+		a                  Action
+		isBackToBack       bool
+		expectedTotal      int
+		expectedLines      int
+		expectedBackToBack bool
+	}{
+		"single": {
+			a:                  Actions.Single,
+			expectedTotal:      100 + 800,
+			expectedLines:      1,
+			expectedBackToBack: false,
+		},
+		"double": {
+			a:                  Actions.Double,
+			expectedTotal:      300 + 1200,
+			expectedLines:      3,
+			expectedBackToBack: false,
+		},
+		"triple": {
+			a:                  Actions.Triple,
+			expectedTotal:      500 + 1800,
+			expectedLines:      5,
+			expectedBackToBack: false,
+		},
+		"tetris": {
+			a:                  Actions.Tetris,
+			expectedTotal:      800 + 2000,
+			expectedLines:      8,
+			expectedBackToBack: true,
+		},
+		"back-to-back tetris": {
+			a:                  Actions.Tetris,
+			isBackToBack:       true,
+			expectedTotal:      1200 + 3200,
+			expectedLines:      12,
+			expectedBackToBack: true,
+		},
+		"single keeps back-to-back": {
+			a:                  Actions.Single,
+			isBackToBack:       true,
+			expectedTotal:      100 + 800,
+			expectedLines:      1,
+			expectedBackToBack: true,
+		},
+		"t-spin double": {
+			a:                  Actions.TSpinDouble,
+			expectedTotal:      1200 + 1200,
+			expectedLines:      12,
+			expectedBackToBack: true,
+		},
+		"back-to-back t-spin double": {
+			a:                  Actions.TSpinDouble,
+			isBackToBack:       true,
+			expectedTotal:      1800 + 1200,
+			expectedLines:      18,
+			expectedBackToBack: true,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			s := &Scoring{
+				level:      1,
+				backToBack: tc.isBackToBack,
+			}
+
+			_, err := s.ProcessPerfectClear(tc.a)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expectedTotal, s.total)
+			assert.Equal(t, tc.expectedLines, s.lines)
+			assert.Equal(t, tc.expectedBackToBack, s.backToBack)
+		})
+	}
+}
+
+func TestRestoreScoring(t *testing.T) {
+	s, err := NewScoring(3, 15, true, false, 150, true)
+	require.NoError(t, err)
+	s.AddHardDrop(10)
+	for _, a := range []Action{Actions.Tetris, Actions.Single, Actions.Double} {
+		_, err = s.ProcessAction(a)
+		require.NoError(t, err)
+	}
+
+	restored, err := RestoreScoring(s.State())
+	require.NoError(t, err)
+	assert.Equal(t, s, restored)
+
+	state := s.State()
+	state.Level = 0
+	_, err = RestoreScoring(state)
+	assert.Error(t, err)
+}
@@ -106,15 +106,31 @@ var RotationCompasses = map[byte]RotationCompass{
 	},
 }
 
-// startingPositions defines the initial spawn position for each Tetrimino type when it enters the game matrix.
-// This position is relative to the matrix buffer zone, meaning the negative Y coordinates account for
-// the pieces spawning partially above the visible playfield.
+// startingPositions defines the initial spawn position for each Tetrimino type when it enters a Matrix
+// of DefaultMatrixWidth. This position is relative to the matrix buffer zone, meaning the negative Y coordinates
+// account for the pieces spawning partially above the visible playfield.
+// For other widths the X coordinate is computed by startingPosition.
 var startingPositions = map[byte]Coordinate{
 	'I': {X: 3, Y: -1},
 	'O': {X: 4, Y: -2},
 	'6': {X: 3, Y: -2},
 }
 
+// startingPosition returns the spawn position of the given Tetrimino in a Matrix of the given width.
+// The Tetrimino is horizontally centered, rounding to the left, which matches startingPositions for
+// a Matrix of DefaultMatrixWidth.
+func startingPosition(tet *Tetrimino, width int) Coordinate {
+	key := tet.Value
+	if key != 'I' && key != 'O' {
+		key = '6'
+	}
+	pos := startingPositions[key]
+	if len(tet.Cells) > 0 {
+		pos.X = (width - len(tet.Cells[0])) / 2
+	}
+	return pos
+}
+
 func getMapOfValidTetriminos() map[byte]Tetrimino {
 	return map[byte]Tetrimino{
 		'I': {
@@ -289,9 +305,17 @@ func (t *Tetrimino) MoveRight(matrix Matrix) bool {
 // This will automatically use Super Rotation System (SRS).
 // If no valid rotation is found, the Tetrimino will not be modified and an error will be returned.
 func (t *Tetrimino) Rotate(matrix Matrix, clockwise bool) error {
+	_, err := t.RotateWithPoint(matrix, clockwise)
+	return err
+}
+
+// RotateWithPoint behaves the same as Rotate but also returns the SRS rotation point (1-5) that was used.
+// The rotation point is the index (plus one) of the offset in the RotationCompass that produced a valid position.
+// If the Tetrimino was not rotated (eg. it is an O Tetrimino or no valid rotation was found) 0 is returned.
+func (t *Tetrimino) RotateWithPoint(matrix Matrix, clockwise bool) (int, error) {
 	if t.Value == 'O' {
 		// O Tetrimino does not rotate.
-		return nil
+		return 0, nil
 	}
 
 	rotated := t.DeepCopy()
@@ -303,18 +327,18 @@ func (t *Tetrimino) Rotate(matrix Matrix, clockwise bool) error {
 		rotationPoint, err = rotated.rotateCounterClockwise(matrix)
 	}
 	if err != nil {
-		return fmt.Errorf("failed to rotate tetrimino: %w", err)
+		return 0, fmt.Errorf("failed to rotate tetrimino: %w", err)
 	}
 
 	foundValid := rotationPoint != invalidRotationPoint
 	if !foundValid {
-		return nil
+		return 0, nil
 	}
 
 	t.Position = rotated.Position
 	t.Cells = rotated.Cells
 	t.CompassDirection = rotated.CompassDirection
-	return nil
+	return rotationPoint, nil
 }
 
 // rotateClockwise rotates the Tetrimino clockwise.
@@ -0,0 +1,111 @@
+package tetris
+
+// TSpin describes the kind of T-Spin (if any) that was performed when a Tetrimino locked down.
+type TSpin int
+
+const (
+	TSpinNone TSpin = iota
+	TSpinMini
+	TSpinFull
+)
+
+// tSpinFinalRotationPoint is the SRS rotation point which upgrades a Mini T-Spin to a full T-Spin.
+const tSpinFinalRotationPoint = 5
+
+// tSpinCenters maps each compass direction to the offset of the T Tetrimino's center Mino from its Position.
+var tSpinCenters = [4]Coordinate{
+	{X: 1, Y: 1}, // North
+	{X: 0, Y: 1}, // East
+	{X: 1, Y: 0}, // South
+	{X: 1, Y: 1}, // West
+}
+
+// tSpinFacing maps each compass direction to the unit vector the flat side of the T Tetrimino points towards.
+var tSpinFacing = [4]Coordinate{
+	{X: 0, Y: -1}, // North
+	{X: 1, Y: 0},  // East
+	{X: 0, Y: 1},  // South
+	{X: -1, Y: 0}, // West
+}
+
+// DetectTSpin uses the 3-corner rule to determine whether the given Tetrimino performed a T-Spin.
+// The rotationPoint is the SRS rotation point (see Tetrimino.RotateWithPoint) used by the last successful
+// movement of the Tetrimino, or 0 if the last successful movement was not a rotation.
+//
+// A T-Spin requires a T Tetrimino whose last movement was a rotation, with at least 3 of the 4 corners
+// diagonal to its center occupied. Walls and the floor count as occupied. If both corners on the pointing
+// side are occupied, or the final SRS rotation point was used, it is a full T-Spin. Otherwise, it is a Mini T-Spin.
+func (m *Matrix) DetectTSpin(tet *Tetrimino, rotationPoint int) TSpin {
+	if tet.Value != 'T' || rotationPoint <= 0 {
+		return TSpinNone
+	}
+	if tet.CompassDirection < 0 || tet.CompassDirection >= len(tSpinCenters) {
+		return TSpinNone
+	}
+
+	center := tSpinCenters[tet.CompassDirection]
+	center.X += tet.Position.X
+	center.Y += tet.Position.Y
+	facing := tSpinFacing[tet.CompassDirection]
+
+	frontCount, backCount := 0, 0
+	for _, dx := range []int{-1, 1} {
+		for _, dy := range []int{-1, 1} {
+			if !m.isCornerOccupied(center.Y+dy, center.X+dx) {
+				continue
+			}
+			if dx == facing.X || dy == facing.Y {
+				frontCount++
+			} else {
+				backCount++
+			}
+		}
+	}
+
+	switch {
+	case frontCount+backCount < 3:
+		return TSpinNone
+	case frontCount == 2, rotationPoint == tSpinFinalRotationPoint:
+		return TSpinFull
+	default:
+		return TSpinMini
+	}
+}
+
+// isCornerOccupied returns true if the cell is occupied by a Mino or is outside the bounds of the Matrix.
+func (m *Matrix) isCornerOccupied(row, col int) bool {
+	if m.isOutOfBoundsHorizontally(col) || m.isOutOfBoundsVertically(row) {
+		return true
+	}
+	return !isCellEmpty((*m)[row][col])
+}
+
+// ApplyTSpin converts a line clear Action (as returned by Matrix.RemoveCompletedLines) to
+// its T-Spin equivalent. If the TSpin is TSpinNone the Action is returned unmodified.
+// A Mini T-Spin which clears two lines is awarded as a full T-Spin Double.
+func ApplyTSpin(a Action, tSpin TSpin) Action {
+	switch tSpin {
+	case TSpinMini:
+		switch a {
+		case Actions.None:
+			return Actions.MiniTSpin
+		case Actions.Single:
+			return Actions.MiniTSpinSingle
+		case Actions.Double:
+			return Actions.TSpinDouble
+		}
+	case TSpinFull:
+		switch a {
+		case Actions.None:
+			return Actions.TSpin
+		case Actions.Single:
+			return Actions.TSpinSingle
+		case Actions.Double:
+			return Actions.TSpinDouble
+		case Actions.Triple:
+			return Actions.TSpinTriple
+		}
+	case TSpinNone:
+	}
+	return a
+}
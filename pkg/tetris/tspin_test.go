@@ -0,0 +1,139 @@
+package tetris
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatrix_DetectTSpin(t *testing.T) {
+	tCells := map[int][][]bool{
+		0: {{false, true, false}, {true, true, true}},
+		1: {{true, false}, {true, true}, {true, false}},
+		2: {{true, true, true}, {false, true, false}},
+		3: {{false, true}, {true, true}, {false, true}},
+	}
+
+	tt := map[string]struct {
+		matrix        Matrix
+		value         byte
+		direction     int
+		position      Coordinate
+		rotationPoint int
+		want          TSpin
+	}{
+		"not a T": {
+			matrix:        Matrix{{'X', 0, 'X'}, {0, 0, 0}, {'X', 0, 'X'}},
+			value:         'J',
+			direction:     0,
+			position:      Coordinate{X: 0, Y: 0},
+			rotationPoint: 1,
+			want:          TSpinNone,
+		},
+		"last movement not a rotation": {
+			matrix:        Matrix{{'X', 0, 'X'}, {0, 0, 0}, {'X', 0, 'X'}},
+			value:         'T',
+			direction:     0,
+			position:      Coordinate{X: 0, Y: 0},
+			rotationPoint: 0,
+			want:          TSpinNone,
+		},
+		"two corners": {
+			matrix:        Matrix{{'X', 0, 'X'}, {0, 0, 0}, {0, 0, 0}},
+			value:         'T',
+			direction:     0,
+			position:      Coordinate{X: 0, Y: 0},
+			rotationPoint: 1,
+			want:          TSpinNone,
+		},
+		"north; both front corners": {
+			matrix:        Matrix{{'X', 0, 'X'}, {0, 0, 0}, {'X', 0, 0}},
+			value:         'T',
+			direction:     0,
+			position:      Coordinate{X: 0, Y: 0},
+			rotationPoint: 1,
+			want:          TSpinFull,
+		},
+		"north; one front corner": {
+			matrix:        Matrix{{'X', 0, 0}, {0, 0, 0}, {'X', 0, 'X'}},
+			value:         'T',
+			direction:     0,
+			position:      Coordinate{X: 0, Y: 0},
+			rotationPoint: 1,
+			want:          TSpinMini,
+		},
+		"north; one front corner; final rotation point": {
+			matrix:        Matrix{{'X', 0, 0}, {0, 0, 0}, {'X', 0, 'X'}},
+			value:         'T',
+			direction:     0,
+			position:      Coordinate{X: 0, Y: 0},
+			rotationPoint: 5,
+			want:          TSpinFull,
+		},
+		"south; both front corners": {
+			matrix:        Matrix{{0, 0, 'X'}, {0, 0, 0}, {'X', 0, 'X'}},
+			value:         'T',
+			direction:     2,
+			position:      Coordinate{X: 0, Y: 1},
+			rotationPoint: 2,
+			want:          TSpinFull,
+		},
+		"east; against wall": {
+			matrix:        Matrix{{0, 'X'}, {0, 0}, {0, 0}},
+			value:         'T',
+			direction:     1,
+			position:      Coordinate{X: 0, Y: 0},
+			rotationPoint: 1,
+			want:          TSpinMini,
+		},
+		"west; against floor": {
+			matrix:        Matrix{{'X', 0, 0}, {0, 0, 0}},
+			value:         'T',
+			direction:     3,
+			position:      Coordinate{X: 0, Y: 0},
+			rotationPoint: 3,
+			want:          TSpinFull,
+		},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			tet := &Tetrimino{
+				Value:            tc.value,
+				Cells:            tCells[tc.direction],
+				Position:         tc.position,
+				CompassDirection: tc.direction,
+				RotationCompass:  RotationCompasses['6'],
+			}
+
+			got := tc.matrix.DetectTSpin(tet, tc.rotationPoint)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestApplyTSpin(t *testing.T) {
+	tt := map[string]struct {
+		a     Action
+		tSpin TSpin
+		want  Action
+	}{
+		"none; single":      {a: Actions.Single, tSpin: TSpinNone, want: Actions.Single},
+		"none; tetris":      {a: Actions.Tetris, tSpin: TSpinNone, want: Actions.Tetris},
+		"mini; no lines":    {a: Actions.None, tSpin: TSpinMini, want: Actions.MiniTSpin},
+		"mini; single":      {a: Actions.Single, tSpin: TSpinMini, want: Actions.MiniTSpinSingle},
+		"mini; double":      {a: Actions.Double, tSpin: TSpinMini, want: Actions.TSpinDouble},
+		"full; no lines":    {a: Actions.None, tSpin: TSpinFull, want: Actions.TSpin},
+		"full; single":      {a: Actions.Single, tSpin: TSpinFull, want: Actions.TSpinSingle},
+		"full; double":      {a: Actions.Double, tSpin: TSpinFull, want: Actions.TSpinDouble},
+		"full; triple":      {a: Actions.Triple, tSpin: TSpinFull, want: Actions.TSpinTriple},
+		"full; unknown":     {a: Actions.Unknown, tSpin: TSpinFull, want: Actions.Unknown},
+		"mini; unsupported": {a: Actions.Triple, tSpin: TSpinMini, want: Actions.Triple},
+	}
+
+	for name, tc := range tt {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, ApplyTSpin(tc.a, tc.tSpin))
+		})
+	}
+}